@@ -1,9 +1,10 @@
-package botapi
+// Code generated by capnpc-go. DO NOT EDIT.
 
-// AUTO GENERATED - DO NOT EDIT
+package botapi
 
 import (
 	context "golang.org/x/net/context"
+	math "math"
 	strconv "strconv"
 	capnp "zombiezen.com/go/capnproto2"
 	text "zombiezen.com/go/capnproto2/encoding/text"
@@ -13,9 +14,12 @@ import (
 
 type AiConnector struct{ Client capnp.Client }
 
-func (c AiConnector) Connect(ctx context.Context, params func(ConnectRequest) error, opts ...capnp.CallOption) AiConnector_connect_Results_Promise {
+// AiConnector_TypeID is the unique identifier for the type AiConnector.
+const AiConnector_TypeID = 0x9804b41cc3cba212
+
+func (c AiConnector) Connect(ctx context.Context, params func(ConnectRequest) error, opts ...capnp.CallOption) ConnectResponse_Promise {
 	if c.Client == nil {
-		return AiConnector_connect_Results_Promise{Pipeline: capnp.NewPipeline(capnp.ErrorAnswer(capnp.ErrNullClient))}
+		return ConnectResponse_Promise{Pipeline: capnp.NewPipeline(capnp.ErrorAnswer(capnp.ErrNullClient))}
 	}
 	call := &capnp.Call{
 		Ctx: ctx,
@@ -28,10 +32,10 @@ func (c AiConnector) Connect(ctx context.Context, params func(ConnectRequest) er
 		Options: capnp.NewCallOptions(opts),
 	}
 	if params != nil {
-		call.ParamsSize = capnp.ObjectSize{DataSize: 0, PointerCount: 2}
+		call.ParamsSize = capnp.ObjectSize{DataSize: 8, PointerCount: 2}
 		call.ParamsFunc = func(s capnp.Struct) error { return params(ConnectRequest{Struct: s}) }
 	}
-	return AiConnector_connect_Results_Promise{Pipeline: capnp.NewPipeline(c.Client.Call(call))}
+	return ConnectResponse_Promise{Pipeline: capnp.NewPipeline(c.Client.Call(call))}
 }
 
 type AiConnector_Server interface {
@@ -56,10 +60,10 @@ func AiConnector_Methods(methods []server.Method, s AiConnector_Server) []server
 			MethodName:    "connect",
 		},
 		Impl: func(c context.Context, opts capnp.CallOptions, p, r capnp.Struct) error {
-			call := AiConnector_connect{c, opts, ConnectRequest{Struct: p}, AiConnector_connect_Results{Struct: r}}
+			call := AiConnector_connect{c, opts, ConnectRequest{Struct: p}, ConnectResponse{Struct: r}}
 			return s.Connect(call)
 		},
-		ResultsSize: capnp.ObjectSize{DataSize: 0, PointerCount: 0},
+		ResultsSize: capnp.ObjectSize{DataSize: 8, PointerCount: 0},
 	})
 
 	return methods
@@ -70,57 +74,128 @@ type AiConnector_connect struct {
 	Ctx     context.Context
 	Options capnp.CallOptions
 	Params  ConnectRequest
-	Results AiConnector_connect_Results
+	Results ConnectResponse
 }
 
-type AiConnector_connect_Results struct{ capnp.Struct }
+type ConnectResponse struct{ capnp.Struct }
 
-// AiConnector_connect_Results_TypeID is the unique identifier for the type AiConnector_connect_Results.
-const AiConnector_connect_Results_TypeID = 0xaf821edee86a29e4
+// ConnectResponse_TypeID is the unique identifier for the type ConnectResponse.
+const ConnectResponse_TypeID = 0xd1d9adc39c14c0d7
 
-func NewAiConnector_connect_Results(s *capnp.Segment) (AiConnector_connect_Results, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0})
-	return AiConnector_connect_Results{st}, err
+func NewConnectResponse(s *capnp.Segment) (ConnectResponse, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0})
+	return ConnectResponse{st}, err
 }
 
-func NewRootAiConnector_connect_Results(s *capnp.Segment) (AiConnector_connect_Results, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0})
-	return AiConnector_connect_Results{st}, err
+func NewRootConnectResponse(s *capnp.Segment) (ConnectResponse, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0})
+	return ConnectResponse{st}, err
 }
 
-func ReadRootAiConnector_connect_Results(msg *capnp.Message) (AiConnector_connect_Results, error) {
+func ReadRootConnectResponse(msg *capnp.Message) (ConnectResponse, error) {
 	root, err := msg.RootPtr()
-	return AiConnector_connect_Results{root.Struct()}, err
+	return ConnectResponse{root.Struct()}, err
 }
 
-func (s AiConnector_connect_Results) String() string {
-	str, _ := text.Marshal(0xaf821edee86a29e4, s.Struct)
+func (s ConnectResponse) String() string {
+	str, _ := text.Marshal(0xd1d9adc39c14c0d7, s.Struct)
 	return str
 }
 
-// AiConnector_connect_Results_List is a list of AiConnector_connect_Results.
-type AiConnector_connect_Results_List struct{ capnp.List }
+func (s ConnectResponse) Status() ConnectStatus {
+	return ConnectStatus(s.Struct.Uint16(0))
+}
 
-// NewAiConnector_connect_Results creates a new list of AiConnector_connect_Results.
-func NewAiConnector_connect_Results_List(s *capnp.Segment, sz int32) (AiConnector_connect_Results_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0}, sz)
-	return AiConnector_connect_Results_List{l}, err
+func (s ConnectResponse) SetStatus(v ConnectStatus) {
+	s.Struct.SetUint16(0, uint16(v))
 }
 
-func (s AiConnector_connect_Results_List) At(i int) AiConnector_connect_Results {
-	return AiConnector_connect_Results{s.List.Struct(i)}
+// ConnectResponse_List is a list of ConnectResponse.
+type ConnectResponse_List struct{ capnp.List }
+
+// NewConnectResponse creates a new list of ConnectResponse.
+func NewConnectResponse_List(s *capnp.Segment, sz int32) (ConnectResponse_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 0}, sz)
+	return ConnectResponse_List{l}, err
 }
 
-func (s AiConnector_connect_Results_List) Set(i int, v AiConnector_connect_Results) error {
+func (s ConnectResponse_List) At(i int) ConnectResponse { return ConnectResponse{s.List.Struct(i)} }
+
+func (s ConnectResponse_List) Set(i int, v ConnectResponse) error {
 	return s.List.SetStruct(i, v.Struct)
 }
 
-// AiConnector_connect_Results_Promise is a wrapper for a AiConnector_connect_Results promised by a client call.
-type AiConnector_connect_Results_Promise struct{ *capnp.Pipeline }
+func (s ConnectResponse_List) String() string {
+	str, _ := text.MarshalList(0xd1d9adc39c14c0d7, s.List)
+	return str
+}
+
+// ConnectResponse_Promise is a wrapper for a ConnectResponse promised by a client call.
+type ConnectResponse_Promise struct{ *capnp.Pipeline }
 
-func (p AiConnector_connect_Results_Promise) Struct() (AiConnector_connect_Results, error) {
+func (p ConnectResponse_Promise) Struct() (ConnectResponse, error) {
 	s, err := p.Pipeline.Struct()
-	return AiConnector_connect_Results{s}, err
+	return ConnectResponse{s}, err
+}
+
+type ConnectStatus uint16
+
+// ConnectStatus_TypeID is the unique identifier for the type ConnectStatus.
+const ConnectStatus_TypeID = 0x952471f421b67c74
+
+// Values of ConnectStatus.
+const (
+	ConnectStatus_ok        ConnectStatus = 0
+	ConnectStatus_badToken  ConnectStatus = 1
+	ConnectStatus_nameTaken ConnectStatus = 2
+)
+
+// String returns the enum's constant name.
+func (c ConnectStatus) String() string {
+	switch c {
+	case ConnectStatus_ok:
+		return "ok"
+	case ConnectStatus_badToken:
+		return "badToken"
+	case ConnectStatus_nameTaken:
+		return "nameTaken"
+
+	default:
+		return ""
+	}
+}
+
+// ConnectStatusFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func ConnectStatusFromString(c string) ConnectStatus {
+	switch c {
+	case "ok":
+		return ConnectStatus_ok
+	case "badToken":
+		return ConnectStatus_badToken
+	case "nameTaken":
+		return ConnectStatus_nameTaken
+
+	default:
+		return 0
+	}
+}
+
+type ConnectStatus_List struct{ capnp.List }
+
+func NewConnectStatus_List(s *capnp.Segment, sz int32) (ConnectStatus_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return ConnectStatus_List{l.List}, err
+}
+
+func (l ConnectStatus_List) At(i int) ConnectStatus {
+	ul := capnp.UInt16List{List: l.List}
+	return ConnectStatus(ul.At(i))
+}
+
+func (l ConnectStatus_List) Set(i int, v ConnectStatus) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
 }
 
 type ConnectRequest struct{ capnp.Struct }
@@ -129,12 +204,12 @@ type ConnectRequest struct{ capnp.Struct }
 const ConnectRequest_TypeID = 0x95f2e57bf5bcea49
 
 func NewConnectRequest(s *capnp.Segment) (ConnectRequest, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
 	return ConnectRequest{st}, err
 }
 
 func NewRootConnectRequest(s *capnp.Segment) (ConnectRequest, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
 	return ConnectRequest{st}, err
 }
 
@@ -192,12 +267,20 @@ func (s ConnectRequest) SetAi(v Ai) error {
 	return s.Struct.SetPtr(1, in.ToPtr())
 }
 
+func (s ConnectRequest) Concurrent() bool {
+	return s.Struct.Bit(0)
+}
+
+func (s ConnectRequest) SetConcurrent(v bool) {
+	s.Struct.SetBit(0, v)
+}
+
 // ConnectRequest_List is a list of ConnectRequest.
 type ConnectRequest_List struct{ capnp.List }
 
 // NewConnectRequest creates a new list of ConnectRequest.
 func NewConnectRequest_List(s *capnp.Segment, sz int32) (ConnectRequest_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
 	return ConnectRequest_List{l}, err
 }
 
@@ -205,6 +288,11 @@ func (s ConnectRequest_List) At(i int) ConnectRequest { return ConnectRequest{s.
 
 func (s ConnectRequest_List) Set(i int, v ConnectRequest) error { return s.List.SetStruct(i, v.Struct) }
 
+func (s ConnectRequest_List) String() string {
+	str, _ := text.MarshalList(0x95f2e57bf5bcea49, s.List)
+	return str
+}
+
 // ConnectRequest_Promise is a wrapper for a ConnectRequest promised by a client call.
 type ConnectRequest_Promise struct{ *capnp.Pipeline }
 
@@ -262,11 +350,7 @@ func (s Credentials) SecretTokenBytes() ([]byte, error) {
 }
 
 func (s Credentials) SetSecretToken(v string) error {
-	t, err := capnp.NewText(s.Struct.Segment(), v)
-	if err != nil {
-		return err
-	}
-	return s.Struct.SetPtr(0, t.List.ToPtr())
+	return s.Struct.SetText(0, v)
 }
 
 func (s Credentials) BotName() (string, error) {
@@ -285,11 +369,7 @@ func (s Credentials) BotNameBytes() ([]byte, error) {
 }
 
 func (s Credentials) SetBotName(v string) error {
-	t, err := capnp.NewText(s.Struct.Segment(), v)
-	if err != nil {
-		return err
-	}
-	return s.Struct.SetPtr(1, t.List.ToPtr())
+	return s.Struct.SetText(1, v)
 }
 
 // Credentials_List is a list of Credentials.
@@ -305,6 +385,11 @@ func (s Credentials_List) At(i int) Credentials { return Credentials{s.List.Stru
 
 func (s Credentials_List) Set(i int, v Credentials) error { return s.List.SetStruct(i, v.Struct) }
 
+func (s Credentials_List) String() string {
+	str, _ := text.MarshalList(0xcca8fe75a57f1ea7, s.List)
+	return str
+}
+
 // Credentials_Promise is a wrapper for a Credentials promised by a client call.
 type Credentials_Promise struct{ *capnp.Pipeline }
 
@@ -315,6 +400,9 @@ func (p Credentials_Promise) Struct() (Credentials, error) {
 
 type Ai struct{ Client capnp.Client }
 
+// Ai_TypeID is the unique identifier for the type Ai.
+const Ai_TypeID = 0xd403ce7bb5b69f1f
+
 func (c Ai) TakeTurn(ctx context.Context, params func(Ai_takeTurn_Params) error, opts ...capnp.CallOption) Ai_takeTurn_Results_Promise {
 	if c.Client == nil {
 		return Ai_takeTurn_Results_Promise{Pipeline: capnp.NewPipeline(capnp.ErrorAnswer(capnp.ErrNullClient))}
@@ -335,9 +423,53 @@ func (c Ai) TakeTurn(ctx context.Context, params func(Ai_takeTurn_Params) error,
 	}
 	return Ai_takeTurn_Results_Promise{Pipeline: capnp.NewPipeline(c.Client.Call(call))}
 }
+func (c Ai) GameStarted(ctx context.Context, params func(Ai_gameStarted_Params) error, opts ...capnp.CallOption) Ai_gameStarted_Results_Promise {
+	if c.Client == nil {
+		return Ai_gameStarted_Results_Promise{Pipeline: capnp.NewPipeline(capnp.ErrorAnswer(capnp.ErrNullClient))}
+	}
+	call := &capnp.Call{
+		Ctx: ctx,
+		Method: capnp.Method{
+			InterfaceID:   0xd403ce7bb5b69f1f,
+			MethodID:      1,
+			InterfaceName: "botapi.capnp:Ai",
+			MethodName:    "gameStarted",
+		},
+		Options: capnp.NewCallOptions(opts),
+	}
+	if params != nil {
+		call.ParamsSize = capnp.ObjectSize{DataSize: 0, PointerCount: 1}
+		call.ParamsFunc = func(s capnp.Struct) error { return params(Ai_gameStarted_Params{Struct: s}) }
+	}
+	return Ai_gameStarted_Results_Promise{Pipeline: capnp.NewPipeline(c.Client.Call(call))}
+}
+func (c Ai) GameEnded(ctx context.Context, params func(Ai_gameEnded_Params) error, opts ...capnp.CallOption) Ai_gameEnded_Results_Promise {
+	if c.Client == nil {
+		return Ai_gameEnded_Results_Promise{Pipeline: capnp.NewPipeline(capnp.ErrorAnswer(capnp.ErrNullClient))}
+	}
+	call := &capnp.Call{
+		Ctx: ctx,
+		Method: capnp.Method{
+			InterfaceID:   0xd403ce7bb5b69f1f,
+			MethodID:      2,
+			InterfaceName: "botapi.capnp:Ai",
+			MethodName:    "gameEnded",
+		},
+		Options: capnp.NewCallOptions(opts),
+	}
+	if params != nil {
+		call.ParamsSize = capnp.ObjectSize{DataSize: 0, PointerCount: 1}
+		call.ParamsFunc = func(s capnp.Struct) error { return params(Ai_gameEnded_Params{Struct: s}) }
+	}
+	return Ai_gameEnded_Results_Promise{Pipeline: capnp.NewPipeline(c.Client.Call(call))}
+}
 
 type Ai_Server interface {
 	TakeTurn(Ai_takeTurn) error
+
+	GameStarted(Ai_gameStarted) error
+
+	GameEnded(Ai_gameEnded) error
 }
 
 func Ai_ServerToClient(s Ai_Server) Ai {
@@ -347,7 +479,7 @@ func Ai_ServerToClient(s Ai_Server) Ai {
 
 func Ai_Methods(methods []server.Method, s Ai_Server) []server.Method {
 	if cap(methods) == 0 {
-		methods = make([]server.Method, 0, 1)
+		methods = make([]server.Method, 0, 3)
 	}
 
 	methods = append(methods, server.Method{
@@ -361,7 +493,35 @@ func Ai_Methods(methods []server.Method, s Ai_Server) []server.Method {
 			call := Ai_takeTurn{c, opts, Ai_takeTurn_Params{Struct: p}, Ai_takeTurn_Results{Struct: r}}
 			return s.TakeTurn(call)
 		},
-		ResultsSize: capnp.ObjectSize{DataSize: 0, PointerCount: 1},
+		ResultsSize: capnp.ObjectSize{DataSize: 8, PointerCount: 1},
+	})
+
+	methods = append(methods, server.Method{
+		Method: capnp.Method{
+			InterfaceID:   0xd403ce7bb5b69f1f,
+			MethodID:      1,
+			InterfaceName: "botapi.capnp:Ai",
+			MethodName:    "gameStarted",
+		},
+		Impl: func(c context.Context, opts capnp.CallOptions, p, r capnp.Struct) error {
+			call := Ai_gameStarted{c, opts, Ai_gameStarted_Params{Struct: p}, Ai_gameStarted_Results{Struct: r}}
+			return s.GameStarted(call)
+		},
+		ResultsSize: capnp.ObjectSize{DataSize: 0, PointerCount: 0},
+	})
+
+	methods = append(methods, server.Method{
+		Method: capnp.Method{
+			InterfaceID:   0xd403ce7bb5b69f1f,
+			MethodID:      2,
+			InterfaceName: "botapi.capnp:Ai",
+			MethodName:    "gameEnded",
+		},
+		Impl: func(c context.Context, opts capnp.CallOptions, p, r capnp.Struct) error {
+			call := Ai_gameEnded{c, opts, Ai_gameEnded_Params{Struct: p}, Ai_gameEnded_Results{Struct: r}}
+			return s.GameEnded(call)
+		},
+		ResultsSize: capnp.ObjectSize{DataSize: 0, PointerCount: 0},
 	})
 
 	return methods
@@ -375,6 +535,22 @@ type Ai_takeTurn struct {
 	Results Ai_takeTurn_Results
 }
 
+// Ai_gameStarted holds the arguments for a server call to Ai.gameStarted.
+type Ai_gameStarted struct {
+	Ctx     context.Context
+	Options capnp.CallOptions
+	Params  Ai_gameStarted_Params
+	Results Ai_gameStarted_Results
+}
+
+// Ai_gameEnded holds the arguments for a server call to Ai.gameEnded.
+type Ai_gameEnded struct {
+	Ctx     context.Context
+	Options capnp.CallOptions
+	Params  Ai_gameEnded_Params
+	Results Ai_gameEnded_Results
+}
+
 type Ai_takeTurn_Params struct{ capnp.Struct }
 
 // Ai_takeTurn_Params_TypeID is the unique identifier for the type Ai_takeTurn_Params.
@@ -442,6 +618,11 @@ func (s Ai_takeTurn_Params_List) Set(i int, v Ai_takeTurn_Params) error {
 	return s.List.SetStruct(i, v.Struct)
 }
 
+func (s Ai_takeTurn_Params_List) String() string {
+	str, _ := text.MarshalList(0x91b9eb0bc884d7fb, s.List)
+	return str
+}
+
 // Ai_takeTurn_Params_Promise is a wrapper for a Ai_takeTurn_Params promised by a client call.
 type Ai_takeTurn_Params_Promise struct{ *capnp.Pipeline }
 
@@ -460,12 +641,12 @@ type Ai_takeTurn_Results struct{ capnp.Struct }
 const Ai_takeTurn_Results_TypeID = 0x8d265c88e8a2e488
 
 func NewAi_takeTurn_Results(s *capnp.Segment) (Ai_takeTurn_Results, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
 	return Ai_takeTurn_Results{st}, err
 }
 
 func NewRootAi_takeTurn_Results(s *capnp.Segment) (Ai_takeTurn_Results, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1})
 	return Ai_takeTurn_Results{st}, err
 }
 
@@ -504,12 +685,20 @@ func (s Ai_takeTurn_Results) NewTurns(n int32) (Turn_List, error) {
 	return l, err
 }
 
+func (s Ai_takeTurn_Results) Crashes() uint32 {
+	return s.Struct.Uint32(0)
+}
+
+func (s Ai_takeTurn_Results) SetCrashes(v uint32) {
+	s.Struct.SetUint32(0, v)
+}
+
 // Ai_takeTurn_Results_List is a list of Ai_takeTurn_Results.
 type Ai_takeTurn_Results_List struct{ capnp.List }
 
 // NewAi_takeTurn_Results creates a new list of Ai_takeTurn_Results.
 func NewAi_takeTurn_Results_List(s *capnp.Segment, sz int32) (Ai_takeTurn_Results_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 1}, sz)
 	return Ai_takeTurn_Results_List{l}, err
 }
 
@@ -521,6 +710,11 @@ func (s Ai_takeTurn_Results_List) Set(i int, v Ai_takeTurn_Results) error {
 	return s.List.SetStruct(i, v.Struct)
 }
 
+func (s Ai_takeTurn_Results_List) String() string {
+	str, _ := text.MarshalList(0x8d265c88e8a2e488, s.List)
+	return str
+}
+
 // Ai_takeTurn_Results_Promise is a wrapper for a Ai_takeTurn_Results promised by a client call.
 type Ai_takeTurn_Results_Promise struct{ *capnp.Pipeline }
 
@@ -529,222 +723,1326 @@ func (p Ai_takeTurn_Results_Promise) Struct() (Ai_takeTurn_Results, error) {
 	return Ai_takeTurn_Results{s}, err
 }
 
-type Board struct{ capnp.Struct }
+type Ai_gameStarted_Params struct{ capnp.Struct }
 
-// Board_TypeID is the unique identifier for the type Board.
-const Board_TypeID = 0xd57da3828ebb699b
+// Ai_gameStarted_Params_TypeID is the unique identifier for the type Ai_gameStarted_Params.
+const Ai_gameStarted_Params_TypeID = 0x86353eadfb092fc4
 
-func NewBoard(s *capnp.Segment) (Board, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
-	return Board{st}, err
+func NewAi_gameStarted_Params(s *capnp.Segment) (Ai_gameStarted_Params, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Ai_gameStarted_Params{st}, err
 }
 
-func NewRootBoard(s *capnp.Segment) (Board, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
-	return Board{st}, err
+func NewRootAi_gameStarted_Params(s *capnp.Segment) (Ai_gameStarted_Params, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Ai_gameStarted_Params{st}, err
 }
 
-func ReadRootBoard(msg *capnp.Message) (Board, error) {
+func ReadRootAi_gameStarted_Params(msg *capnp.Message) (Ai_gameStarted_Params, error) {
 	root, err := msg.RootPtr()
-	return Board{root.Struct()}, err
+	return Ai_gameStarted_Params{root.Struct()}, err
 }
 
-func (s Board) String() string {
-	str, _ := text.Marshal(0xd57da3828ebb699b, s.Struct)
+func (s Ai_gameStarted_Params) String() string {
+	str, _ := text.Marshal(0x86353eadfb092fc4, s.Struct)
 	return str
 }
 
-func (s Board) GameId() (string, error) {
-	p, err := s.Struct.Ptr(1)
-	return p.Text(), err
+func (s Ai_gameStarted_Params) Board() (InitialBoard, error) {
+	p, err := s.Struct.Ptr(0)
+	return InitialBoard{Struct: p.Struct()}, err
 }
 
-func (s Board) HasGameId() bool {
-	p, err := s.Struct.Ptr(1)
+func (s Ai_gameStarted_Params) HasBoard() bool {
+	p, err := s.Struct.Ptr(0)
 	return p.IsValid() || err != nil
 }
 
-func (s Board) GameIdBytes() ([]byte, error) {
-	p, err := s.Struct.Ptr(1)
-	return p.TextBytes(), err
+func (s Ai_gameStarted_Params) SetBoard(v InitialBoard) error {
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
 }
 
-func (s Board) SetGameId(v string) error {
-	t, err := capnp.NewText(s.Struct.Segment(), v)
+// NewBoard sets the board field to a newly
+// allocated InitialBoard struct, preferring placement in s's segment.
+func (s Ai_gameStarted_Params) NewBoard() (InitialBoard, error) {
+	ss, err := NewInitialBoard(s.Struct.Segment())
 	if err != nil {
-		return err
+		return InitialBoard{}, err
 	}
-	return s.Struct.SetPtr(1, t.List.ToPtr())
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
 }
 
-func (s Board) Width() uint16 {
-	return s.Struct.Uint16(0)
+// Ai_gameStarted_Params_List is a list of Ai_gameStarted_Params.
+type Ai_gameStarted_Params_List struct{ capnp.List }
+
+// NewAi_gameStarted_Params creates a new list of Ai_gameStarted_Params.
+func NewAi_gameStarted_Params_List(s *capnp.Segment, sz int32) (Ai_gameStarted_Params_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return Ai_gameStarted_Params_List{l}, err
 }
 
-func (s Board) SetWidth(v uint16) {
-	s.Struct.SetUint16(0, v)
+func (s Ai_gameStarted_Params_List) At(i int) Ai_gameStarted_Params {
+	return Ai_gameStarted_Params{s.List.Struct(i)}
 }
 
-func (s Board) Height() uint16 {
-	return s.Struct.Uint16(2)
+func (s Ai_gameStarted_Params_List) Set(i int, v Ai_gameStarted_Params) error {
+	return s.List.SetStruct(i, v.Struct)
 }
 
-func (s Board) SetHeight(v uint16) {
-	s.Struct.SetUint16(2, v)
+func (s Ai_gameStarted_Params_List) String() string {
+	str, _ := text.MarshalList(0x86353eadfb092fc4, s.List)
+	return str
 }
 
-func (s Board) Robots() (Robot_List, error) {
-	p, err := s.Struct.Ptr(0)
-	return Robot_List{List: p.List()}, err
+// Ai_gameStarted_Params_Promise is a wrapper for a Ai_gameStarted_Params promised by a client call.
+type Ai_gameStarted_Params_Promise struct{ *capnp.Pipeline }
+
+func (p Ai_gameStarted_Params_Promise) Struct() (Ai_gameStarted_Params, error) {
+	s, err := p.Pipeline.Struct()
+	return Ai_gameStarted_Params{s}, err
 }
 
-func (s Board) HasRobots() bool {
-	p, err := s.Struct.Ptr(0)
-	return p.IsValid() || err != nil
+func (p Ai_gameStarted_Params_Promise) Board() InitialBoard_Promise {
+	return InitialBoard_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
 }
 
-func (s Board) SetRobots(v Robot_List) error {
-	return s.Struct.SetPtr(0, v.List.ToPtr())
+type Ai_gameStarted_Results struct{ capnp.Struct }
+
+// Ai_gameStarted_Results_TypeID is the unique identifier for the type Ai_gameStarted_Results.
+const Ai_gameStarted_Results_TypeID = 0xef263491be9d3220
+
+func NewAi_gameStarted_Results(s *capnp.Segment) (Ai_gameStarted_Results, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0})
+	return Ai_gameStarted_Results{st}, err
 }
 
-// NewRobots sets the robots field to a newly
-// allocated Robot_List, preferring placement in s's segment.
-func (s Board) NewRobots(n int32) (Robot_List, error) {
-	l, err := NewRobot_List(s.Struct.Segment(), n)
-	if err != nil {
-		return Robot_List{}, err
-	}
-	err = s.Struct.SetPtr(0, l.List.ToPtr())
-	return l, err
+func NewRootAi_gameStarted_Results(s *capnp.Segment) (Ai_gameStarted_Results, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0})
+	return Ai_gameStarted_Results{st}, err
 }
 
-func (s Board) Round() int32 {
-	return int32(s.Struct.Uint32(4))
+func ReadRootAi_gameStarted_Results(msg *capnp.Message) (Ai_gameStarted_Results, error) {
+	root, err := msg.RootPtr()
+	return Ai_gameStarted_Results{root.Struct()}, err
 }
 
-func (s Board) SetRound(v int32) {
-	s.Struct.SetUint32(4, uint32(v))
+func (s Ai_gameStarted_Results) String() string {
+	str, _ := text.Marshal(0xef263491be9d3220, s.Struct)
+	return str
 }
 
-// Board_List is a list of Board.
-type Board_List struct{ capnp.List }
+// Ai_gameStarted_Results_List is a list of Ai_gameStarted_Results.
+type Ai_gameStarted_Results_List struct{ capnp.List }
 
-// NewBoard creates a new list of Board.
-func NewBoard_List(s *capnp.Segment, sz int32) (Board_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
-	return Board_List{l}, err
+// NewAi_gameStarted_Results creates a new list of Ai_gameStarted_Results.
+func NewAi_gameStarted_Results_List(s *capnp.Segment, sz int32) (Ai_gameStarted_Results_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0}, sz)
+	return Ai_gameStarted_Results_List{l}, err
 }
 
-func (s Board_List) At(i int) Board { return Board{s.List.Struct(i)} }
+func (s Ai_gameStarted_Results_List) At(i int) Ai_gameStarted_Results {
+	return Ai_gameStarted_Results{s.List.Struct(i)}
+}
 
-func (s Board_List) Set(i int, v Board) error { return s.List.SetStruct(i, v.Struct) }
+func (s Ai_gameStarted_Results_List) Set(i int, v Ai_gameStarted_Results) error {
+	return s.List.SetStruct(i, v.Struct)
+}
 
-// Board_Promise is a wrapper for a Board promised by a client call.
-type Board_Promise struct{ *capnp.Pipeline }
+func (s Ai_gameStarted_Results_List) String() string {
+	str, _ := text.MarshalList(0xef263491be9d3220, s.List)
+	return str
+}
 
-func (p Board_Promise) Struct() (Board, error) {
+// Ai_gameStarted_Results_Promise is a wrapper for a Ai_gameStarted_Results promised by a client call.
+type Ai_gameStarted_Results_Promise struct{ *capnp.Pipeline }
+
+func (p Ai_gameStarted_Results_Promise) Struct() (Ai_gameStarted_Results, error) {
 	s, err := p.Pipeline.Struct()
-	return Board{s}, err
+	return Ai_gameStarted_Results{s}, err
 }
 
-type InitialBoard struct{ capnp.Struct }
+type Ai_gameEnded_Params struct{ capnp.Struct }
 
-// InitialBoard_TypeID is the unique identifier for the type InitialBoard.
-const InitialBoard_TypeID = 0xa01831bb8bf68e89
+// Ai_gameEnded_Params_TypeID is the unique identifier for the type Ai_gameEnded_Params.
+const Ai_gameEnded_Params_TypeID = 0xa8097dda1f5f5ece
 
-func NewInitialBoard(s *capnp.Segment) (InitialBoard, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
-	return InitialBoard{st}, err
+func NewAi_gameEnded_Params(s *capnp.Segment) (Ai_gameEnded_Params, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Ai_gameEnded_Params{st}, err
 }
 
-func NewRootInitialBoard(s *capnp.Segment) (InitialBoard, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
-	return InitialBoard{st}, err
+func NewRootAi_gameEnded_Params(s *capnp.Segment) (Ai_gameEnded_Params, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Ai_gameEnded_Params{st}, err
 }
 
-func ReadRootInitialBoard(msg *capnp.Message) (InitialBoard, error) {
+func ReadRootAi_gameEnded_Params(msg *capnp.Message) (Ai_gameEnded_Params, error) {
 	root, err := msg.RootPtr()
-	return InitialBoard{root.Struct()}, err
+	return Ai_gameEnded_Params{root.Struct()}, err
 }
 
-func (s InitialBoard) String() string {
-	str, _ := text.Marshal(0xa01831bb8bf68e89, s.Struct)
+func (s Ai_gameEnded_Params) String() string {
+	str, _ := text.Marshal(0xa8097dda1f5f5ece, s.Struct)
 	return str
 }
 
-func (s InitialBoard) Board() (Board, error) {
+func (s Ai_gameEnded_Params) Result() (GameResult, error) {
 	p, err := s.Struct.Ptr(0)
-	return Board{Struct: p.Struct()}, err
+	return GameResult{Struct: p.Struct()}, err
 }
 
-func (s InitialBoard) HasBoard() bool {
+func (s Ai_gameEnded_Params) HasResult() bool {
 	p, err := s.Struct.Ptr(0)
 	return p.IsValid() || err != nil
 }
 
-func (s InitialBoard) SetBoard(v Board) error {
+func (s Ai_gameEnded_Params) SetResult(v GameResult) error {
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewResult sets the result field to a newly
+// allocated GameResult struct, preferring placement in s's segment.
+func (s Ai_gameEnded_Params) NewResult() (GameResult, error) {
+	ss, err := NewGameResult(s.Struct.Segment())
+	if err != nil {
+		return GameResult{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+// Ai_gameEnded_Params_List is a list of Ai_gameEnded_Params.
+type Ai_gameEnded_Params_List struct{ capnp.List }
+
+// NewAi_gameEnded_Params creates a new list of Ai_gameEnded_Params.
+func NewAi_gameEnded_Params_List(s *capnp.Segment, sz int32) (Ai_gameEnded_Params_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return Ai_gameEnded_Params_List{l}, err
+}
+
+func (s Ai_gameEnded_Params_List) At(i int) Ai_gameEnded_Params {
+	return Ai_gameEnded_Params{s.List.Struct(i)}
+}
+
+func (s Ai_gameEnded_Params_List) Set(i int, v Ai_gameEnded_Params) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s Ai_gameEnded_Params_List) String() string {
+	str, _ := text.MarshalList(0xa8097dda1f5f5ece, s.List)
+	return str
+}
+
+// Ai_gameEnded_Params_Promise is a wrapper for a Ai_gameEnded_Params promised by a client call.
+type Ai_gameEnded_Params_Promise struct{ *capnp.Pipeline }
+
+func (p Ai_gameEnded_Params_Promise) Struct() (Ai_gameEnded_Params, error) {
+	s, err := p.Pipeline.Struct()
+	return Ai_gameEnded_Params{s}, err
+}
+
+func (p Ai_gameEnded_Params_Promise) Result() GameResult_Promise {
+	return GameResult_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+type Ai_gameEnded_Results struct{ capnp.Struct }
+
+// Ai_gameEnded_Results_TypeID is the unique identifier for the type Ai_gameEnded_Results.
+const Ai_gameEnded_Results_TypeID = 0xd6c8a227f1f61864
+
+func NewAi_gameEnded_Results(s *capnp.Segment) (Ai_gameEnded_Results, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0})
+	return Ai_gameEnded_Results{st}, err
+}
+
+func NewRootAi_gameEnded_Results(s *capnp.Segment) (Ai_gameEnded_Results, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0})
+	return Ai_gameEnded_Results{st}, err
+}
+
+func ReadRootAi_gameEnded_Results(msg *capnp.Message) (Ai_gameEnded_Results, error) {
+	root, err := msg.RootPtr()
+	return Ai_gameEnded_Results{root.Struct()}, err
+}
+
+func (s Ai_gameEnded_Results) String() string {
+	str, _ := text.Marshal(0xd6c8a227f1f61864, s.Struct)
+	return str
+}
+
+// Ai_gameEnded_Results_List is a list of Ai_gameEnded_Results.
+type Ai_gameEnded_Results_List struct{ capnp.List }
+
+// NewAi_gameEnded_Results creates a new list of Ai_gameEnded_Results.
+func NewAi_gameEnded_Results_List(s *capnp.Segment, sz int32) (Ai_gameEnded_Results_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 0}, sz)
+	return Ai_gameEnded_Results_List{l}, err
+}
+
+func (s Ai_gameEnded_Results_List) At(i int) Ai_gameEnded_Results {
+	return Ai_gameEnded_Results{s.List.Struct(i)}
+}
+
+func (s Ai_gameEnded_Results_List) Set(i int, v Ai_gameEnded_Results) error {
+	return s.List.SetStruct(i, v.Struct)
+}
+
+func (s Ai_gameEnded_Results_List) String() string {
+	str, _ := text.MarshalList(0xd6c8a227f1f61864, s.List)
+	return str
+}
+
+// Ai_gameEnded_Results_Promise is a wrapper for a Ai_gameEnded_Results promised by a client call.
+type Ai_gameEnded_Results_Promise struct{ *capnp.Pipeline }
+
+func (p Ai_gameEnded_Results_Promise) Struct() (Ai_gameEnded_Results, error) {
+	s, err := p.Pipeline.Struct()
+	return Ai_gameEnded_Results{s}, err
+}
+
+type GameResult struct{ capnp.Struct }
+
+// GameResult_TypeID is the unique identifier for the type GameResult.
+const GameResult_TypeID = 0xcf62c64f643dcea5
+
+func NewGameResult(s *capnp.Segment) (GameResult, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return GameResult{st}, err
+}
+
+func NewRootGameResult(s *capnp.Segment) (GameResult, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return GameResult{st}, err
+}
+
+func ReadRootGameResult(msg *capnp.Message) (GameResult, error) {
+	root, err := msg.RootPtr()
+	return GameResult{root.Struct()}, err
+}
+
+func (s GameResult) String() string {
+	str, _ := text.Marshal(0xcf62c64f643dcea5, s.Struct)
+	return str
+}
+
+func (s GameResult) Board() (InitialBoard, error) {
+	p, err := s.Struct.Ptr(0)
+	return InitialBoard{Struct: p.Struct()}, err
+}
+
+func (s GameResult) HasBoard() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s GameResult) SetBoard(v InitialBoard) error {
 	return s.Struct.SetPtr(0, v.Struct.ToPtr())
 }
 
 // NewBoard sets the board field to a newly
-// allocated Board struct, preferring placement in s's segment.
-func (s InitialBoard) NewBoard() (Board, error) {
-	ss, err := NewBoard(s.Struct.Segment())
+// allocated InitialBoard struct, preferring placement in s's segment.
+func (s GameResult) NewBoard() (InitialBoard, error) {
+	ss, err := NewInitialBoard(s.Struct.Segment())
 	if err != nil {
-		return Board{}, err
+		return InitialBoard{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s GameResult) Reason() EndReason {
+	return EndReason(s.Struct.Uint16(0))
+}
+
+func (s GameResult) SetReason(v EndReason) {
+	s.Struct.SetUint16(0, uint16(v))
+}
+
+func (s GameResult) Winner() uint8 {
+	return s.Struct.Uint8(2)
+}
+
+func (s GameResult) SetWinner(v uint8) {
+	s.Struct.SetUint8(2, v)
+}
+
+func (s GameResult) Tiebreak() Tiebreak {
+	return Tiebreak(s.Struct.Uint16(4))
+}
+
+func (s GameResult) SetTiebreak(v Tiebreak) {
+	s.Struct.SetUint16(4, uint16(v))
+}
+
+func (s GameResult) Scores() (capnp.Int32List, error) {
+	p, err := s.Struct.Ptr(1)
+	return capnp.Int32List{List: p.List()}, err
+}
+
+func (s GameResult) HasScores() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s GameResult) SetScores(v capnp.Int32List) error {
+	return s.Struct.SetPtr(1, v.List.ToPtr())
+}
+
+// NewScores sets the scores field to a newly
+// allocated capnp.Int32List, preferring placement in s's segment.
+func (s GameResult) NewScores(n int32) (capnp.Int32List, error) {
+	l, err := capnp.NewInt32List(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.Int32List{}, err
+	}
+	err = s.Struct.SetPtr(1, l.List.ToPtr())
+	return l, err
+}
+
+// GameResult_List is a list of GameResult.
+type GameResult_List struct{ capnp.List }
+
+// NewGameResult creates a new list of GameResult.
+func NewGameResult_List(s *capnp.Segment, sz int32) (GameResult_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
+	return GameResult_List{l}, err
+}
+
+func (s GameResult_List) At(i int) GameResult { return GameResult{s.List.Struct(i)} }
+
+func (s GameResult_List) Set(i int, v GameResult) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s GameResult_List) String() string {
+	str, _ := text.MarshalList(0xcf62c64f643dcea5, s.List)
+	return str
+}
+
+// GameResult_Promise is a wrapper for a GameResult promised by a client call.
+type GameResult_Promise struct{ *capnp.Pipeline }
+
+func (p GameResult_Promise) Struct() (GameResult, error) {
+	s, err := p.Pipeline.Struct()
+	return GameResult{s}, err
+}
+
+func (p GameResult_Promise) Board() InitialBoard_Promise {
+	return InitialBoard_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+type EndReason uint16
+
+// EndReason_TypeID is the unique identifier for the type EndReason.
+const EndReason_TypeID = 0xbd6a477d4c71e823
+
+// Values of EndReason.
+const (
+	EndReason_roundLimit  EndReason = 0
+	EndReason_elimination EndReason = 1
+	EndReason_forfeit     EndReason = 2
+	EndReason_aborted     EndReason = 3
+)
+
+// String returns the enum's constant name.
+func (c EndReason) String() string {
+	switch c {
+	case EndReason_roundLimit:
+		return "roundLimit"
+	case EndReason_elimination:
+		return "elimination"
+	case EndReason_forfeit:
+		return "forfeit"
+	case EndReason_aborted:
+		return "aborted"
+
+	default:
+		return ""
+	}
+}
+
+// EndReasonFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func EndReasonFromString(c string) EndReason {
+	switch c {
+	case "roundLimit":
+		return EndReason_roundLimit
+	case "elimination":
+		return EndReason_elimination
+	case "forfeit":
+		return EndReason_forfeit
+	case "aborted":
+		return EndReason_aborted
+
+	default:
+		return 0
+	}
+}
+
+type EndReason_List struct{ capnp.List }
+
+func NewEndReason_List(s *capnp.Segment, sz int32) (EndReason_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return EndReason_List{l.List}, err
+}
+
+func (l EndReason_List) At(i int) EndReason {
+	ul := capnp.UInt16List{List: l.List}
+	return EndReason(ul.At(i))
+}
+
+func (l EndReason_List) Set(i int, v EndReason) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
+}
+
+type Board struct{ capnp.Struct }
+
+// Board_TypeID is the unique identifier for the type Board.
+const Board_TypeID = 0xd57da3828ebb699b
+
+func NewBoard(s *capnp.Segment) (Board, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return Board{st}, err
+}
+
+func NewRootBoard(s *capnp.Segment) (Board, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return Board{st}, err
+}
+
+func ReadRootBoard(msg *capnp.Message) (Board, error) {
+	root, err := msg.RootPtr()
+	return Board{root.Struct()}, err
+}
+
+func (s Board) String() string {
+	str, _ := text.Marshal(0xd57da3828ebb699b, s.Struct)
+	return str
+}
+
+func (s Board) GameId() (string, error) {
+	p, err := s.Struct.Ptr(1)
+	return p.Text(), err
+}
+
+func (s Board) HasGameId() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s Board) GameIdBytes() ([]byte, error) {
+	p, err := s.Struct.Ptr(1)
+	return p.TextBytes(), err
+}
+
+func (s Board) SetGameId(v string) error {
+	return s.Struct.SetText(1, v)
+}
+
+func (s Board) Width() uint16 {
+	return s.Struct.Uint16(0)
+}
+
+func (s Board) SetWidth(v uint16) {
+	s.Struct.SetUint16(0, v)
+}
+
+func (s Board) Height() uint16 {
+	return s.Struct.Uint16(2)
+}
+
+func (s Board) SetHeight(v uint16) {
+	s.Struct.SetUint16(2, v)
+}
+
+func (s Board) Robots() (Robot_List, error) {
+	p, err := s.Struct.Ptr(0)
+	return Robot_List{List: p.List()}, err
+}
+
+func (s Board) HasRobots() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Board) SetRobots(v Robot_List) error {
+	return s.Struct.SetPtr(0, v.List.ToPtr())
+}
+
+// NewRobots sets the robots field to a newly
+// allocated Robot_List, preferring placement in s's segment.
+func (s Board) NewRobots(n int32) (Robot_List, error) {
+	l, err := NewRobot_List(s.Struct.Segment(), n)
+	if err != nil {
+		return Robot_List{}, err
+	}
+	err = s.Struct.SetPtr(0, l.List.ToPtr())
+	return l, err
+}
+
+func (s Board) Round() int32 {
+	return int32(s.Struct.Uint32(4))
+}
+
+func (s Board) SetRound(v int32) {
+	s.Struct.SetUint32(4, uint32(v))
+}
+
+// Board_List is a list of Board.
+type Board_List struct{ capnp.List }
+
+// NewBoard creates a new list of Board.
+func NewBoard_List(s *capnp.Segment, sz int32) (Board_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
+	return Board_List{l}, err
+}
+
+func (s Board_List) At(i int) Board { return Board{s.List.Struct(i)} }
+
+func (s Board_List) Set(i int, v Board) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Board_List) String() string {
+	str, _ := text.MarshalList(0xd57da3828ebb699b, s.List)
+	return str
+}
+
+// Board_Promise is a wrapper for a Board promised by a client call.
+type Board_Promise struct{ *capnp.Pipeline }
+
+func (p Board_Promise) Struct() (Board, error) {
+	s, err := p.Pipeline.Struct()
+	return Board{s}, err
+}
+
+type InitialBoard struct{ capnp.Struct }
+
+// InitialBoard_TypeID is the unique identifier for the type InitialBoard.
+const InitialBoard_TypeID = 0xa01831bb8bf68e89
+
+func NewInitialBoard(s *capnp.Segment) (InitialBoard, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4})
+	return InitialBoard{st}, err
+}
+
+func NewRootInitialBoard(s *capnp.Segment) (InitialBoard, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4})
+	return InitialBoard{st}, err
+}
+
+func ReadRootInitialBoard(msg *capnp.Message) (InitialBoard, error) {
+	root, err := msg.RootPtr()
+	return InitialBoard{root.Struct()}, err
+}
+
+func (s InitialBoard) String() string {
+	str, _ := text.Marshal(0xa01831bb8bf68e89, s.Struct)
+	return str
+}
+
+func (s InitialBoard) Board() (Board, error) {
+	p, err := s.Struct.Ptr(0)
+	return Board{Struct: p.Struct()}, err
+}
+
+func (s InitialBoard) HasBoard() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s InitialBoard) SetBoard(v Board) error {
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewBoard sets the board field to a newly
+// allocated Board struct, preferring placement in s's segment.
+func (s InitialBoard) NewBoard() (Board, error) {
+	ss, err := NewBoard(s.Struct.Segment())
+	if err != nil {
+		return Board{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s InitialBoard) Cells() (CellType_List, error) {
+	p, err := s.Struct.Ptr(1)
+	return CellType_List{List: p.List()}, err
+}
+
+func (s InitialBoard) HasCells() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s InitialBoard) SetCells(v CellType_List) error {
+	return s.Struct.SetPtr(1, v.List.ToPtr())
+}
+
+// NewCells sets the cells field to a newly
+// allocated CellType_List, preferring placement in s's segment.
+func (s InitialBoard) NewCells(n int32) (CellType_List, error) {
+	l, err := NewCellType_List(s.Struct.Segment(), n)
+	if err != nil {
+		return CellType_List{}, err
+	}
+	err = s.Struct.SetPtr(1, l.List.ToPtr())
+	return l, err
+}
+
+func (s InitialBoard) Rules() (RuleSet, error) {
+	p, err := s.Struct.Ptr(2)
+	return RuleSet{Struct: p.Struct()}, err
+}
+
+func (s InitialBoard) HasRules() bool {
+	p, err := s.Struct.Ptr(2)
+	return p.IsValid() || err != nil
+}
+
+func (s InitialBoard) SetRules(v RuleSet) error {
+	return s.Struct.SetPtr(2, v.Struct.ToPtr())
+}
+
+// NewRules sets the rules field to a newly
+// allocated RuleSet struct, preferring placement in s's segment.
+func (s InitialBoard) NewRules() (RuleSet, error) {
+	ss, err := NewRuleSet(s.Struct.Segment())
+	if err != nil {
+		return RuleSet{}, err
+	}
+	err = s.Struct.SetPtr(2, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s InitialBoard) Symmetry() Symmetry {
+	return Symmetry(s.Struct.Uint16(0))
+}
+
+func (s InitialBoard) SetSymmetry(v Symmetry) {
+	s.Struct.SetUint16(0, uint16(v))
+}
+
+func (s InitialBoard) Players() uint8 {
+	return s.Struct.Uint8(2) ^ 2
+}
+
+func (s InitialBoard) SetPlayers(v uint8) {
+	s.Struct.SetUint8(2, v^2)
+}
+
+func (s InitialBoard) Player() uint8 {
+	return s.Struct.Uint8(3) ^ 1
+}
+
+func (s InitialBoard) SetPlayer(v uint8) {
+	s.Struct.SetUint8(3, v^1)
+}
+
+func (s InitialBoard) Teams() uint8 {
+	return s.Struct.Uint8(4)
+}
+
+func (s InitialBoard) SetTeams(v uint8) {
+	s.Struct.SetUint8(4, v)
+}
+
+func (s InitialBoard) SpawnRounds() (capnp.Int32List, error) {
+	p, err := s.Struct.Ptr(3)
+	return capnp.Int32List{List: p.List()}, err
+}
+
+func (s InitialBoard) HasSpawnRounds() bool {
+	p, err := s.Struct.Ptr(3)
+	return p.IsValid() || err != nil
+}
+
+func (s InitialBoard) SetSpawnRounds(v capnp.Int32List) error {
+	return s.Struct.SetPtr(3, v.List.ToPtr())
+}
+
+// NewSpawnRounds sets the spawnRounds field to a newly
+// allocated capnp.Int32List, preferring placement in s's segment.
+func (s InitialBoard) NewSpawnRounds(n int32) (capnp.Int32List, error) {
+	l, err := capnp.NewInt32List(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.Int32List{}, err
+	}
+	err = s.Struct.SetPtr(3, l.List.ToPtr())
+	return l, err
+}
+
+// InitialBoard_List is a list of InitialBoard.
+type InitialBoard_List struct{ capnp.List }
+
+// NewInitialBoard creates a new list of InitialBoard.
+func NewInitialBoard_List(s *capnp.Segment, sz int32) (InitialBoard_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4}, sz)
+	return InitialBoard_List{l}, err
+}
+
+func (s InitialBoard_List) At(i int) InitialBoard { return InitialBoard{s.List.Struct(i)} }
+
+func (s InitialBoard_List) Set(i int, v InitialBoard) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s InitialBoard_List) String() string {
+	str, _ := text.MarshalList(0xa01831bb8bf68e89, s.List)
+	return str
+}
+
+// InitialBoard_Promise is a wrapper for a InitialBoard promised by a client call.
+type InitialBoard_Promise struct{ *capnp.Pipeline }
+
+func (p InitialBoard_Promise) Struct() (InitialBoard, error) {
+	s, err := p.Pipeline.Struct()
+	return InitialBoard{s}, err
+}
+
+func (p InitialBoard_Promise) Board() Board_Promise {
+	return Board_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p InitialBoard_Promise) Rules() RuleSet_Promise {
+	return RuleSet_Promise{Pipeline: p.Pipeline.GetPipeline(2)}
+}
+
+type Symmetry uint16
+
+// Symmetry_TypeID is the unique identifier for the type Symmetry.
+const Symmetry_TypeID = 0xba4afe8266f3c861
+
+// Values of Symmetry.
+const (
+	Symmetry_horizontal Symmetry = 0
+	Symmetry_vertical   Symmetry = 1
+	Symmetry_rotational Symmetry = 2
+	Symmetry_diagonal   Symmetry = 3
+	Symmetry_quartered  Symmetry = 4
+)
+
+// String returns the enum's constant name.
+func (c Symmetry) String() string {
+	switch c {
+	case Symmetry_horizontal:
+		return "horizontal"
+	case Symmetry_vertical:
+		return "vertical"
+	case Symmetry_rotational:
+		return "rotational"
+	case Symmetry_diagonal:
+		return "diagonal"
+	case Symmetry_quartered:
+		return "quartered"
+
+	default:
+		return ""
+	}
+}
+
+// SymmetryFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func SymmetryFromString(c string) Symmetry {
+	switch c {
+	case "horizontal":
+		return Symmetry_horizontal
+	case "vertical":
+		return Symmetry_vertical
+	case "rotational":
+		return Symmetry_rotational
+	case "diagonal":
+		return Symmetry_diagonal
+	case "quartered":
+		return Symmetry_quartered
+
+	default:
+		return 0
+	}
+}
+
+type Symmetry_List struct{ capnp.List }
+
+func NewSymmetry_List(s *capnp.Segment, sz int32) (Symmetry_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return Symmetry_List{l.List}, err
+}
+
+func (l Symmetry_List) At(i int) Symmetry {
+	ul := capnp.UInt16List{List: l.List}
+	return Symmetry(ul.At(i))
+}
+
+func (l Symmetry_List) Set(i int, v Symmetry) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
+}
+
+type RuleSet struct{ capnp.Struct }
+
+// RuleSet_TypeID is the unique identifier for the type RuleSet.
+const RuleSet_TypeID = 0x89ec5bd250304cdf
+
+func NewRuleSet(s *capnp.Segment) (RuleSet, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 88, PointerCount: 0})
+	return RuleSet{st}, err
+}
+
+func NewRootRuleSet(s *capnp.Segment) (RuleSet, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 88, PointerCount: 0})
+	return RuleSet{st}, err
+}
+
+func ReadRootRuleSet(msg *capnp.Message) (RuleSet, error) {
+	root, err := msg.RootPtr()
+	return RuleSet{root.Struct()}, err
+}
+
+func (s RuleSet) String() string {
+	str, _ := text.Marshal(0x89ec5bd250304cdf, s.Struct)
+	return str
+}
+
+func (s RuleSet) InitialHealth() int32 {
+	return int32(s.Struct.Uint32(0) ^ 50)
+}
+
+func (s RuleSet) SetInitialHealth(v int32) {
+	s.Struct.SetUint32(0, uint32(v)^50)
+}
+
+func (s RuleSet) CollisionDamage() int32 {
+	return int32(s.Struct.Uint32(4) ^ 5)
+}
+
+func (s RuleSet) SetCollisionDamage(v int32) {
+	s.Struct.SetUint32(4, uint32(v)^5)
+}
+
+func (s RuleSet) AttackDamage() int32 {
+	return int32(s.Struct.Uint32(8) ^ 10)
+}
+
+func (s RuleSet) SetAttackDamage(v int32) {
+	s.Struct.SetUint32(8, uint32(v)^10)
+}
+
+func (s RuleSet) DestructDamage() int32 {
+	return int32(s.Struct.Uint32(12) ^ 15)
+}
+
+func (s RuleSet) SetDestructDamage(v int32) {
+	s.Struct.SetUint32(12, uint32(v)^15)
+}
+
+func (s RuleSet) GuardMultiplier() float64 {
+	return math.Float64frombits(s.Struct.Uint64(16) ^ 0x3fe0000000000000)
+}
+
+func (s RuleSet) SetGuardMultiplier(v float64) {
+	s.Struct.SetUint64(16, math.Float64bits(v)^0x3fe0000000000000)
+}
+
+func (s RuleSet) SpawnEvery() int32 {
+	return int32(s.Struct.Uint32(24) ^ 10)
+}
+
+func (s RuleSet) SetSpawnEvery(v int32) {
+	s.Struct.SetUint32(24, uint32(v)^10)
+}
+
+func (s RuleSet) SpawnUntil() int32 {
+	return int32(s.Struct.Uint32(28) ^ 100)
+}
+
+func (s RuleSet) SetSpawnUntil(v int32) {
+	s.Struct.SetUint32(28, uint32(v)^100)
+}
+
+func (s RuleSet) MaxRounds() int32 {
+	return int32(s.Struct.Uint32(32) ^ 100)
+}
+
+func (s RuleSet) SetMaxRounds(v int32) {
+	s.Struct.SetUint32(32, uint32(v)^100)
+}
+
+func (s RuleSet) FriendlyFire() bool {
+	return !s.Struct.Bit(288)
+}
+
+func (s RuleSet) SetFriendlyFire(v bool) {
+	s.Struct.SetBit(288, !v)
+}
+
+func (s RuleSet) IllegalPenalty() Penalty {
+	return Penalty(s.Struct.Uint16(38) ^ 1)
+}
+
+func (s RuleSet) SetIllegalPenalty(v Penalty) {
+	s.Struct.SetUint16(38, uint16(v)^1)
+}
+
+func (s RuleSet) IllegalDamage() int32 {
+	return int32(s.Struct.Uint32(40) ^ 5)
+}
+
+func (s RuleSet) SetIllegalDamage(v int32) {
+	s.Struct.SetUint32(40, uint32(v)^5)
+}
+
+func (s RuleSet) EndOnElimination() bool {
+	return s.Struct.Bit(289)
+}
+
+func (s RuleSet) SetEndOnElimination(v bool) {
+	s.Struct.SetBit(289, v)
+}
+
+func (s RuleSet) FirstTiebreak() Tiebreak {
+	return Tiebreak(s.Struct.Uint16(44))
+}
+
+func (s RuleSet) SetFirstTiebreak(v Tiebreak) {
+	s.Struct.SetUint16(44, uint16(v))
+}
+
+func (s RuleSet) SecondTiebreak() Tiebreak {
+	return Tiebreak(s.Struct.Uint16(46))
+}
+
+func (s RuleSet) SetSecondTiebreak(v Tiebreak) {
+	s.Struct.SetUint16(46, uint16(v))
+}
+
+func (s RuleSet) VisionRadius() int32 {
+	return int32(s.Struct.Uint32(48))
+}
+
+func (s RuleSet) SetVisionRadius(v int32) {
+	s.Struct.SetUint32(48, uint32(v))
+}
+
+func (s RuleSet) VisionMetric() Metric {
+	return Metric(s.Struct.Uint16(52))
+}
+
+func (s RuleSet) SetVisionMetric(v Metric) {
+	s.Struct.SetUint16(52, uint16(v))
+}
+
+func (s RuleSet) HazardDamage() int32 {
+	return int32(s.Struct.Uint32(56) ^ 5)
+}
+
+func (s RuleSet) SetHazardDamage(v int32) {
+	s.Struct.SetUint32(56, uint32(v)^5)
+}
+
+func (s RuleSet) CoverMultiplier() float64 {
+	return math.Float64frombits(s.Struct.Uint64(64) ^ 0x3fe0000000000000)
+}
+
+func (s RuleSet) SetCoverMultiplier(v float64) {
+	s.Struct.SetUint64(64, math.Float64bits(v)^0x3fe0000000000000)
+}
+
+func (s RuleSet) HealAmount() int32 {
+	return int32(s.Struct.Uint32(60) ^ 5)
+}
+
+func (s RuleSet) SetHealAmount(v int32) {
+	s.Struct.SetUint32(60, uint32(v)^5)
+}
+
+func (s RuleSet) AllyHeal() int32 {
+	return int32(s.Struct.Uint32(72))
+}
+
+func (s RuleSet) SetAllyHeal(v int32) {
+	s.Struct.SetUint32(72, uint32(v))
+}
+
+func (s RuleSet) RangedDamage() int32 {
+	return int32(s.Struct.Uint32(76))
+}
+
+func (s RuleSet) SetRangedDamage(v int32) {
+	s.Struct.SetUint32(76, uint32(v))
+}
+
+func (s RuleSet) SpawnCamping() Camping {
+	return Camping(s.Struct.Uint16(54))
+}
+
+func (s RuleSet) SetSpawnCamping(v Camping) {
+	s.Struct.SetUint16(54, uint16(v))
+}
+
+func (s RuleSet) CampingDamage() int32 {
+	return int32(s.Struct.Uint32(80) ^ 10)
+}
+
+func (s RuleSet) SetCampingDamage(v int32) {
+	s.Struct.SetUint32(80, uint32(v)^10)
+}
+
+// RuleSet_List is a list of RuleSet.
+type RuleSet_List struct{ capnp.List }
+
+// NewRuleSet creates a new list of RuleSet.
+func NewRuleSet_List(s *capnp.Segment, sz int32) (RuleSet_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 88, PointerCount: 0}, sz)
+	return RuleSet_List{l}, err
+}
+
+func (s RuleSet_List) At(i int) RuleSet { return RuleSet{s.List.Struct(i)} }
+
+func (s RuleSet_List) Set(i int, v RuleSet) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s RuleSet_List) String() string {
+	str, _ := text.MarshalList(0x89ec5bd250304cdf, s.List)
+	return str
+}
+
+// RuleSet_Promise is a wrapper for a RuleSet promised by a client call.
+type RuleSet_Promise struct{ *capnp.Pipeline }
+
+func (p RuleSet_Promise) Struct() (RuleSet, error) {
+	s, err := p.Pipeline.Struct()
+	return RuleSet{s}, err
+}
+
+type Camping uint16
+
+// Camping_TypeID is the unique identifier for the type Camping.
+const Camping_TypeID = 0xeff431b8c472cc8e
+
+// Values of Camping.
+const (
+	Camping_kill   Camping = 0
+	Camping_skip   Camping = 1
+	Camping_damage Camping = 2
+)
+
+// String returns the enum's constant name.
+func (c Camping) String() string {
+	switch c {
+	case Camping_kill:
+		return "kill"
+	case Camping_skip:
+		return "skip"
+	case Camping_damage:
+		return "damage"
+
+	default:
+		return ""
+	}
+}
+
+// CampingFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func CampingFromString(c string) Camping {
+	switch c {
+	case "kill":
+		return Camping_kill
+	case "skip":
+		return Camping_skip
+	case "damage":
+		return Camping_damage
+
+	default:
+		return 0
+	}
+}
+
+type Camping_List struct{ capnp.List }
+
+func NewCamping_List(s *capnp.Segment, sz int32) (Camping_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return Camping_List{l.List}, err
+}
+
+func (l Camping_List) At(i int) Camping {
+	ul := capnp.UInt16List{List: l.List}
+	return Camping(ul.At(i))
+}
+
+func (l Camping_List) Set(i int, v Camping) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
+}
+
+type Metric uint16
+
+// Metric_TypeID is the unique identifier for the type Metric.
+const Metric_TypeID = 0x8b6867d6463986d6
+
+// Values of Metric.
+const (
+	Metric_manhattan Metric = 0
+	Metric_chebyshev Metric = 1
+)
+
+// String returns the enum's constant name.
+func (c Metric) String() string {
+	switch c {
+	case Metric_manhattan:
+		return "manhattan"
+	case Metric_chebyshev:
+		return "chebyshev"
+
+	default:
+		return ""
+	}
+}
+
+// MetricFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func MetricFromString(c string) Metric {
+	switch c {
+	case "manhattan":
+		return Metric_manhattan
+	case "chebyshev":
+		return Metric_chebyshev
+
+	default:
+		return 0
+	}
+}
+
+type Metric_List struct{ capnp.List }
+
+func NewMetric_List(s *capnp.Segment, sz int32) (Metric_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return Metric_List{l.List}, err
+}
+
+func (l Metric_List) At(i int) Metric {
+	ul := capnp.UInt16List{List: l.List}
+	return Metric(ul.At(i))
+}
+
+func (l Metric_List) Set(i int, v Metric) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
+}
+
+type Tiebreak uint16
+
+// Tiebreak_TypeID is the unique identifier for the type Tiebreak.
+const Tiebreak_TypeID = 0x92dc0cd1e6a7abbd
+
+// Values of Tiebreak.
+const (
+	Tiebreak_none        Tiebreak = 0
+	Tiebreak_totalHealth Tiebreak = 1
+	Tiebreak_damageDealt Tiebreak = 2
+)
+
+// String returns the enum's constant name.
+func (c Tiebreak) String() string {
+	switch c {
+	case Tiebreak_none:
+		return "none"
+	case Tiebreak_totalHealth:
+		return "totalHealth"
+	case Tiebreak_damageDealt:
+		return "damageDealt"
+
+	default:
+		return ""
+	}
+}
+
+// TiebreakFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func TiebreakFromString(c string) Tiebreak {
+	switch c {
+	case "none":
+		return Tiebreak_none
+	case "totalHealth":
+		return Tiebreak_totalHealth
+	case "damageDealt":
+		return Tiebreak_damageDealt
+
+	default:
+		return 0
 	}
-	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
-	return ss, err
 }
 
-func (s InitialBoard) Cells() (CellType_List, error) {
-	p, err := s.Struct.Ptr(1)
-	return CellType_List{List: p.List()}, err
+type Tiebreak_List struct{ capnp.List }
+
+func NewTiebreak_List(s *capnp.Segment, sz int32) (Tiebreak_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return Tiebreak_List{l.List}, err
 }
 
-func (s InitialBoard) HasCells() bool {
-	p, err := s.Struct.Ptr(1)
-	return p.IsValid() || err != nil
+func (l Tiebreak_List) At(i int) Tiebreak {
+	ul := capnp.UInt16List{List: l.List}
+	return Tiebreak(ul.At(i))
 }
 
-func (s InitialBoard) SetCells(v CellType_List) error {
-	return s.Struct.SetPtr(1, v.List.ToPtr())
+func (l Tiebreak_List) Set(i int, v Tiebreak) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
 }
 
-// NewCells sets the cells field to a newly
-// allocated CellType_List, preferring placement in s's segment.
-func (s InitialBoard) NewCells(n int32) (CellType_List, error) {
-	l, err := NewCellType_List(s.Struct.Segment(), n)
-	if err != nil {
-		return CellType_List{}, err
+type Penalty uint16
+
+// Penalty_TypeID is the unique identifier for the type Penalty.
+const Penalty_TypeID = 0x97f9f2fdd64e601b
+
+// Values of Penalty.
+const (
+	Penalty_none   Penalty = 0
+	Penalty_count  Penalty = 1
+	Penalty_damage Penalty = 2
+)
+
+// String returns the enum's constant name.
+func (c Penalty) String() string {
+	switch c {
+	case Penalty_none:
+		return "none"
+	case Penalty_count:
+		return "count"
+	case Penalty_damage:
+		return "damage"
+
+	default:
+		return ""
 	}
-	err = s.Struct.SetPtr(1, l.List.ToPtr())
-	return l, err
 }
 
-// InitialBoard_List is a list of InitialBoard.
-type InitialBoard_List struct{ capnp.List }
+// PenaltyFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func PenaltyFromString(c string) Penalty {
+	switch c {
+	case "none":
+		return Penalty_none
+	case "count":
+		return Penalty_count
+	case "damage":
+		return Penalty_damage
 
-// NewInitialBoard creates a new list of InitialBoard.
-func NewInitialBoard_List(s *capnp.Segment, sz int32) (InitialBoard_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
-	return InitialBoard_List{l}, err
+	default:
+		return 0
+	}
 }
 
-func (s InitialBoard_List) At(i int) InitialBoard { return InitialBoard{s.List.Struct(i)} }
-
-func (s InitialBoard_List) Set(i int, v InitialBoard) error { return s.List.SetStruct(i, v.Struct) }
+type Penalty_List struct{ capnp.List }
 
-// InitialBoard_Promise is a wrapper for a InitialBoard promised by a client call.
-type InitialBoard_Promise struct{ *capnp.Pipeline }
+func NewPenalty_List(s *capnp.Segment, sz int32) (Penalty_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return Penalty_List{l.List}, err
+}
 
-func (p InitialBoard_Promise) Struct() (InitialBoard, error) {
-	s, err := p.Pipeline.Struct()
-	return InitialBoard{s}, err
+func (l Penalty_List) At(i int) Penalty {
+	ul := capnp.UInt16List{List: l.List}
+	return Penalty(ul.At(i))
 }
 
-func (p InitialBoard_Promise) Board() Board_Promise {
-	return Board_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+func (l Penalty_List) Set(i int, v Penalty) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
 }
 
 type Robot struct{ capnp.Struct }
@@ -812,6 +2110,22 @@ func (s Robot) SetFaction(v Faction) {
 	s.Struct.SetUint16(10, uint16(v))
 }
 
+func (s Robot) Player() uint8 {
+	return s.Struct.Uint8(12)
+}
+
+func (s Robot) SetPlayer(v uint8) {
+	s.Struct.SetUint8(12, v)
+}
+
+func (s Robot) Entering() Direction {
+	return Direction(s.Struct.Uint16(14) ^ 4)
+}
+
+func (s Robot) SetEntering(v Direction) {
+	s.Struct.SetUint16(14, uint16(v)^4)
+}
+
 // Robot_List is a list of Robot.
 type Robot_List struct{ capnp.List }
 
@@ -825,6 +2139,11 @@ func (s Robot_List) At(i int) Robot { return Robot{s.List.Struct(i)} }
 
 func (s Robot_List) Set(i int, v Robot) error { return s.List.SetStruct(i, v.Struct) }
 
+func (s Robot_List) String() string {
+	str, _ := text.MarshalList(0xa1f5501bdc903810, s.List)
+	return str
+}
+
 // Robot_Promise is a wrapper for a Robot promised by a client call.
 type Robot_Promise struct{ *capnp.Pipeline }
 
@@ -839,12 +2158,12 @@ type Replay struct{ capnp.Struct }
 const Replay_TypeID = 0xb1b85070ccf68de1
 
 func NewReplay(s *capnp.Segment) (Replay, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 3})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4})
 	return Replay{st}, err
 }
 
 func NewRootReplay(s *capnp.Segment) (Replay, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 3})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4})
 	return Replay{st}, err
 }
 
@@ -874,11 +2193,7 @@ func (s Replay) GameIdBytes() ([]byte, error) {
 }
 
 func (s Replay) SetGameId(v string) error {
-	t, err := capnp.NewText(s.Struct.Segment(), v)
-	if err != nil {
-		return err
-	}
-	return s.Struct.SetPtr(0, t.List.ToPtr())
+	return s.Struct.SetText(0, v)
 }
 
 func (s Replay) Initial() (InitialBoard, error) {
@@ -931,12 +2246,45 @@ func (s Replay) NewRounds(n int32) (Replay_Round_List, error) {
 	return l, err
 }
 
+func (s Replay) Seed() int64 {
+	return int64(s.Struct.Uint64(0))
+}
+
+func (s Replay) SetSeed(v int64) {
+	s.Struct.SetUint64(0, uint64(v))
+}
+
+func (s Replay) Rules() (RuleSet, error) {
+	p, err := s.Struct.Ptr(3)
+	return RuleSet{Struct: p.Struct()}, err
+}
+
+func (s Replay) HasRules() bool {
+	p, err := s.Struct.Ptr(3)
+	return p.IsValid() || err != nil
+}
+
+func (s Replay) SetRules(v RuleSet) error {
+	return s.Struct.SetPtr(3, v.Struct.ToPtr())
+}
+
+// NewRules sets the rules field to a newly
+// allocated RuleSet struct, preferring placement in s's segment.
+func (s Replay) NewRules() (RuleSet, error) {
+	ss, err := NewRuleSet(s.Struct.Segment())
+	if err != nil {
+		return RuleSet{}, err
+	}
+	err = s.Struct.SetPtr(3, ss.Struct.ToPtr())
+	return ss, err
+}
+
 // Replay_List is a list of Replay.
 type Replay_List struct{ capnp.List }
 
 // NewReplay creates a new list of Replay.
 func NewReplay_List(s *capnp.Segment, sz int32) (Replay_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 3}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 4}, sz)
 	return Replay_List{l}, err
 }
 
@@ -944,6 +2292,11 @@ func (s Replay_List) At(i int) Replay { return Replay{s.List.Struct(i)} }
 
 func (s Replay_List) Set(i int, v Replay) error { return s.List.SetStruct(i, v.Struct) }
 
+func (s Replay_List) String() string {
+	str, _ := text.MarshalList(0xb1b85070ccf68de1, s.List)
+	return str
+}
+
 // Replay_Promise is a wrapper for a Replay promised by a client call.
 type Replay_Promise struct{ *capnp.Pipeline }
 
@@ -956,18 +2309,22 @@ func (p Replay_Promise) Initial() InitialBoard_Promise {
 	return InitialBoard_Promise{Pipeline: p.Pipeline.GetPipeline(1)}
 }
 
+func (p Replay_Promise) Rules() RuleSet_Promise {
+	return RuleSet_Promise{Pipeline: p.Pipeline.GetPipeline(3)}
+}
+
 type Replay_Round struct{ capnp.Struct }
 
 // Replay_Round_TypeID is the unique identifier for the type Replay_Round.
 const Replay_Round_TypeID = 0xa37a83b5e914a8c4
 
 func NewReplay_Round(s *capnp.Segment) (Replay_Round, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 5})
 	return Replay_Round{st}, err
 }
 
 func NewRootReplay_Round(s *capnp.Segment) (Replay_Round, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 5})
 	return Replay_Round{st}, err
 }
 
@@ -1031,12 +2388,87 @@ func (s Replay_Round) NewEndBoard() (Board, error) {
 	return ss, err
 }
 
+func (s Replay_Round) Events() (Event_List, error) {
+	p, err := s.Struct.Ptr(2)
+	return Event_List{List: p.List()}, err
+}
+
+func (s Replay_Round) HasEvents() bool {
+	p, err := s.Struct.Ptr(2)
+	return p.IsValid() || err != nil
+}
+
+func (s Replay_Round) SetEvents(v Event_List) error {
+	return s.Struct.SetPtr(2, v.List.ToPtr())
+}
+
+// NewEvents sets the events field to a newly
+// allocated Event_List, preferring placement in s's segment.
+func (s Replay_Round) NewEvents(n int32) (Event_List, error) {
+	l, err := NewEvent_List(s.Struct.Segment(), n)
+	if err != nil {
+		return Event_List{}, err
+	}
+	err = s.Struct.SetPtr(2, l.List.ToPtr())
+	return l, err
+}
+
+func (s Replay_Round) Latencies() (capnp.UInt32List, error) {
+	p, err := s.Struct.Ptr(3)
+	return capnp.UInt32List{List: p.List()}, err
+}
+
+func (s Replay_Round) HasLatencies() bool {
+	p, err := s.Struct.Ptr(3)
+	return p.IsValid() || err != nil
+}
+
+func (s Replay_Round) SetLatencies(v capnp.UInt32List) error {
+	return s.Struct.SetPtr(3, v.List.ToPtr())
+}
+
+// NewLatencies sets the latencies field to a newly
+// allocated capnp.UInt32List, preferring placement in s's segment.
+func (s Replay_Round) NewLatencies(n int32) (capnp.UInt32List, error) {
+	l, err := capnp.NewUInt32List(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.UInt32List{}, err
+	}
+	err = s.Struct.SetPtr(3, l.List.ToPtr())
+	return l, err
+}
+
+func (s Replay_Round) TimedOut() (capnp.BitList, error) {
+	p, err := s.Struct.Ptr(4)
+	return capnp.BitList{List: p.List()}, err
+}
+
+func (s Replay_Round) HasTimedOut() bool {
+	p, err := s.Struct.Ptr(4)
+	return p.IsValid() || err != nil
+}
+
+func (s Replay_Round) SetTimedOut(v capnp.BitList) error {
+	return s.Struct.SetPtr(4, v.List.ToPtr())
+}
+
+// NewTimedOut sets the timedOut field to a newly
+// allocated capnp.BitList, preferring placement in s's segment.
+func (s Replay_Round) NewTimedOut(n int32) (capnp.BitList, error) {
+	l, err := capnp.NewBitList(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.BitList{}, err
+	}
+	err = s.Struct.SetPtr(4, l.List.ToPtr())
+	return l, err
+}
+
 // Replay_Round_List is a list of Replay_Round.
 type Replay_Round_List struct{ capnp.List }
 
 // NewReplay_Round creates a new list of Replay_Round.
 func NewReplay_Round_List(s *capnp.Segment, sz int32) (Replay_Round_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 5}, sz)
 	return Replay_Round_List{l}, err
 }
 
@@ -1044,6 +2476,11 @@ func (s Replay_Round_List) At(i int) Replay_Round { return Replay_Round{s.List.S
 
 func (s Replay_Round_List) Set(i int, v Replay_Round) error { return s.List.SetStruct(i, v.Struct) }
 
+func (s Replay_Round_List) String() string {
+	str, _ := text.MarshalList(0xa37a83b5e914a8c4, s.List)
+	return str
+}
+
 // Replay_Round_Promise is a wrapper for a Replay_Round promised by a client call.
 type Replay_Round_Promise struct{ *capnp.Pipeline }
 
@@ -1056,12 +2493,251 @@ func (p Replay_Round_Promise) EndBoard() Board_Promise {
 	return Board_Promise{Pipeline: p.Pipeline.GetPipeline(1)}
 }
 
+type Event struct{ capnp.Struct }
+
+// Event_TypeID is the unique identifier for the type Event.
+const Event_TypeID = 0xc44a8444f392469f
+
+func NewEvent(s *capnp.Segment) (Event, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 0})
+	return Event{st}, err
+}
+
+func NewRootEvent(s *capnp.Segment) (Event, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 24, PointerCount: 0})
+	return Event{st}, err
+}
+
+func ReadRootEvent(msg *capnp.Message) (Event, error) {
+	root, err := msg.RootPtr()
+	return Event{root.Struct()}, err
+}
+
+func (s Event) String() string {
+	str, _ := text.Marshal(0xc44a8444f392469f, s.Struct)
+	return str
+}
+
+func (s Event) Type() EventType {
+	return EventType(s.Struct.Uint16(0))
+}
+
+func (s Event) SetType(v EventType) {
+	s.Struct.SetUint16(0, uint16(v))
+}
+
+func (s Event) Robot() uint32 {
+	return s.Struct.Uint32(4)
+}
+
+func (s Event) SetRobot(v uint32) {
+	s.Struct.SetUint32(4, v)
+}
+
+func (s Event) Other() uint32 {
+	return s.Struct.Uint32(8)
+}
+
+func (s Event) SetOther(v uint32) {
+	s.Struct.SetUint32(8, v)
+}
+
+func (s Event) X() uint16 {
+	return s.Struct.Uint16(2)
+}
+
+func (s Event) SetX(v uint16) {
+	s.Struct.SetUint16(2, v)
+}
+
+func (s Event) Y() uint16 {
+	return s.Struct.Uint16(12)
+}
+
+func (s Event) SetY(v uint16) {
+	s.Struct.SetUint16(12, v)
+}
+
+func (s Event) TargetX() int16 {
+	return int16(s.Struct.Uint16(14))
+}
+
+func (s Event) SetTargetX(v int16) {
+	s.Struct.SetUint16(14, uint16(v))
+}
+
+func (s Event) TargetY() int16 {
+	return int16(s.Struct.Uint16(16))
+}
+
+func (s Event) SetTargetY(v int16) {
+	s.Struct.SetUint16(16, uint16(v))
+}
+
+func (s Event) Damage() int32 {
+	return int32(s.Struct.Uint32(20))
+}
+
+func (s Event) SetDamage(v int32) {
+	s.Struct.SetUint32(20, uint32(v))
+}
+
+// Event_List is a list of Event.
+type Event_List struct{ capnp.List }
+
+// NewEvent creates a new list of Event.
+func NewEvent_List(s *capnp.Segment, sz int32) (Event_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 24, PointerCount: 0}, sz)
+	return Event_List{l}, err
+}
+
+func (s Event_List) At(i int) Event { return Event{s.List.Struct(i)} }
+
+func (s Event_List) Set(i int, v Event) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Event_List) String() string {
+	str, _ := text.MarshalList(0xc44a8444f392469f, s.List)
+	return str
+}
+
+// Event_Promise is a wrapper for a Event promised by a client call.
+type Event_Promise struct{ *capnp.Pipeline }
+
+func (p Event_Promise) Struct() (Event, error) {
+	s, err := p.Pipeline.Struct()
+	return Event{s}, err
+}
+
+type EventType uint16
+
+// EventType_TypeID is the unique identifier for the type EventType.
+const EventType_TypeID = 0xb971078763280b2f
+
+// Values of EventType.
+const (
+	EventType_unknown        EventType = 0
+	EventType_moved          EventType = 1
+	EventType_blocked        EventType = 2
+	EventType_collided       EventType = 3
+	EventType_attacked       EventType = 4
+	EventType_damaged        EventType = 5
+	EventType_guarded        EventType = 6
+	EventType_selfDestructed EventType = 7
+	EventType_died           EventType = 8
+	EventType_spawned        EventType = 9
+	EventType_illegalMove    EventType = 10
+	EventType_illegalAttack  EventType = 11
+	EventType_slowed         EventType = 12
+	EventType_healed         EventType = 13
+	EventType_rangedAttacked EventType = 14
+)
+
+// String returns the enum's constant name.
+func (c EventType) String() string {
+	switch c {
+	case EventType_unknown:
+		return "unknown"
+	case EventType_moved:
+		return "moved"
+	case EventType_blocked:
+		return "blocked"
+	case EventType_collided:
+		return "collided"
+	case EventType_attacked:
+		return "attacked"
+	case EventType_damaged:
+		return "damaged"
+	case EventType_guarded:
+		return "guarded"
+	case EventType_selfDestructed:
+		return "selfDestructed"
+	case EventType_died:
+		return "died"
+	case EventType_spawned:
+		return "spawned"
+	case EventType_illegalMove:
+		return "illegalMove"
+	case EventType_illegalAttack:
+		return "illegalAttack"
+	case EventType_slowed:
+		return "slowed"
+	case EventType_healed:
+		return "healed"
+	case EventType_rangedAttacked:
+		return "rangedAttacked"
+
+	default:
+		return ""
+	}
+}
+
+// EventTypeFromString returns the enum value with a name,
+// or the zero value if there's no such value.
+func EventTypeFromString(c string) EventType {
+	switch c {
+	case "unknown":
+		return EventType_unknown
+	case "moved":
+		return EventType_moved
+	case "blocked":
+		return EventType_blocked
+	case "collided":
+		return EventType_collided
+	case "attacked":
+		return EventType_attacked
+	case "damaged":
+		return EventType_damaged
+	case "guarded":
+		return EventType_guarded
+	case "selfDestructed":
+		return EventType_selfDestructed
+	case "died":
+		return EventType_died
+	case "spawned":
+		return EventType_spawned
+	case "illegalMove":
+		return EventType_illegalMove
+	case "illegalAttack":
+		return EventType_illegalAttack
+	case "slowed":
+		return EventType_slowed
+	case "healed":
+		return EventType_healed
+	case "rangedAttacked":
+		return EventType_rangedAttacked
+
+	default:
+		return 0
+	}
+}
+
+type EventType_List struct{ capnp.List }
+
+func NewEventType_List(s *capnp.Segment, sz int32) (EventType_List, error) {
+	l, err := capnp.NewUInt16List(s, sz)
+	return EventType_List{l.List}, err
+}
+
+func (l EventType_List) At(i int) EventType {
+	ul := capnp.UInt16List{List: l.List}
+	return EventType(ul.At(i))
+}
+
+func (l EventType_List) Set(i int, v EventType) {
+	ul := capnp.UInt16List{List: l.List}
+	ul.Set(i, uint16(v))
+}
+
 type Faction uint16
 
+// Faction_TypeID is the unique identifier for the type Faction.
+const Faction_TypeID = 0xf4110aa7cb359a55
+
 // Values of Faction.
 const (
 	Faction_mine     Faction = 0
 	Faction_opponent Faction = 1
+	Faction_ally     Faction = 2
 )
 
 // String returns the enum's constant name.
@@ -1071,6 +2747,8 @@ func (c Faction) String() string {
 		return "mine"
 	case Faction_opponent:
 		return "opponent"
+	case Faction_ally:
+		return "ally"
 
 	default:
 		return ""
@@ -1085,6 +2763,8 @@ func FactionFromString(c string) Faction {
 		return Faction_mine
 	case "opponent":
 		return Faction_opponent
+	case "ally":
+		return Faction_ally
 
 	default:
 		return 0
@@ -1117,10 +2797,12 @@ const (
 	Turn_Which_attack       Turn_Which = 2
 	Turn_Which_selfDestruct Turn_Which = 3
 	Turn_Which_guard        Turn_Which = 4
+	Turn_Which_heal         Turn_Which = 5
+	Turn_Which_rangedAttack Turn_Which = 6
 )
 
 func (w Turn_Which) String() string {
-	const s = "waitmoveattackselfDestructguard"
+	const s = "waitmoveattackselfDestructguardhealrangedAttack"
 	switch w {
 	case Turn_Which_wait:
 		return s[0:4]
@@ -1132,6 +2814,10 @@ func (w Turn_Which) String() string {
 		return s[14:26]
 	case Turn_Which_guard:
 		return s[26:31]
+	case Turn_Which_heal:
+		return s[31:35]
+	case Turn_Which_rangedAttack:
+		return s[35:47]
 
 	}
 	return "Turn_Which(" + strconv.FormatUint(uint64(w), 10) + ")"
@@ -1177,6 +2863,9 @@ func (s Turn) SetWait() {
 }
 
 func (s Turn) Move() Direction {
+	if s.Struct.Uint16(0) != 1 {
+		panic("Which() != move")
+	}
 	return Direction(s.Struct.Uint16(2))
 }
 
@@ -1186,6 +2875,9 @@ func (s Turn) SetMove(v Direction) {
 }
 
 func (s Turn) Attack() Direction {
+	if s.Struct.Uint16(0) != 2 {
+		panic("Which() != attack")
+	}
 	return Direction(s.Struct.Uint16(2))
 }
 
@@ -1204,6 +2896,30 @@ func (s Turn) SetGuard() {
 
 }
 
+func (s Turn) Heal() Direction {
+	if s.Struct.Uint16(0) != 5 {
+		panic("Which() != heal")
+	}
+	return Direction(s.Struct.Uint16(2))
+}
+
+func (s Turn) SetHeal(v Direction) {
+	s.Struct.SetUint16(0, 5)
+	s.Struct.SetUint16(2, uint16(v))
+}
+
+func (s Turn) RangedAttack() Direction {
+	if s.Struct.Uint16(0) != 6 {
+		panic("Which() != rangedAttack")
+	}
+	return Direction(s.Struct.Uint16(2))
+}
+
+func (s Turn) SetRangedAttack(v Direction) {
+	s.Struct.SetUint16(0, 6)
+	s.Struct.SetUint16(2, uint16(v))
+}
+
 // Turn_List is a list of Turn.
 type Turn_List struct{ capnp.List }
 
@@ -1217,6 +2933,11 @@ func (s Turn_List) At(i int) Turn { return Turn{s.List.Struct(i)} }
 
 func (s Turn_List) Set(i int, v Turn) error { return s.List.SetStruct(i, v.Struct) }
 
+func (s Turn_List) String() string {
+	str, _ := text.MarshalList(0x812bccd38a6bb1d6, s.List)
+	return str
+}
+
 // Turn_Promise is a wrapper for a Turn promised by a client call.
 type Turn_Promise struct{ *capnp.Pipeline }
 
@@ -1227,6 +2948,9 @@ func (p Turn_Promise) Struct() (Turn, error) {
 
 type Direction uint16
 
+// Direction_TypeID is the unique identifier for the type Direction.
+const Direction_TypeID = 0xf170f8946262e9ff
+
 // Values of Direction.
 const (
 	Direction_north Direction = 0
@@ -1294,11 +3018,18 @@ func (l Direction_List) Set(i int, v Direction) {
 
 type CellType uint16
 
+// CellType_TypeID is the unique identifier for the type CellType.
+const CellType_TypeID = 0x9d1e08507e51e6ed
+
 // Values of CellType.
 const (
 	CellType_invalid CellType = 0
 	CellType_valid   CellType = 1
 	CellType_spawn   CellType = 2
+	CellType_hazard  CellType = 3
+	CellType_cover   CellType = 4
+	CellType_slow    CellType = 5
+	CellType_healing CellType = 6
 )
 
 // String returns the enum's constant name.
@@ -1310,6 +3041,14 @@ func (c CellType) String() string {
 		return "valid"
 	case CellType_spawn:
 		return "spawn"
+	case CellType_hazard:
+		return "hazard"
+	case CellType_cover:
+		return "cover"
+	case CellType_slow:
+		return "slow"
+	case CellType_healing:
+		return "healing"
 
 	default:
 		return ""
@@ -1326,6 +3065,14 @@ func CellTypeFromString(c string) CellType {
 		return CellType_valid
 	case "spawn":
 		return CellType_spawn
+	case "hazard":
+		return CellType_hazard
+	case "cover":
+		return CellType_cover
+	case "slow":
+		return CellType_slow
+	case "healing":
+		return CellType_healing
 
 	default:
 		return 0
@@ -1349,112 +3096,293 @@ func (l CellType_List) Set(i int, v CellType) {
 	ul.Set(i, uint16(v))
 }
 
-const schema_834c2fcbeb96c6bd = "x\xda\x84Vml\x14U\x17>\xe7\xde\x99\x9dm\xdf" +
-	"\xee\xbb\x1df\x9b\x17^-\x1b\x130R\xa5\xd2\"\x91" +
-	"4\x9a\x96R\x846\xa0{\x9b\x1ac\xa2?fw\x07" +
-	":t;\xb3\xcc\xccR*b\xe5\xc3\xa4\xa8%`\xc0" +
-	"\x00\xd2HAB14\xa4\x06\x0c\x8a\xfe0~D\x81" +
-	"\xf8\x03\xbfbbL4\xa0T\xc2\x0f\x8d\x10\x13\x941" +
-	"wf?\x86\xdd\x8a\x7f\xda\xbbw\xce<\xe7\x9c\xe7<" +
-	"\xcf\xbd\xb3\xa0\x83\xb6\x91&\xf1\xe7\x10\x00K\x88!\xf7" +
-	"\xeb\xc9\xbe\x17\xbf8\x7f\xeff`\x11D\xf7\xfdO^" +
-	"\xbdr\xee\xfe\x95[\xa1C\x12\x01\x94'\xe9+\x8aJ" +
-	"%\x80\x85O\xd3'\x10\xd0\x1d\xbex\xe8\xf2\xf0Sw" +
-	"\x8f\x80\\\x8b\x00\"\xf2G\x13B5\x02*\xa7\x84V" +
-	"@\xf7\xc67\xdb>\xfd\xcf\x95wv\x05\x03.\x09\x84" +
-	"\x07Ly\x01\x9d\xbf\xbcwm\xe3\xa5\xdf\xf6\x80\x1c\x09" +
-	"\xa4\x13\x89\x04\xa0T\x89?*u\"_\xc9\xe2\x00\xa0" +
-	";\xe3\xd0\xb9\x0f\xef<)\xec\x059BK\xb1\x80\xca" +
-	":\xf1\xac\xb2\xc9\x0b\x1c\x14\x97+c|\xe5^\xfd\x89" +
-	"=\x97\x08\xcf\x1e\x059Bn\x09\xde.\xbe\xa5\xec\xf2" +
-	"\x82G\xc4\xc7\x00\xdd\xed;\xae\xbft\xa6i\xe6\xc1\xe9" +
-	"*\x18\x13/(\x13^\xec1\xaf\x82\xda\xc5;\xbf\xbb" +
-	"#qm\x8c\xb3\x13@\x15xD]h\xbfR\x1f\xe2" +
-	"\x0d\xce\x0a\xc599\x1f\x8d\xc7\xa6Nm}\xe60\xc8" +
-	"1t\x7f\x18\xb9~>\x9b8=\x99\x07\x9e']P" +
-	"\x16I|\xd5$q\xe0\x8b\xf3\xd6^\xfe~\xf6\x96\x13" +
-	" \xcfB\xf0\xf0\x16\x8eI]\x08B\xe9\xc5[\xcb\xa3" +
-	"^\x03\xd2!e\x8f\xf4?\x00eT:\x01\xe8\x1e\x9d" +
-	"=t$ws\xfc\xfct\xad,\x0b\x9fUX\x98\xaf" +
-	"V\x85y\xc6\xf8\xebo\x9f\xda\xf89\xfd\xb2\x82\xcc\x89" +
-	"\xf0\x16e\xd2\x0b\x9c\x08/W\xbe\xe5+\xf75\xfd\xcc" +
-	"\x8e-\x877}U\xa6\x0a\x1f\xf9\x83\xf0~\xe53\x1e" +
-	"\xb6\xf0\xe3\xb0\xd7\xb8;\x95L\xee\xfe#\xfbk\x05\xf5" +
-	"\x97\xaa\xdeU\xaeV\xf1w\xa6\xaa\x86\x00\xdd\xc7\xf7/" +
-	":w\xb4Z\xfe\xbd\"\xb0\xbe\xfaMen5\x0f\xbc" +
-	"\xab\xfaA\x98\xef&MG\xcd\xea\x8d)T\xb3F\xb6" +
-	"\xa5'g\xa1\x91@d3\xa9\x10A\xd7\x15\x10@\xde" +
-	"\xd7\x00\xc0vSd\x07\x09\xd6\x93\x9b.\xc6\x90o\x8f" +
-	"\xf2\xed\xbd\x14\xd9\x1b\x04\xeb\xe9_|\x9b\x00\xc8c-" +
-	"\x00\xec\x00E6N\xb0\x16cH\x01\xe4#\xff\x07`" +
-	"\x07)\xb2\xe3\x04#\xc2\x9fn\x0c\x05\x00\xf9\xd8Z\x00" +
-	"6N\x91\x9d$\x18\x11o\xb81\x14\x01\xe4\xc9f\x00" +
-	"v\x9c\";M0:\xa0\xea\x0e\x84\xa2\xfd\xe6z\x0d" +
-	"\xa3\xa5\xee\x011\x0a\xd8\xaa:\x8e\x9a\xea\xab|@\xf5" +
-	"4\x86\x81`\x18\xd0\xb5\xb5\xcc\xea\x0e\xcdv j\xe5" +
-	"R\x0e\x84\xe2kr\xaa\x95\x86P\xb1q\xea5\xbeD" +
-	"ot\xd4>\xad'g\x19s\xba5;\x97ql\x00" +
-	"&P\x01\xc0\xe3 \xc2\xab\x0aSds\x08\xc6\x9d\x9c" +
-	"e\xd8\xf8_\xc0\x04E\xac-\x19\x1b\x90o\xde\x069" +
-	"\xa1Zj\xbf=-n\x8c`<i\xaaV\x1akK" +
-	"\xbe\x01\xc4\xda\x00 \xf1\x00\x97\x9a\x86\xa1\xa5\x9cnm" +
-	"]N\xd2l\x87O+\\\xc4\x9b\x97\x04`\xf7Pd" +
-	"\x0f\x10\x941?\xa9&N\xff}\x14\xd9b\x82n\xca" +
-	"\xd2\xd2\x9a\xe1\xe8 \xa9\x19\x1bkK\xca\xf6\x93QU" +
-	"G\xb9$a@\x94+*X\xa2\xe7k0-\x00\x9e" +
-	"_\xa0\"@\xf1\xc0\xc1\x82\xe5d\xb9\x1d\x88,JC" +
-	")?\xbc\x0d\x13\x88e\x8a[\xaae\xe2\x99\x9e\xc1\xac" +
-	"\xc6qj<\x05\xd5\xb7{i\xeb\x9a\x01\x90x\x0c\x0d" +
-	"\xe9\xc6z5\xa3\xa7\xe3\xfe_;\xab\x0e\x18e5u" +
-	"\x1a\xba\xa3\xab\x99vS\xb50]\xc6\x09\xe7x\x0eE" +
-	"\xb6 \xc0\xc9\xfc\xe6<Q\x1d\x01\xe2\x8bf\xf4\xb9\x88" +
-	"\xa7\xb4L\xa68\xe8h\xe9\xe0+\x1b\xb4\xdfI\xb7\x99" +
-	"\xa4\xa67\x8eX1\xf5&\xce\xfc\x06\x8al\x1bOM" +
-	"\xfc\xd4\x9bg\x00\xb0g)\xb2a\x822\xa1\xbem^" +
-	"\xe0\x9b\xcfSd/\x13\x94\xa9\xe0\xfbf;\xf7\xd26" +
-	"\x8al'AY\x10}\xdb\x8c\xb4\x03\xb0a\x8al7" +
-	"\x09*\x1d7\xa0\x04\x04%@\x1c,\xacZ{55" +
-	"\xe3\xf4\"\x05\x82\x14ph\xb5\x9art\xd3\xc0h\xe9" +
-	"|\xf0\x1dSFf\xb7\x96\xcd\xa8\x83\x8d\xddf\xce\xf8" +
-	"G2\xdb\x02d>\xdc\x05\xc0\x1e\xa2\xc8V\x10\x8cs" +
-	"\xb3\xde\xc6\x1d\x9a\x91\xe6CJ\x03@%\xe3\xc5:\x84" +
-	"r\xa15\xe65Trg9\xfdZV\xca\xa8\x83L" +
-	"\xc0\xe0\xfd\x80\xcdq\xdeE\x9a\xd5\x14;X\xc6Im" +
-	"\xa3\xc8V\x06:\xe8\xe4\xa4vPd\x09>\x13\xe2\xcf" +
-	"d\x15\x8f\\A\x91\xa5\x09\xb6\xaeQ\xfb\xb5\xce4\xd6" +
-	"\x00\xc1\x1a\xc0!\xdd\xd7[\xa5[[-\x9e/@@" +
-	"\xb1\x982\xd5\xe4\xdd\x9c\xb7\xa3\x9a\xb1}/\xfd\x9b\x97" +
-	"\xdb\x03^\xb6\xb5\x94\xa59=&H}\x9aQ,-" +
-	"i:\x8f\xaa\xfdZ\xe1w\x19OK\xf4\x80e\x0b\x1f" +
-	"\x11X\xf8\xdc\x90\xe5. r\x95\xe4\x16\xce,\x00\x98" +
-	"\xce\xb6\xed\xa6J\xadt^\xec\x88\xbe\xd8\x9b\x03b'" +
-	"\xf9z7\xb7\x94\xc4\x8e\x05\xad\xb7\xe4\xb5~\x80\xcb:" +
-	"\x7fG\xeck.\xdd2\xfc\xde\xe0R\x1fm)]1" +
-	"\xf1\x01=\xed\xf4\x06\x94\xad\xaf\xe9u\x8a?-3i" +
-	":\x01\xd2\x8b\x9f\x13>\xe9qo((\x00A\x01\xca" +
-	"gY\xd6Z\x87n\xb5j\x9eO\xbc\xf6\xbc\x8a\x175" +
-	"{G\xd2|\xffH\x9a\xdb\x00\x80T\xae\xe7\xff\x04\xb9" +
-	"\xae\x01 n\x98\x96\xd3\x1b\xb7\xcd\x9c\xd3\x1b\xd5T\xdb" +
-	"\x89\x0eh\xb6\x135LC+C\x7fDME\x0b\xd8" +
-	"a\x0f[n\xf0\xb0\xab\xba\x00\xa2\xfd\xba\xa1\xb9f6" +
-	"k\x1a\x9a\xe1\x00\xc0\xdf\x01\x00\x00\xff\xff\xed\xdd\xb1\x07"
+const schema_834c2fcbeb96c6bd = "x\xda\xacY}p\x1c\xe5y\x7f\x9f}\xef\xf4H\xb6" +
+	"\xa4\xbb\xd7\xef\xd9\xb1l\xcb\xa7\xb48\xb5\xd5\xf0\xe1\x13" +
+	"\x9e\x82\x06\xf7,a\x81\xad\xc1A\xab\x133\xe0\x9a\xb6" +
+	"\xab\xbb\xd7\xd2\xdaw\xbb\xf2\xde\x9e\x8c\x1c\\\x05\x07R" +
+	"H0\xc5L\xdc\x02\xc5\x13L\xc6\x83M\xd1$08" +
+	"\x83\x09\xee\x90\x06\x12\x1al\x0a\x14\x08\xce\xd0i\xdc\xc1" +
+	"\x19H`\x12'\x98\xc1%\xb0\x9dgw\xeft\xa7\x93" +
+	"m\xfe\xe8\x1f\x8fV\xf7\xfe\xde}?\x9e\xef\xe7\xd9\xcb" +
+	"v5\xad\xd1VF\xbf7\x871\xfd\xd6h\x83\xf7\xe6" +
+	"\x13[\xbf\xf9\x9f\xc7\xfe\xfc6\xa6\xb7\x00xG\x7f\xf2" +
+	"\x8f\xbfy\xe9\xd2\xeb\xbe\xce\xd6\"2&WG\xef\x93" +
+	"}Q$\xea\xea\x8b\xfe\x14\x18\x93&\xa2\xf7\xfc\xa5M" +
+	"\x9fL\xfd\xe5\xaao0\x11\x07\xc6\xa2\x80\x8cu\xdd\x80" +
+	"\x8b@*\xc4\x90\xd2\x8c\xc9C\x88\xde\x7f_w\xd9\xc0" +
+	"k\x7f\xf5\xfe]\xb4\xfc\xdc\xe9\xe5#\xb4\xfa^|L" +
+	"\xeeC$\xea\xda\x87\xb1(c\xf2d\x0bzo~\xe3" +
+	"\xcak\xde\x1c\x19\xfd\x16\x13-\xda\xf4\x1b\x0c\xe4\xf1\x96" +
+	"G\xe4\x1b-\x18\xd2_0&\xa1\x15\xbd;\xdfy\xe4" +
+	"\xdd;7}i7\xd3\xe3P9\xcd\x07-s@~" +
+	"\xda\x82!mgL\xeeiE\xef\x93\x9f\xdf\xfe\xe2\xdc" +
+	"\xdf\x1c\xd9S}\xf0\x9d\xad\x1a\xc8\xbbZ1$:\xf8" +
+	"\xf1V\xf4\x8e\xfe\xcb\xa3\xbfz\xb5\xf9\xed\xfb\xeaNq" +
+	"\xa4\xf5I\xf9\xa3V\x0c\xe9z:E\x0c=\xf7\xd6\x1f" +
+	"|\xf1\xc3m\x17\xed\xad\x9b\xfeA\xeb/\xe4\xd9V\x0c" +
+	"\x89\xa6_\x19Co\xfd\xaf\x9f=\xf3\xd5S\xbf\xdf;" +
+	"\x83\xebQ\x8d\xf8\xb2,\xf6?re\x0cC\xfa\x1ec" +
+	"2\x1aGo\xf1\xdf~\xe5\xcdO\x7f\x7f\xf6\x9f\xeav" +
+	"8\x1d{L\x9e\x8daH\xb4\xc3\xca8z\xf3\x1ey" +
+	"\xe9\xc7K\x9e\x8a\xdc\xcfD\x0b\xaf\x99\xde\x1e\xff\x99\\" +
+	"\x11\xc7\x90\xae\x95*\x8eD\xde\x07\xbf\xd2\xffn\xa0q" +
+	"\xe9\xbe\xba\xf5\xf5\xf8\x93\xf2\xa68\x86D\xc79\x19G" +
+	"\xef\xae{>\xfa\xd6\x0fW.|x\xe6\x0d|\xc9\x1e" +
+	"\x8f\xbf&O\xc4\x91\xa8\xebD\xdc\xd7\x9bv\x89^\xfc" +
+	"\x8a{\xdf^<pf?\xbd\xa3\xcdP\x86&\xf9\xa0" +
+	"\x14\x12\x89\xba\x84\xfc\x07z\xe5d\x02\xbd\xe7\x0f&\xde" +
+	";\xfc\xf5\x1d\xdfe\"\x01\xde\xc9\xdd\x1f\x1d\x1b\x1bx" +
+	"\xfa\x09\x16\x8d\xfa\xbb$^\x93'\x12H\xd4u\"\x91" +
+	"\xa4W&\x16\xa0\xf7\xf2_\xffM\xf2\x17;\x9b\x0eV" +
+	"\x0bY-\x98\x03\xb2\xb4\x00C\"!\x1fY\x80\xd3\x0b" +
+	"\xcev\x89\x03\x0b\x1e\x91S\x0b\xbe@\x13\xbb\x8e,\xf0" +
+	"\x97o_\x88\xde\xa5s\x97g\xff\x1e\xb7\x1d\xa9\xe3S" +
+	"\xd3\xc2g\xa4X\x88D]b\xa1M\xf3\xf56\xf4\x8c" +
+	"\x17\xff\xb0y\xd7g\xfd\xcf\xd4\xcd_\xdd\xf6\xa4\xeck" +
+	"\xc3\x90&\xe9Hm\xe8\xfd\xe9\xbb\xdb\xae\xdby\xed\x96" +
+	"\xa3u\xd3\x0f\xb4=#\xa7\xda0\xa4\x11\xd2\xbbE\xe8" +
+	"}\xe7\x9a\xfb\xfe\xb0\xf6\xf6\xfe\xe7\xe9\x06|\x06K?" +
+	"h{P\x9eiC\xa2\xae3m\xbe\x14\xce.F\xef" +
+	"\xd1\xa5\x93\x07J\x9f\x1d<\xc6DK\x9d\xea\x9dZ\xfc" +
+	"3yz1\x86Df\xb3m\x09z\x07^^\x9d\xbb" +
+	"\xfe'\xc3\xff1\xab\xb6\xde\xbc\xe4\xdf\xa4Z\x82D]" +
+	"j\x89\xcf\xa6\xe3\xed\xe8\xfd\xfc\xb9\xc4C?\x9e:\xf1" +
+	"\xea\x8cw\xfc\x83\x1di\xff\xb5|\xa1\x1dC\"a4" +
+	"-E/\xf9\x9d\x1f\x1c\xfe\xea\xcb\xfc\xf5:\x8d=\xd3" +
+	"\xbeK\x9em\xc7\x90~*\x8f,E\"\xef\x9f\xcd\x1f" +
+	"\xde\xb3\xeb\xbb;\xdf\x98\xf5X\x07\x96>(\xa7\x96\"" +
+	"Q\xd7\xd4R\xffX\xd0\x81^n\xe1G\xa7\xff\xec\x91" +
+	"\x17\xdf\x0c\x94#\xe2\xfb\x8a\xe4<\x90\x9f&\xb1L\xc4" +
+	"\xa6$z\x1d\xa9}\xff\xba\xe7\xf2/\xfd\xb6j\xe6\xa9" +
+	"\xe4\x9f\x80<\x93\xc421&O'\xd1\xbb\xe7\x98\xf3" +
+	"\xfc\xd3+?\xfcm\x9d\xc8\xfe+\xf9\x98<\x95\xc4\x90" +
+	"\xc82\xdb;\xd0\xf3\xde\x1b\x1e\xfe\xf6\xc7c\xa7\xeb\x15" +
+	"\xa8\xe3\x19):0$R\x88m\x1d\xe8\xdd\xf0\xe0\xaa" +
+	"\x97\x1e\x9d#>\xac\x9b~s\xc7cRu`H\xb4" +
+	"\xfa\xfe\x0e\xf4\x86m\xd7\x183/\xc9\x821f\x8du" +
+	"\x0f\x95\x1c\xb0\x06\x00\x06@\xd3;x\xa4\x05</\x02" +
+	"\x8c\x89W;\xc5\xab\xa8\xbf\xc2A\x7f[\x83v\xed3" +
+	"\x0f\x12@\xe3':\xc5\x09\xd4\xdf\xe2\xa0\xbf\xafA;" +
+	"\xff\x94\xc65\xc6\xc4{\xdd\xe2=\xd4\xdf\xe5\xa0\xffQ" +
+	"\x838$\x803&\xce.\x12gQ\xff\x98C&\x02" +
+	"\x1a\xb4D\xfe\xe8% B|\x86-2\x0a\x98\x89\x00" +
+	"\x87L\x9c\xa0\xe8'^\x02\xc8\xbf\xb7@J\xb6\x00f" +
+	"\x9a\x09Z\x08\x1a\xb47\xfc/\xed\xd1\xc0\x98\x9c\x0f\x9d" +
+	"r>`&A\xd8r\xc2\xf0,a\xbeK\x84-r" +
+	"\x05`f9aW\x81\x06\xb1\xed\x86\xe9\x0e\x80\xc6\x1a" +
+	"b\x05{\\\x0d\xd0\xd04g\x19[\x03\x02p@\x03" +
+	"\x881H\x1b\xaekd\xb7\x9ew\x0e7s\x8472" +
+	"\"\xf0\x8a*\xbfy\xad*\xba,\xe6\x94\xb2\xc1>\xc9" +
+	"\x91\x92\xe1\xe4\x82-G\x95\x91?\xefr\x9ecX#" +
+	"*\xd7\xe3\xb2\xd8\x05\xb7^\x03\x15\xa1q_h=\xe6" +
+	"%#FAe\\\xc3qU\xee\xa2\x01\xc31x\xa1" +
+	"\x18J1\xc2#\x8c\xf92lI\x89\x16\xd4\x9b9\xe8" +
+	"\x1d\x1a$\x87\xed\xe0t\x10\x9fv\xcdU\xfb\xc4k\xf6" +
+	"\x09\x94c\xb0\x94\x8f\xa9\x8cr\xc3\x957\x05+\x03\xc8" +
+	"\x92\xe6\xc8\x09\x0d3\xb7h\x1c2\xb7k\x1a\x08\x80\x04" +
+	"\x10r\x9b\xb6K\xde\xa1a\xe6vB\xee%D\xd3\x12" +
+	"\xa0\x01\xc8\xdd\xda\x16\xb9G\xc3\xcc\xbd\x84<D\x08\xe7" +
+	"$I\x90\x0fh;\xe4>\x0d3\x0f\x11r\x90\x90\x88" +
+	"\x96\x80\x08\x80<\xa0\xed\x92\x874\xcc\x1c$\xe4)B" +
+	"\xa2\x0d\x09\x88\x02\xc8'\xb4\x8d\xf2\xb0\x86\x99\xa7\x08y" +
+	"\x8e\x90\x06L@\x03\x80<\xaam\x94?\xd20\xf3\x1c" +
+	"!\xc7\x08\xc1\xc6\x04 \x80\xfcwmP\x1e\xd70s" +
+	"\x8c\x90\xb74\x0dV6v@\x02\x1a\x01\xe4\x1b\xda\x16" +
+	"yB\xc3\xcc[\x04\xbdC/5\xc9\x044\x01\xc8\x93" +
+	"\xda\x0eyJ\xc3\xcc;\x84|L\xc8\x9c9\x09\x98\x03" +
+	" \xcfh\x8e<\xaba\xe6cB\"\\\x83\x95s\xbf" +
+	"\x08\x09\x98K\x0a\xce\xbf)\x9b8f\x1a9\x87L\x82" +
+	"k \x9a\x17$\xa0\x991)\xb8#\xe7s\xcc$\x08" +
+	"YNH\xcb\x17\x12\xd0B\x1a\xccw\xc8\x15\x1c3\xcb" +
+	"\x09\xb9\x8a\x90\xd6\xe6\x04\xb4R>\xc0\xb7\xc8\xd5\x1c3" +
+	"W\x11\xb2\x8e\x90\xd8\xa2\x04\xc4\x18\x93}|\x8b\\\xcf" +
+	"1\xb3\x8e\x90M\x84\xc4[\x13\x10\x07\x907\xf1-\xf2" +
+	"f\x8e\x99M\x84\x8c\x12\"\x1a\x13 \x00\xa4\xe2\xbb\xa4" +
+	"\xc913J\x88K\xc8\xbcX\x02\xe6\x01\xc8m|\xa3" +
+	",q\xcc\xb8\x84|\x8d\x109/\x01\x921\xb9\x93\xf7" +
+	"\xcb\xdb8f\xbeF\xc8\xdd\x84$d\x02\x12\x8c\xc9\xbb" +
+	"\xf8\x16\xb9\x9bc\xe6nB\xee'd\xfe\xe2\x04\xcc\xa7" +
+	"\xe4\x8do\x91\x0fp\xcc\xdcO\xc8\xe3\x84,H$`" +
+	"\x01\x80<\xc4\x1d9\xc51\xf38!Os\x0d<\xd3" +
+	"2]\xd3\xc8\xafcIe\xe4\xdd\xd1\x01\xd0 \xc24" +
+	"\x11I1\xf0\xb2v>o\x16M\x1b\xac\xb5F\xc1\x18" +
+	"Q\xac\x82F\x19x\x81\xe5\xae5X\x8c\xb0\x0a4\x87" +
+	"\x81\x97SE\x97\x8c\x93\xa5\xd7\x1a5 \x99\x9fo\xab" +
+	"\x1bJyp\xcd\xb1\xbc\xa9\x1c\x7f\xd5\xb9L\x83\xb9\xcf" +
+	"\xfd2\xcd\x98W\x1c3\xb6[}\xe3\x8aqg\xa2f" +
+	"U\x1f\xb8\xc1r\x197\xf3\x15 \xc7\xc0+\x18\xb7\x0c" +
+	"\xda%+\xc7\xa0X3\xbe\xd91\x95\x95\xcbO\xb0\xd8" +
+	"5\xa6\xe3\x1f\x02\x98\x16\x05`\xe0\x99\xf9\xbc\x1a1\xf2" +
+	"\x03,\xad,#\xefN\x04^\xa0\x92\xc7M[g4" +
+	"V5}-K\xd6\xde\x87\xf8\xa0\xac\xdc\xf5V_\xde" +
+	"\x84\x82i\x19\xaei[\x8c\x85[\xf9;m6\x9d\xa2" +
+	";d*\x96\x1cv\x94\x11\xba\x9bJ\x06;\xc35\x15" +
+	"U\xd6\xb6rC&K\xab\xcf1{\x9c\x84c\x0d\x1a" +
+	",\x963K\xe5\xabC\xa4\x82lP,\xe6:f6" +
+	"X\xa5\x92\xbb\xcfXe\xd4\xd8a8\xb9:9F}" +
+	"\x05\x18W\xceyDE\xbe\xb6\xa7`\x97\x18\xb7\xdcZ" +
+	"\xdd\xc8\xe7'\xd6)#\xcf\x18\xab>V\xe0ygl" +
+	"\x05\x91\xb2l\xaf6\x0a,6fZ#\xc1\x81+\xb1" +
+	"{\xc6\x81\xb3F\x81&\xd5\x09c\xce,~t\x83r" +
+	"\xd1\xe7\x80\xefF\x1b\xfd\x80)\x06\xc5|\x04\x08\x9e^" +
+	"\xc1\xb0FI\x95\x19\x05c\xcd\xcb\x8e\xaa\xe1\x89\xe2\xa8" +
+	"b0>\x00\xdal\xfe\xdf5\xb6\xaa\xa1\x92c]4" +
+	"\xa8\x8a\xa5\xbc[d\xac\xbcz\xc5\xfd\xafH\x89\x15\xa8" +
+	"/\xe7\xa0_\xa7\x01\x84\xf1{}\xafX\x8f\xfa:\x0e" +
+	"\xfa\x90\x06I\xb7\xe4X\xbe\xc8Z\x19\x0cp\x80\xf8t" +
+	"\xb5Wu\xddV\x06\x93Y\xc7(\x8e\xaabU\x08<" +
+	"\xef\xa9($\x15\x8a\xec\xff?$\x0d\x99\xaa\xac\xc2\xfe" +
+	"\xd2\xcd>3\xdb;E;1\xb3m\x98\x9eZ\xf0\x8c" +
+	"Y\xb6Er\xf1\\\xdb5\xf2\xeb\x94\xc10\xf0-^" +
+	"\xce\x17\xd9Z\xc5\xd0\xc8\xbb\xb5\x0c\xd6\xfc]\xae\xb6-" +
+	"Ke\xdd\x8ck\xb8\xbcT\xac\xddjQ\xb8U\x7f\xb8" +
+	"\xd5\xa0hGn\x93\x91x\xc3Fn\xc8\xde\xaa\xac@" +
+	"\xe1<\xcb(\xa8!c\xab\x0a\x84z\xaeM\x06\xd5\xb6" +
+	"\x12\xaab9\xc86Wx\xd57\\\x16\xd5\xa6r\x80" +
+	"eL\xdc\xb4H\xdc\x84\xfa\x8d\x1c\xf4\xbc\x06\xa0\x05\xd9" +
+	"\x97\xb9Q\x14P\xcfs\xd0o\xd1\xc0\xcb:*\xa7," +
+	"\xd7\xa4\xeb\x15\x03\xf6VR\xfaZ\xf6r\xc3$\\L" +
+	"\xa7\xd6U\xb8\xf0m\xcf\xca\x96\x1cG\x95m+\xf4'" +
+	"ub\x19PV,\xf0`\xb3I%%\xda\x88U\xf3" +
+	"\xbb\xc5\xfc\x8aT\x92Y\xbb\xe4\xaf\x99\xce\x95\x0d\xa8\x8e" +
+	"C=f\xc8#\xdba\xd3\xba\x14e\xacR\x1eC\xb9" +
+	"\x8a\x10\xa2W\x08\xec\x89CO\x02D\x1bNf\x83\x17" +
+	"\x07@\x0bn\x13\xfc]\x03\x03P\x7f\xf8\xabU>\x99" +
+	"\x1f\x9a\x18S\xe1\x16K\xfc\xd3o\xe8\x15\x1b\xe8\xf4\xeb" +
+	"Sb=\x9d\xbe/%\xfa\x10\xb8\xe8\xe9\x16=\x08\x11" +
+	"\xb1:%V#D\xc5\x95\x9d\xe2J\x84\x06\xb1\xaaW" +
+	"\xac\xc2I\xd3\x1a7\xf2~\xaa\x98\xac\xfc\xe3\xbb\x15\xba" +
+	"i\xe0\xe4\x82\xcb\x8f+g\x00\xb4X1oo\x1f\x00" +
+	"m\x92\x9cX\xe0s\xea\xb8\xb0>\x08\x8e\xbd\xb6\xe1@" +
+	"n:U\x0f\xb5\xe4\xd5T9Q\x7f\xa7JKN\xa6" +
+	"\xc4I\xd4\x7f\x19f\xdeA\x0e\xe6'\xde)\x09\x80\x83" +
+	"\x94$\xfbY\x19\xf7\x13u)\xa0\xbf6\xb3\xaed`" +
+	"\xcb\xa0W.\x03\xcc\\D\xc8e\x84Dy\x90\x81]" +
+	"\x0c\xdd\xf2b\xc0\xcc\x97\x09\xb9\x82\x90\x86H\x90\xa7\xaf" +
+	"\x82\x94\\\x05\x98\xb9\x9c\x905\x84 \x0f\xb2\xf4\xd50" +
+	",{\x003k\x08\xd9\x04\xb5.\xa0R\xad\xd5\xeah" +
+	"2\xab\xf2\xf9j'\x15\x9b\xeeD\xd4:\xa9\xa4S\xca" +
+	"\xabP\xe1+m\xa5\xda\xc5\xbc\xe2D\xa1\xa0\\g\"" +
+	"\x8c\x09\xb1\xe9\xea\xbb\xd6\xbfO\x8e\xe5\x8d\x09\xe5\xf8\xab" +
+	"50-\xda\xa01H\x07c\x95!`\x90t\x95Q" +
+	"(O\x82\x86r\x0c\x19\xb4K\x0c\xad\\\xf5\xa9)\xc6" +
+	"\xb4\xce\x96c\xdb\xc3\xdc.\x1b\xff\x92\x8aX\x0f/\x12" +
+	"\x87Q\x7f\x8a\x83\xfe\x1c\x89U\x0b\xc4zt\x9e8\x8a" +
+	"\xfa\xb3\x1c\xf4\x17I\xaa<\xb0\xfe\x17\xe6\x89\x17P\x7f" +
+	"\x9e\x83\xfe\x8a\x06\x82G\x82\xe2\xebx\xb78\x8e\xfa1" +
+	"\x0e\xfa[$\xcf\xa8_z\x897z\xc5\x1b\xa8\xbf\xce" +
+	"A\x7f\x97d\xd9\xecW]\xe2T\xb78\x85\xfa;\x1c" +
+	"\xf4\xdfMg\xd2\xe2\x83~q\x1a\xf5\xdf\x85JT[" +
+	"\x00\xc1-\xf4\x03\x19\x11\xc0D\xd5\x8f\xf4h%\x87\xe3" +
+	"\x8c\x08&7\x1bYJI\x02\x86W\xca\xd5\x195X" +
+	"\x0ds}V*\xcbU\x8ei\x8dTd5K}\x14" +
+	"\x8dE\x18\xd4\x99\xcc\xa0\xa2\xd5.\xa1|\xacb2\x89" +
+	"\x0aow\xa6\xc4N\xd4o\xe5\xa0?\\e2\xfb\xfa" +
+	"\xc5~\xd4\x1f\xe6\xa0?5m1\xe2\x89n\xf1\x04\xea" +
+	"\xdf\xe7\xa0\xbf^\xaeX\xc8\xe8\x06\xcb\\$~E\"" +
+	"\x01o\xa7\xf9\xd5L\xaaMe\xe7\xe7\x0a\xae\x94\xbc\x91" +
+	"u\xe7\xc2\x8b\x9e\xd3\x14\xd2j\\Yn\xed\x9a\x95\xfe" +
+	"\xce\x8c5\xf3\x86\xab\xac\xac\xa9\x18T\xcfo\x0c\xb4\xd0" +
+	"s\xcd\x82\xca]_r\xc3\x1dC\x14\xeat\xb4\xa6\xde" +
+	"\xec\xb3ra\xb5Y(\xb2Yb{wUlO;" +
+	"~^\x12\\\xa7\xd2\x1e\xbaP\xbd\xa9\xc60oL\xe8" +
+	"\x11\x80\xaa\xbe\x1e\xa4\x92~b=C\x88\xdde!\xde" +
+	"Y%\xc4;z\xc5\x1d\xa8\xdf\xceA\xbf\xbfJ\x88{" +
+	"\xbb\xc5^\xd4\xbf\xcdA\x7f\xba\xe2\xf3\xc4\xe1\xcej\x0b" +
+	"\x8b\xf0@\x86GSe\x0b{E\x834\xddz=m" +
+	"\x0c\xcd\x8c\x08&\xc3J\xe5\xbcIK\xda\xa1\xe3\xd6\x8a" +
+	"\xa9r\x9dZ1\xc5\x8aJ\xf9\xebG\x19\xd1\xe7qa" +
+	"u\\\xeb\x1bWi\xcb\xad\x8a_W\xf9\x97\x9e\xea\x15" +
+	"S\x08 \x0e\xa5\xc4!\x8a_\x07z\xc5\x01\x8a_\xfb" +
+	"\xfb\xe9\x19\x09\x9fQ\xb1\xbfW\xec\xa7\xf8\xb5\xafW\xec" +
+	"C@\xf1\xc0\x0ez6\x8a\x07:\xc5\x03\x08Mbo" +
+	"\xaf\xd8\x8b0G\xec\x19\xa6\xe7\\\xb1\xc7\xa1g\xb3\xd8" +
+	"\xd3-\xf6 \xb4\x88\xdd\xddb7B\xab\xb8k\x87\xd8" +
+	"\x8d\x93%k\xabe\xfb\xd1\xce\xd7\x7f\xba\xdb\xe4p\xde" +
+	"\xcen\xf5\xff\x0d\x8a\xb9\x9c\x0a5=\xac\xde\xca?'" +
+	"\x83L\xc0\x7f\xc7\xaf\xcf\x82w*\xad\x964Us\xfe" +
+	"X,g\x06\xd3|o\x1bL\x0b\xab\xa3\x0d\x0c\x83v" +
+	"Oy\xa0\x87%\xcb\xdd\x9d4E[\x7f\xb6\xef\xa4\x82" +
+	"\xf7*\x8d\x98tp\x96\xda\x10\x1c\xb083QH\xfa" +
+	"a\xa3\xecK|\x0e\xaf\xdaH\x91\x1f\xc4\xaa~zj" +
+	"\xe1o\x1e\xfe\x8e\x88U\x83\xe2J\xf4Fm\xc7\xdca" +
+	"S\xc1\xe8\xab\x8d7\xae\x1c\xd7\xcc\x96\xab\x12\xcf\xb1\xdd" +
+	"\xa0^\x0b\xe1\x9ci\x8c\xd8V\x05\xdeV\xa2\x1e\x8f\xa3" +
+	"\x18\xccz\xb0>+\x97\x1eTF\xd1.\xf7\xf0\xe2\xfe" +
+	"\xc9Vl\x14\x17\xd3\xc9V\x0c\xd3S\x13+z\xc5\x0a" +
+	":\xd9\xb2^\xb1\x0c=_=\xaf3\x0b\x8c\xfb\x1d2" +
+	"O\xe5M\xbfjd\x188\xe9\xc9\xcd\xb6\xb3Y\xf9\xd8" +
+	"\xa41l;\xee\xecL\xe9\x1bW~V83!\xe9" +
+	"<oB\xa2\xbf_e\x98\xef\xa5\xca]\xc3\x0f\xc9\xbb" +
+	"\x86m\xc3\xd3\xf3B?J\xbdD\x11i\x08,\xf3\xec" +
+	"\xbc\xeaf\xa2\x88b\xd00\x04\xe8\xad\xa44\xcd~\x16" +
+	"\xd2\x18d!M\xd0+\x9b\x003\x8d\xe5dG`4" +
+	"\xc8B\x04tK\x01\x98\x89\x13\xb2\x84\"\x8b\xeb\x1b\x0f" +
+	"\x85\x98J\xf3\xbe6:%\x1d{\xd8v\xab\xe2_\xd2" +
+	"vG\x95S5p\xee\x808\xe9\x1a\xce\x88ro\xac" +
+	"\x8e\x88\xc1\xd0MUC\xd3ip\xb9\x1c\xad/\x18\xc2" +
+	"\xbc\xde\xc8\xcfZ\xee\x91\xac\xf5/s\xd0\xaf\xa8\xe2\xbb" +
+	"\x9f\x95\xea\x97s\xd0\xd7h~e\xef(w\xc8f\xb8" +
+	"UY\xd5nm\xd8v\xbfb\x14T\xd5P\xdd\xee\xd7" +
+	"\x1a\x05\x15\x14\x9b\xec<\x11\xf5\xde\xe9R\xd3w\x0c\xfa" +
+	"\xdda\x98-\x8b|_\xb7\xd8\x87\xfaC\x1c\xf4\x83$" +
+	"r-\x10\xf9\x81~q\x08\xf5\x83\x1c\xf4gI\xe4\x10" +
+	"\x88\xfcH\xb78\x82\xfa\xd3A\xf0\xfd\x1c\xd5b\xda\x09" +
+	"M\x81$Y\xf9N2#\xcf\xd8nZVm\x9e\xe1" +
+	"\x9aA\x9f\xa3\x92g\x9c\xa3\xd5\x91.fmG\x9d?" +
+	"\xa9\x9bQ\xda\x15\xc7b\xb6UT\x17\x88\x95E\xd7p" +
+	"\x83\xd6Il\xfa;\xe19{\xc0\x10\xc6dV\xa9\x18" +
+	"\xa9\"*\x7f\xb9\x84\xf2\xe7N\xa1\xf7\x8b\x1b\xb0g\x08" +
+	"zn\x04q3\x02T\xbe\xcaB\xf9\xcb\x85\xd0\x87k" +
+	"\xa6h\x95OcP\xfe\x0c\"\xf4\xc1\xea)^\xb9\xc2" +
+	"\x0fxU[[y\xe5\xa64\xc3\xc0e\xcc\x02S\x0e" +
+	"\x11\xf8\xb2\x0b\xd7e\xbd\xb6\xc1\x9d\xaa\xf4\x0d`\x86\xb2" +
+	"Q\xe4\xd7\xca\x91\xbf\xbb\x1c\xf9\xef\x0db\xbcV\xa3\x82" +
+	"\xdf\x0f\xd5\x8a\x94m*%\xa6P\x7f<H\x07\xe2\xa1" +
+	"\xae\x1d\xee\xaeJ\x07\x92\xdb\xcd\x9c;Ze\xc3\xe9Q" +
+	"e\x8e\x8c\xba\xd5#\xbeG\xa8\x0d\xf2\x95\xcf\x973\xeb" +
+	"\x920})[v}^q\xfe\x94+0;\x08\x1b" +
+	"\x11\x03<r\xa1/\x02\xf4\x02\xe6\xddY_\x08k^" +
+	"\xa3\x106\xb9f+\xd8;\xab\x0b\xf6\xadf>\xef\x97" +
+	"\xaa[\xcd\xb1s\xd5\xeb\xc1\xa2kM'\xad\xc2T\xbf" +
+	":N\xa6\xc4*ZveJ\xac\xa4e/\xee\xa4\xa8" +
+	"\xc4\xc5\x8aN\x8aJ\x11\xb1\xacS,\xc3\xa4e;>" +
+	"\xcb\x93E\xbb\xe4\xff\x13S\x06uE\xb4\xd8v\x15<" +
+	"\xc3\xceA\xdd\xb6\xd7\x18\xd9X\xd5\xa63\xeeR\xee\xd3" +
+	"\xd0\x9db\x05\xd3_\xc2\xb3\xc7\xc6lKYa\xfe\x1b" +
+	"\xa3N\xa1\xbf\xf2\xff\x0d\x00\x0ex\xed\xec"
 
 func init() {
 	schemas.Register(schema_834c2fcbeb96c6bd,
 		0x812bccd38a6bb1d6,
+		0x86353eadfb092fc4,
+		0x89ec5bd250304cdf,
+		0x8b6867d6463986d6,
 		0x8d265c88e8a2e488,
 		0x91b9eb0bc884d7fb,
+		0x92dc0cd1e6a7abbd,
+		0x952471f421b67c74,
 		0x95f2e57bf5bcea49,
+		0x97f9f2fdd64e601b,
 		0x9804b41cc3cba212,
 		0x9d1e08507e51e6ed,
 		0xa01831bb8bf68e89,
 		0xa1f5501bdc903810,
 		0xa37a83b5e914a8c4,
-		0xaf821edee86a29e4,
+		0xa8097dda1f5f5ece,
 		0xb1b85070ccf68de1,
+		0xb971078763280b2f,
+		0xba4afe8266f3c861,
+		0xbd6a477d4c71e823,
+		0xc44a8444f392469f,
 		0xcca8fe75a57f1ea7,
+		0xcf62c64f643dcea5,
+		0xd1d9adc39c14c0d7,
 		0xd403ce7bb5b69f1f,
 		0xd57da3828ebb699b,
+		0xd6c8a227f1f61864,
+		0xef263491be9d3220,
+		0xeff431b8c472cc8e,
 		0xf170f8946262e9ff,
 		0xf4110aa7cb359a55)
 }
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/bcspragu/Gobots/botapi"
+	"github.com/bcspragu/Gobots/engine"
 	capnp "zombiezen.com/go/capnproto2"
 
 	bolt "go.etcd.io/bbolt"
@@ -24,14 +25,6 @@ var (
 	errGameNotFound            = errors.New("gobots: game not found")
 )
 
-type WinType int
-
-const (
-	P1Win WinType = iota
-	P2Win
-	Tie
-)
-
 type datastore interface {
 	// Users
 	createUser(u *userInfo) (id uID, err error)
@@ -46,11 +39,11 @@ type datastore interface {
 	matchHistory(id aiID) ([]*gameInfo, error)
 
 	// Games
-	startGame(ai1, ai2 aiID, init botapi.InitialBoard) (gameID, error)
+	startGame(ais []aiID, init botapi.InitialBoard, seed int64) (gameID, error)
 	addRound(id gameID, round botapi.Replay_Round) error
 	lookupGame(id gameID) (botapi.Replay, error)
 	lookupGameInfo(id gameID) (*gameInfo, error)
-	finishGame(id gameID, p1, p2 *aiInfo, info *gameInfo) error
+	finishGame(id gameID, info *gameInfo) error
 }
 
 type dbImpl struct {
@@ -132,12 +125,178 @@ type aiInfo struct {
 
 type gameInfo struct {
 	ID        gameID
-	AI1       *aiInfo
-	AI2       *aiInfo
-	AI1Score  int
-	AI2Score  int
 	StartTime time.Time
 	EndTime   time.Time
+
+	// Players holds how each AI in the game did, in faction order.
+	Players []*playerInfo
+
+	// Seed is the seed the board was created with, playing the same AIs with it
+	// again gives a rematch on the same spawns.
+	Seed int64
+
+	// Map is the name of the map the game was played on, or empty for the
+	// default map.
+	Map string
+
+	// Teams is the number of teams the players were split into, or 0 if
+	// everyone played for themselves.
+	Teams int
+
+	// Result is how the game ended as far as the AIs are concerned: played to
+	// the end, won because other AIs forfeited, or aborted by the server.
+	Result resultReason
+
+	// End is why the game ended. Games that are tied on score can still have a
+	// Winner, decided by Tiebreak. The Winner is the team that won in team
+	// games, the faction that won otherwise, or 0 for a tie.
+	End      engine.EndReason
+	Winner   int
+	Tiebreak engine.Tiebreak
+
+	// Games from before there could be more than two players only have these,
+	// see upgrade.
+	AI1      *aiInfo
+	AI2      *aiInfo
+	AI1Score int
+	AI2Score int
+}
+
+// resultReason is how a game ended as far as the AIs are concerned.
+type resultReason int
+
+const (
+	completed         resultReason = iota // The game was played to the end
+	forfeitDisconnect                     // An AI disconnected and didn't come back
+	forfeitTimeout                        // An AI ran out of time too many times
+	aborted                               // The server couldn't finish the game
+)
+
+var resultReasonNames = map[resultReason]string{
+	completed:         "completed",
+	forfeitDisconnect: "forfeit-disconnect",
+	forfeitTimeout:    "forfeit-timeout",
+	aborted:           "aborted",
+}
+
+func (r resultReason) String() string {
+	return resultReasonNames[r]
+}
+
+// playerInfo is how one AI did in a game.
+type playerInfo struct {
+	AI      *aiInfo
+	Faction int
+
+	// Team is the team the AI played on, which is its Faction when there are no
+	// teams.
+	Team int
+
+	// Score is the number of robots the AI had left at the end.
+	Score int
+
+	// Problems with the turns the AI sent, see engine.ValidateTurns.
+	Warnings turnWarnings
+
+	// The illegal actions the AI's robots tried, like walking into walls.
+	Illegal engine.IllegalActions
+
+	// The tiebreak values: the total health of the AI's robots at the end, and
+	// the damage they did to other AIs' robots.
+	Health int
+	Damage int
+
+	// Timeouts is how many turns the AI ran out of time on, and Forfeit is why
+	// the AI forfeited, or completed if it didn't.
+	Timeouts int
+	Forfeit  resultReason
+
+	// Crashes is how many times the AI's code crashed during the game, costing
+	// its robots their turns.
+	Crashes int
+}
+
+// upgrade fills in the Players and Winner of games from before there could be
+// more than two players.
+func (g *gameInfo) upgrade() {
+	if len(g.Players) > 0 || g.AI1 == nil || g.AI2 == nil {
+		return
+	}
+	g.Players = []*playerInfo{
+		{AI: g.AI1, Faction: engine.P1Faction, Team: engine.P1Faction, Score: g.AI1Score},
+		{AI: g.AI2, Faction: engine.P2Faction, Team: engine.P2Faction, Score: g.AI2Score},
+	}
+	switch {
+	case g.AI1Score > g.AI2Score:
+		g.Winner = engine.P1Faction
+	case g.AI2Score > g.AI1Score:
+		g.Winner = engine.P2Faction
+	}
+}
+
+// maxWarnings is how many warning messages are kept for each AI in a game, any
+// past that are only counted.
+const maxWarnings = 50
+
+// turnWarnings holds the problems found with the turns an AI sent in a game.
+type turnWarnings struct {
+	Count    int
+	Messages []string
+}
+
+func (w *turnWarnings) add(vs []engine.Violation) {
+	for _, v := range vs {
+		w.Count++
+		if len(w.Messages) < maxWarnings {
+			w.Messages = append(w.Messages, v.String())
+		}
+	}
+}
+
+// problem records something that went wrong with the AI's turn in the given
+// round as a whole, like running out of time.
+func (w *turnWarnings) problem(round int, msg string) {
+	w.Count++
+	if len(w.Messages) < maxWarnings {
+		w.Messages = append(w.Messages, fmt.Sprintf("round %d: %s", round, msg))
+	}
+}
+
+// Aborted reports whether the server couldn't finish the game.
+func (g *gameInfo) Aborted() bool {
+	return g.Result == aborted
+}
+
+// Forfeited reports whether the game was won because other AIs forfeited.
+func (g *gameInfo) Forfeited() bool {
+	return g.Result == forfeitDisconnect || g.Result == forfeitTimeout
+}
+
+// player returns how the given AI did in this game, or nil if it wasn't in it.
+func (g *gameInfo) player(id aiID) *playerInfo {
+	for _, p := range g.Players {
+		if p.AI.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// WarningsFor returns the warnings for the given AI in this game.
+func (g *gameInfo) WarningsFor(id aiID) turnWarnings {
+	if p := g.player(id); p != nil {
+		return p.Warnings
+	}
+	return turnWarnings{}
+}
+
+// IllegalFor returns the illegal actions the given AI's robots tried in this
+// game.
+func (g *gameInfo) IllegalFor(id aiID) engine.IllegalActions {
+	if p := g.player(id); p != nil {
+		return p.Illegal
+	}
+	return engine.IllegalActions{}
 }
 
 // ByTime implements sort.Interface for a []*gameInfo based on the StartTime
@@ -148,20 +307,52 @@ func (g ByTime) Len() int           { return len(g) }
 func (g ByTime) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
 func (g ByTime) Less(i, j int) bool { return g[i].StartTime.Unix() > g[j].StartTime.Unix() }
 
-func (g *gameInfo) GameResult() WinType {
-	switch {
-	case g.AI1Score > g.AI2Score:
-		return P1Win
-	case g.AI1Score < g.AI2Score:
-		return P2Win
+// WinnerAIs returns the AIs that won the game, which is more than one in team
+// games, or nil if it was a tie.
+func (g *gameInfo) WinnerAIs() []*aiInfo {
+	if g.Winner == 0 {
+		return nil
 	}
-	return Tie
+	var ais []*aiInfo
+	for _, p := range g.Players {
+		if p.team() == g.Winner {
+			ais = append(ais, p.AI)
+		}
+	}
+	return ais
+}
+
+// Sides returns the players on each side of the game in order, which is one
+// player per side unless there were teams.
+func (g *gameInfo) Sides() [][]*playerInfo {
+	var sides [][]*playerInfo
+	for _, p := range g.Players {
+		if n := len(sides); n > 0 && sides[n-1][0].team() == p.team() {
+			sides[n-1] = append(sides[n-1], p)
+		} else {
+			sides = append(sides, []*playerInfo{p})
+		}
+	}
+	return sides
+}
+
+// team returns the team the player was on, for games from before there were
+// teams too.
+func (p *playerInfo) team() int {
+	if p.Team == 0 {
+		return p.Faction
+	}
+	return p.Team
 }
 
 type aiStats struct {
 	Wins   int
 	Losses int
 	Ties   int
+
+	// Forfeits are the games the AI lost by forfeiting, which aren't counted
+	// in Losses.
+	Forfeits int
 }
 
 func (db *dbImpl) createUser(uInfo *userInfo) (id uID, err error) {
@@ -343,7 +534,8 @@ func (db *dbImpl) matchHistory(id aiID) ([]*gameInfo, error) {
 			if err := dec.Decode(&g); err != nil {
 				return err
 			}
-			if g.AI1.ID == id || g.AI2.ID == id {
+			g.upgrade()
+			if g.player(id) != nil {
 				g.ID = gameID(k)
 				gInfos = append(gInfos, &g)
 			}
@@ -355,7 +547,7 @@ func (db *dbImpl) matchHistory(id aiID) ([]*gameInfo, error) {
 }
 
 // Games
-func (db *dbImpl) startGame(ai1, ai2 aiID, init botapi.InitialBoard) (gameID, error) {
+func (db *dbImpl) startGame(ais []aiID, init botapi.InitialBoard, seed int64) (gameID, error) {
 	var gID gameID
 	err := db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(GameBucket)
@@ -375,6 +567,14 @@ func (db *dbImpl) startGame(ai1, ai2 aiID, init botapi.InitialBoard) (gameID, er
 		gID = gameID(strconv.FormatUint(idNum, 10))
 		r.SetGameId(string(gID))
 		r.SetInitial(init)
+		r.SetSeed(seed)
+		rules, err := init.Rules()
+		if err != nil {
+			return err
+		}
+		if err := r.SetRules(rules); err != nil {
+			return err
+		}
 
 		data, err := msg.Marshal()
 		if err != nil {
@@ -439,6 +639,14 @@ func addReplayRound(orig botapi.Replay, round botapi.Replay_Round) (*capnp.Messa
 	if err := newReplay.SetInitial(initBoard); err != nil {
 		return nil, err
 	}
+	newReplay.SetSeed(orig.Seed())
+	rules, err := orig.Rules()
+	if err != nil {
+		return nil, err
+	}
+	if err := newReplay.SetRules(rules); err != nil {
+		return nil, err
+	}
 	origRounds, err := orig.Rounds()
 	if err != nil {
 		return nil, err
@@ -488,38 +696,43 @@ func (db *dbImpl) lookupGameInfo(id gameID) (*gameInfo, error) {
 }
 
 // When we finish a game, we want to increment the win count of the winner and
-// the lose count of the loser, and make a game info entry
-func (db *dbImpl) finishGame(id gameID, p1, p2 *aiInfo, info *gameInfo) error {
+// the lose count of the losers (or the forfeit count, for the ones that
+// forfeited), and make a game info entry. Aborted games don't count.
+func (db *dbImpl) finishGame(id gameID, info *gameInfo) error {
 	err := db.Update(func(tx *bolt.Tx) error {
-		// Only update the stats if the bot didn't fight itself
-		if p1.ID != p2.ID {
-			p1Stat, err := aiStat(tx, p1.ID)
-			if err != nil {
-				return err
-			}
-
-			p2Stat, err := aiStat(tx, p2.ID)
-			if err != nil {
-				return err
+		// Each AI's stats only count the game once, even if it played itself,
+		// and only if it played someone else
+		var ids []aiID
+		seen := make(map[aiID]bool)
+		for _, p := range info.Players {
+			if !seen[p.AI.ID] {
+				seen[p.AI.ID] = true
+				ids = append(ids, p.AI.ID)
 			}
-
-			switch info.GameResult() {
-			case P1Win:
-				p1Stat.Wins++
-				p2Stat.Losses++
-			case P2Win:
-				p1Stat.Losses++
-				p2Stat.Wins++
-			case Tie:
-				p1Stat.Ties++
-				p2Stat.Ties++
-			}
-
-			if err := writeAiStats(tx, p1.ID, p1Stat); err != nil {
-				return err
+		}
+		if len(ids) > 1 && !info.Aborted() {
+			winners := make(map[aiID]bool)
+			for _, ai := range info.WinnerAIs() {
+				winners[ai.ID] = true
 			}
-			if err := writeAiStats(tx, p2.ID, p2Stat); err != nil {
-				return err
+			for _, aid := range ids {
+				stat, err := aiStat(tx, aid)
+				if err != nil {
+					return err
+				}
+				switch {
+				case info.Winner == 0:
+					stat.Ties++
+				case winners[aid]:
+					stat.Wins++
+				case info.player(aid).Forfeit != completed:
+					stat.Forfeits++
+				default:
+					stat.Losses++
+				}
+				if err := writeAiStats(tx, aid, stat); err != nil {
+					return err
+				}
 			}
 		}
 		return writeGameInfo(tx, id, info)
@@ -609,6 +822,7 @@ func game(tx *bolt.Tx, id gameID) (*gameInfo, error) {
 	if err := dec.Decode(&g); err != nil {
 		return &g, err
 	}
+	g.upgrade()
 	log.Printf("LookupGameInfo: Decoding from GameInfoBucket at [%s]=%v", id, g)
 
 	return &g, nil
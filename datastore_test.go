@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testDB returns a datastore in a temporary directory, which is removed when
+// the returned function is called.
+func testDB(t *testing.T) (*dbImpl, func()) {
+	dir, err := ioutil.TempDir("", "gobots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := initDB(filepath.Join(dir, "test.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal("initDB:", err)
+	}
+	db := ds.(*dbImpl)
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFinishGame(t *testing.T) {
+	db, done := testDB(t)
+	defer done()
+
+	const tok = accessToken("token")
+	if _, err := db.createUser(&userInfo{Name: "user", Token: tok}); err != nil {
+		t.Fatal("createUser:", err)
+	}
+	var a, b, c *aiInfo
+	for i, ai := range []**aiInfo{&a, &b, &c} {
+		name := fmt.Sprintf("bot %d", i)
+		id, err := db.createAI(&aiInfo{Name: name}, tok)
+		if err != nil {
+			t.Fatal("createAI:", err)
+		}
+		*ai = &aiInfo{ID: id, Name: name}
+	}
+
+	p := func(ai *aiInfo, faction, team int, forfeit resultReason) *playerInfo {
+		return &playerInfo{AI: ai, Faction: faction, Team: team, Forfeit: forfeit}
+	}
+	games := []*gameInfo{
+		// a beats b
+		{Players: []*playerInfo{p(a, 1, 1, completed), p(b, 2, 2, completed)}, Winner: 1},
+		// a and c tie
+		{Players: []*playerInfo{p(a, 1, 1, completed), p(c, 2, 2, completed)}},
+		// c wins because b ran out of time
+		{Players: []*playerInfo{p(b, 1, 1, forfeitTimeout), p(c, 2, 2, completed)}, Winner: 2, Result: forfeitTimeout},
+		// Aborted games don't count
+		{Players: []*playerInfo{p(a, 1, 1, completed), p(b, 2, 2, completed)}, Winner: 1, Result: aborted},
+		// Neither do games against yourself
+		{Players: []*playerInfo{p(a, 1, 1, completed), p(a, 2, 2, completed)}, Winner: 2},
+		// a and c beat b, who only counts the game once
+		{Players: []*playerInfo{p(a, 1, 1, completed), p(c, 2, 1, completed), p(b, 3, 2, completed), p(b, 4, 2, completed)}, Teams: 2, Winner: 1},
+	}
+	for i, g := range games {
+		gid := gameID(fmt.Sprint(i + 1))
+		if err := db.finishGame(gid, g); err != nil {
+			t.Fatalf("finishGame(%s): %v", gid, err)
+		}
+		info, err := db.lookupGameInfo(gid)
+		if err != nil {
+			t.Fatalf("lookupGameInfo(%s): %v", gid, err)
+		}
+		if info.Result != g.Result || info.Winner != g.Winner {
+			t.Errorf("game %s was recorded as %v won by %d; want %v won by %d", gid, info.Result, info.Winner, g.Result, g.Winner)
+		}
+	}
+
+	dir, err := db.loadDirectory()
+	if err != nil {
+		t.Fatal("loadDirectory:", err)
+	}
+	want := map[*aiInfo]aiStats{
+		a: {Wins: 2, Ties: 1},
+		b: {Losses: 2, Forfeits: 1},
+		c: {Wins: 2, Ties: 1},
+	}
+	for ai, stats := range want {
+		if got := dir.AIStats[ai.ID]; got == nil || !reflect.DeepEqual(*got, stats) {
+			t.Errorf("stats for %s = %+v; want %+v", ai.ID, got, stats)
+		}
+	}
+}
+
+func TestResultReason(t *testing.T) {
+	tests := []struct {
+		r         resultReason
+		name      string
+		aborted   bool
+		forfeited bool
+	}{
+		{completed, "completed", false, false},
+		{forfeitDisconnect, "forfeit-disconnect", false, true},
+		{forfeitTimeout, "forfeit-timeout", false, true},
+		{aborted, "aborted", true, false},
+	}
+	for _, test := range tests {
+		if got := test.r.String(); got != test.name {
+			t.Errorf("resultReason(%d).String() = %q; want %q", test.r, got, test.name)
+		}
+		g := &gameInfo{Result: test.r}
+		if g.Aborted() != test.aborted || g.Forfeited() != test.forfeited {
+			t.Errorf("%s: Aborted, Forfeited = %t, %t; want %t, %t", test.name, g.Aborted(), g.Forfeited(), test.aborted, test.forfeited)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package engine
 
 import (
-	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"strconv"
 
 	"github.com/bcspragu/Gobots/botapi"
@@ -12,14 +13,7 @@ const (
 	P1Faction = 1
 	P2Faction = 2
 
-	InitialHealth = 50
-
-	CollisionDamage = 5
-	AttackDamage    = 10
-	DestructDamage  = 15
-	SelfDamage      = 1000 // Make them super dead
-
-	NewBotsSpacing = 10 // Number of rounds to wait before spawning new bots
+	SelfDamage = 1000 // Make them super dead
 )
 
 type (
@@ -28,17 +22,17 @@ type (
 	CellInfo struct {
 		Bot      *Robot
 		CellType CellType
+		// Fogged is whether the cell is out of sight, see ViewAtXY.
+		Fogged bool
 	}
 
 	CellType   int
 	DamageType int
 
-	collisionMap map[Loc][]botMove
-
 	botMove struct {
 		Bot      *Robot
 		Location Loc
-		Turn     botapi.Turn
+		Turn     Turn
 	}
 )
 
@@ -48,53 +42,143 @@ const (
 	Attack
 	Destruct
 	Self
+	Penalized
+	Hazard
+	Ranged
+	Camped
 )
 
 const (
 	Invalid CellType = iota
 	Valid
 	Spawn
+
+	// The terrain cells are valid cells that do something to the robots on
+	// them, with the numbers coming from the RuleSet.
+
+	// HazardCell does HazardDamage to the robot on it at the end of each round.
+	HazardCell
+	// CoverCell scales attacks on the robot on it by CoverMultiplier.
+	CoverCell
+	// SlowCell takes two rounds of moving the same way to get into. The first
+	// round the robot stays put and is Entering the cell. Doing anything else
+	// the next round, or getting blocked, starts it over.
+	SlowCell
+	// HealingCell gives the robot on it HealAmount health back at the end of
+	// each round, up to InitialHealth.
+	HealingCell
 )
 
 var (
-	damageMap = map[DamageType]int{
-		Collision: CollisionDamage,
-		Attack:    AttackDamage,
-		Destruct:  DestructDamage,
-	}
-
 	cellToWire = map[CellType]botapi.CellType{
 		Invalid: botapi.CellType_invalid,
 		Valid:   botapi.CellType_valid,
 		Spawn:   botapi.CellType_spawn,
+
+		HazardCell:  botapi.CellType_hazard,
+		CoverCell:   botapi.CellType_cover,
+		SlowCell:    botapi.CellType_slow,
+		HealingCell: botapi.CellType_healing,
 	}
 
 	cellFromWire = map[botapi.CellType]CellType{
 		botapi.CellType_invalid: Invalid,
 		botapi.CellType_valid:   Valid,
 		botapi.CellType_spawn:   Spawn,
+
+		botapi.CellType_hazard:  HazardCell,
+		botapi.CellType_cover:   CoverCell,
+		botapi.CellType_slow:    SlowCell,
+		botapi.CellType_healing: HealingCell,
 	}
 )
 
 type Board struct {
+	// Locs holds every robot on the board. It's kept in sync with the indices
+	// below, so it should only be changed through Set.
 	Locs map[Loc]*Robot
 
 	Cells [][]CellType
 	Size  Loc
 	Round int
+	Rules RuleSet
+
+	// Symmetry is how the spawn points on each player's part mirror each
+	// other.
+	Symmetry Symmetry
+
+	// Factions is the number of players, who play as factions 1 through
+	// Factions.
+	Factions int
+
+	// Teams is the number of teams the factions are split into, see Team, or 0
+	// if every faction plays for itself.
+	Teams int
 
 	NextID RobotID
 
-	s Spawner
-	c Typer
+	// Illegal counts the illegal actions each faction has tried so far, unless
+	// the rules say not to.
+	Illegal map[int]IllegalActions
+
+	// DamageDealt is how much damage each faction has done to robots on other
+	// sides so far.
+	DamageDealt map[int]int
+
+	// Forfeited holds the factions that have forfeited the game, see Forfeit.
+	Forfeited map[int]bool
+
+	// eliminated is set once a side has been wiped out, if the rules end the
+	// game for it.
+	eliminated bool
 
-	leftSpawns []Loc
+	s        Spawner
+	c        Typer
+	schedule Schedule
+	src      *source
+	rand     *rand.Rand
+
+	// spawns are the spawn points on the first player's half of the board.
+	spawns []Loc
+
+	// ids indexes where each robot is, and grid holds the robot in each cell
+	// (at x*Size.Y+y), so that looking robots up doesn't mean scanning Locs.
+	ids  map[RobotID]Loc
+	grid []*Robot
+
+	// events holds what's happened so far in the round being played.
+	events []Event
 }
 
 type BoardConfig struct {
 	Size      Loc
 	Spawner   Spawner
 	CellTyper Typer
+
+	// Rules are the rules the game is played with. Nil means DefaultRules. A
+	// Map's Terrain overrides them on the map.
+	Rules *RuleSet
+
+	// Symmetry is how the CellTyper's layout mirrors the first player's part of
+	// the board to get the others'. The zero value means Horizontal.
+	Symmetry Symmetry
+
+	// Factions is the number of players, which can't be more than the Symmetry
+	// has room for. The zero value means two.
+	Factions int
+
+	// Teams is the number of teams the players are split into, which has to
+	// divide Factions. The zero value means no teams.
+	Teams int
+
+	// Schedule decides which rounds robots spawn at the end of. The zero value
+	// means every SpawnEvery rounds before SpawnUntil, as set in the Rules.
+	Schedule Schedule
+
+	// Seed seeds the random source that the Spawner and CellTyper draw from.
+	// Two boards with the same config and seed play out identically given the
+	// same moves.
+	Seed int64
 }
 
 var DefaultConfig = BoardConfig{
@@ -112,16 +196,76 @@ func (b *Board) BotCount(faction int) (n int) {
 	return
 }
 
+// TeamCount returns the number of robots on the given team, which is the same
+// as BotCount when there are no teams.
+func (b *Board) TeamCount(team int) (n int) {
+	for _, bot := range b.Locs {
+		if b.Team(bot.Faction) == team {
+			n++
+		}
+	}
+	return
+}
+
+// Team returns the team a faction plays on. Teams are made of factions next to
+// each other in order, so with four factions and two teams, factions 1 and 2
+// are team 1, and 3 and 4 are team 2. Without teams, every faction is its own
+// team.
+func (b *Board) Team(faction int) int {
+	if b.Teams == 0 {
+		return faction
+	}
+	return (faction-1)*b.Teams/b.Factions + 1
+}
+
+// Sides returns the number of sides playing against each other, which is the
+// number of teams, or the number of factions when there are no teams.
+func (b *Board) Sides() int {
+	if b.Teams == 0 {
+		return b.Factions
+	}
+	return b.Teams
+}
+
+// allies reports whether two factions are on the same side. A Spectator isn't
+// on anyone's side.
+func (b *Board) allies(f1, f2 int) bool {
+	if f1 == Spectator || f2 == Spectator {
+		return false
+	}
+	return b.Team(f1) == b.Team(f2)
+}
+
 func (b *Board) CellsJS() [][]CellType {
 	return b.Cells
 }
 
-// EmptyBoard creates an empty board of the given size.
+// EmptyBoard creates an empty board of the given size, played with the given
+// rules.
 func EmptyBoard(bc BoardConfig) *Board {
 	b := &Board{
-		Locs:  make(map[Loc]*Robot),
-		Size:  bc.Size,
-		Cells: make([][]CellType, bc.Size.X),
+		Locs:     make(map[Loc]*Robot),
+		Size:     bc.Size,
+		Cells:    make([][]CellType, bc.Size.X),
+		Rules:    DefaultRules,
+		Symmetry: bc.Symmetry,
+		Factions: bc.Factions,
+		Teams:    bc.Teams,
+		schedule: bc.Schedule,
+		ids:      make(map[RobotID]Loc),
+		grid:     make([]*Robot, bc.Size.X*bc.Size.Y),
+	}
+	if bc.Rules != nil {
+		b.Rules = *bc.Rules
+	}
+	if m, ok := bc.CellTyper.(*Map); ok {
+		b.Rules = m.Terrain.apply(b.Rules)
+	}
+	if b.Symmetry == UnknownSymmetry {
+		b.Symmetry = Horizontal
+	}
+	if b.Factions == 0 {
+		b.Factions = 2
 	}
 
 	for i := 0; i < bc.Size.X; i++ {
@@ -132,19 +276,61 @@ func EmptyBoard(bc BoardConfig) *Board {
 }
 
 func (b *Board) InitBoard(bc BoardConfig) {
+	b.src = newSource(bc.Seed)
+	b.rand = rand.New(b.src)
 	b.s = bc.Spawner
 	b.c = bc.CellTyper
+	if st, ok := b.c.(SeededTyper); ok {
+		b.c = st.Seeded(b.rand)
+	}
 
 	for x := 0; x < b.Size.X; x++ {
 		for y := 0; y < b.Size.Y; y++ {
-			t := b.c.Type(x, y)
-			b.Cells[x][y] = t
-			if t == Spawn && x < b.Size.X/2 {
-				b.leftSpawns = append(b.leftSpawns, Loc{x, y})
-			}
+			b.Cells[x][y] = b.c.Type(x, y)
 		}
 	}
 	b.spawnBots()
+	// The starting robots are part of the initial board, not a round.
+	b.events = nil
+}
+
+// Clone returns a copy of the board that plays out exactly like b would, but
+// can be changed without affecting it. The cells are shared, since they never
+// change once the board is set up.
+func (b *Board) Clone() *Board {
+	c := *b
+	c.Locs = make(map[Loc]*Robot, len(b.Locs))
+	c.ids = make(map[RobotID]Loc, len(b.ids))
+	c.grid = make([]*Robot, b.Size.X*b.Size.Y)
+	for loc, r := range b.Locs {
+		rc := *r
+		c.put(loc, &rc)
+	}
+	if b.src != nil {
+		src := *b.src
+		c.src = &src
+		c.rand = rand.New(c.src)
+	}
+	if b.Illegal != nil {
+		c.Illegal = make(map[int]IllegalActions, len(b.Illegal))
+		for f, ia := range b.Illegal {
+			c.Illegal[f] = ia
+		}
+	}
+	if b.DamageDealt != nil {
+		c.DamageDealt = make(map[int]int, len(b.DamageDealt))
+		for f, dmg := range b.DamageDealt {
+			c.DamageDealt[f] = dmg
+		}
+	}
+	if b.Forfeited != nil {
+		c.Forfeited = make(map[int]bool, len(b.Forfeited))
+		for f, ff := range b.Forfeited {
+			c.Forfeited[f] = ff
+		}
+	}
+	c.events = nil
+	return &c
 }
 
 func (b *Board) Width() int {
@@ -160,67 +346,156 @@ func (b *Board) newID() RobotID {
 	return b.NextID
 }
 
+// findSpawns returns the spawn points on the first player's part of the board.
+func (b *Board) findSpawns() []Loc {
+	var locs []Loc
+	for x := 0; x < b.Size.X; x++ {
+		for y := 0; y < b.Size.Y; y++ {
+			loc := Loc{x, y}
+			if b.Cells[x][y] == Spawn && b.Symmetry.firstHalf(b.Size, loc) {
+				locs = append(locs, loc)
+			}
+		}
+	}
+	return locs
+}
+
+// spawnBots clears out the spawn zone and puts new robots in it. Boards without
+// a Spawner, like the ones bots simulate games on, only clear it out, since they
+// can't know where robots will spawn.
 func (b *Board) spawnBots() {
-	// Clear out the spawn zone
-	for _, locA := range b.leftSpawns {
-		locB := Loc{b.Size.X - 1 - locA.X, locA.Y}
-		delete(b.Locs, locA)
-		delete(b.Locs, locB)
+	if b.spawns == nil {
+		b.spawns = b.findSpawns()
 	}
 
-	// Spawn() returns the list of locations to spawn bots at
-	for _, locA := range b.s.Spawn(b.leftSpawns) {
-		locB := Loc{b.Size.X - 1 - locA.X, locA.Y}
-		b.Locs[locA] = &Robot{
-			ID:      b.newID(),
-			Health:  InitialHealth,
-			Faction: P1Faction,
+	// Deal with anyone camping in the spawn zone
+	n := len(b.events)
+	for _, spawn := range b.spawns {
+		for _, loc := range b.Symmetry.images(b.Size, spawn) {
+			r := b.at(loc)
+			if r == nil {
+				continue
+			}
+			switch b.Rules.SpawnCamping {
+			case KillCampers:
+				b.emit(Event{Type: Died, Robot: r.ID, Loc: loc})
+				b.remove(loc)
+			case DamageCampers:
+				b.hurtBot(botMove{Bot: r, Location: loc}, Camped, nil)
+			}
 		}
-		b.Locs[locB] = &Robot{
-			ID:      b.newID(),
-			Health:  InitialHealth,
-			Faction: P2Faction,
+	}
+	b.sortEvents(n)
+	b.clearTheDead()
+
+	if b.s == nil {
+		return
+	}
+
+	// Spawn() returns the list of locations to spawn bots at, which get mirrored
+	// for everyone else
+	picked := b.s.Spawn(b.rand, b, b.spawns)
+	for _, spawn := range picked {
+		locs := b.Symmetry.images(b.Size, spawn)
+		for f := 1; f <= b.Factions; f++ {
+			b.spawn(locs[b.Symmetry.seat(f, b.Factions)], f)
 		}
 	}
-}
 
-func (b *Board) Update(ta, tb botapi.Turn_List) {
-	// Put all the moves and bots into a list
-	moves := make([]botMove, ta.Len()+tb.Len())
-	for i := 0; i < ta.Len(); i++ {
-		t := ta.At(i)
-		loc, bot := b.fromID(RobotID(t.Id()))
-		moves[i].Bot = bot
-		moves[i].Turn = t
-		moves[i].Location = loc
+	fs, ok := b.s.(FactionSpawner)
+	if !ok {
+		return
 	}
-	for i, l := 0, ta.Len(); i < tb.Len(); i++ {
-		t := tb.At(i)
-		loc, bot := b.fromID(RobotID(t.Id()))
-		moves[i+l].Bot = bot
-		moves[i+l].Turn = t
-		moves[i+l].Location = loc
+	rest := unpicked(b.spawns, picked)
+	for f := 1; f <= b.Factions; f++ {
+		for _, spawn := range fs.SpawnMore(b.rand, b, f, rest) {
+			b.spawn(b.Symmetry.images(b.Size, spawn)[b.Symmetry.seat(f, b.Factions)], f)
+		}
 	}
-	c := make(collisionMap)
-	b.addCollisions(c, moves)
+}
 
-	// Move the bots to their new locations, unless they collide with something,
-	// in which case just subtract 1 from their health and don't move them.
-
-	// TODO: This allows bots to swap places, which isn't allowed in the original
-	// game.
-	for loc, ms := range c {
-		// If there's only one bot trying to get somewhere, just move them there
-		if len(ms) == 1 {
-			b.moveBot(ms[0].Bot, loc)
-		} else {
-			// Multiple bots, hurt 'em
-			for _, m := range ms {
-				b.hurtBot(m, Collision)
-			}
+// unpicked returns the locations in locs that aren't in picked.
+func unpicked(locs, picked []Loc) []Loc {
+	in := make(map[Loc]bool, len(picked))
+	for _, loc := range picked {
+		in[loc] = true
+	}
+	var rest []Loc
+	for _, loc := range locs {
+		if !in[loc] {
+			rest = append(rest, loc)
 		}
+	}
+	return rest
+}
+
+// spawnsAfter reports whether robots spawn at the end of the given round.
+func (b *Board) spawnsAfter(round int) bool {
+	if b.schedule != nil {
+		return b.schedule.SpawnsAfter(round)
+	}
+	return SpawnPeriod{Every: b.Rules.SpawnEvery, Until: b.Rules.SpawnUntil}.SpawnsAfter(round)
+}
+
+// spawn puts a new robot for faction at loc, unless there's already a robot
+// there or the faction has forfeited.
+func (b *Board) spawn(loc Loc, faction int) {
+	if b.at(loc) != nil || b.Forfeited[faction] {
+		return
+	}
+	r := &Robot{
+		ID:      b.newID(),
+		Health:  b.Rules.InitialHealth,
+		Faction: faction,
+	}
+	b.put(loc, r)
+	b.emit(Event{Type: Spawned, Robot: r.ID, Loc: loc})
+}
+
+// Update plays out a round with the turns for each faction, in faction order,
+// which should have been checked with ValidateTurns first, and returns
+// everything that happened during the round in the order it happened.
+func (b *Board) Update(turns ...botapi.Turn_List) []Event {
+	ts := make([][]Turn, len(turns))
+	for i, t := range turns {
+		ts[i] = TurnsFromWire(t)
+	}
+	return b.Apply(ts...)
+}
 
+// Apply plays out a round like Update, with turns that are already in the
+// engine's representation. Every robot on the board should have exactly one
+// turn.
+func (b *Board) Apply(turns ...[]Turn) []Event {
+	b.events = nil
+
+	// Put all the moves and bots into a list, and index them by robot
+	var moves []botMove
+	for _, ts := range turns {
+		for _, t := range ts {
+			loc, bot := b.fromID(t.ID)
+			moves = append(moves, botMove{
+				Bot:      bot,
+				Location: loc,
+				Turn:     t,
+			})
+		}
 	}
+	byID := make(map[RobotID]botMove, len(moves))
+	for _, m := range moves {
+		byID[m.Bot.ID] = m
+		if m.Turn.Kind == botapi.Turn_Which_guard {
+			b.emit(Event{Type: Guarded, Robot: m.Bot.ID, Loc: m.Location})
+		}
+	}
+
+	// Count and punish anyone trying to walk off the board or into a wall.
+	b.checkIllegal(moves)
+
+	// Move the bots to their new locations, unless they collide with something,
+	// in which case they stay put and get hurt for bumping into each other.
+	b.moveBots(moves, byID)
+
 	// Get rid of anyone who died in a collision
 	b.clearTheDead()
 
@@ -231,85 +506,143 @@ func (b *Board) Update(ta, tb botapi.Turn_List) {
 	// strategy this way
 
 	// Allow all attacks to be issued before removing bots, because there's no
-	// good, sensical way to order attacks. They all happen simultaneously
-	b.issueAttacks(moves)
+	// good, sensical way to order attacks. They all happen simultaneously, up
+	// close or at range
+	b.issueAttacks(moves, byID)
 
 	// Get rid of anyone who was viciously murdered
 	b.clearTheDead()
 
+	// The survivors get patched up, so healing can't save anyone from an
+	// attack, but it can from an explosion
+	b.issueHeals(moves)
+
 	// Boom goes the dynamite
-	b.issueSelfDestructs(moves)
+	b.issueSelfDestructs(moves, byID)
 
 	// Get rid of anyone killed in some kamikaze-shenanigans
 	b.clearTheDead()
 
+	// Whoever's left gets hurt or healed by what they're standing on
+	b.applyTerrain()
+	b.clearTheDead()
+
 	b.Round++
 
-	if b.Round%NewBotsSpacing == 0 && b.Round < 100 {
+	// A side that's been wiped out doesn't get to come back at the next spawn.
+	b.checkElimination()
+
+	if !b.eliminated && b.spawnsAfter(b.Round) {
 		b.spawnBots()
 	}
+
+	evs := b.events
+	b.events = nil
+	return evs
 }
 
-func (b *Board) issueAttacks(moves []botMove) {
+func (b *Board) issueAttacks(moves []botMove, byID map[RobotID]botMove) {
 	for _, move := range moves {
-		if move.Turn.Which() != botapi.Turn_Which_attack {
+		e := Event{Robot: move.Bot.ID, Loc: move.Location, Target: move.target()}
+		var dt DamageType
+		switch move.Turn.Kind {
+		case botapi.Turn_Which_attack:
+			e.Type, dt = Attacked, Attack
+		case botapi.Turn_Which_rangedAttack:
+			e.Type, dt = RangedAttacked, Ranged
+		default:
 			continue
 		}
 
-		// They're attacking
-		xOff, yOff := directionOffsets(move.Turn.Attack())
-		attackLoc := Loc{
-			X: move.Location.X + xOff,
-			Y: move.Location.Y + yOff,
+		// They're attacking, maybe somewhere off the board
+		var victim *Robot
+		if b.isValidLoc(e.Target) {
+			victim = b.at(e.Target)
 		}
 
 		// If there's a bot at the attack location, make them sad
-		// You *can* attack your own robots
-		victim := b.Locs[attackLoc]
+		// You *can* attack your own robots, if the rules allow it
 		if victim != nil {
-			for _, m := range moves {
-				if m.Bot.ID == victim.ID {
-					b.hurtBot(m, Attack)
-					break
-				}
+			e.Other = victim.ID
+		}
+		b.emit(e)
+		if b.canHurt(move.Bot, victim) {
+			if m, ok := byID[victim.ID]; ok {
+				b.hurtBot(m, dt, move.Bot)
 			}
 		}
 	}
 }
 
-func (b *Board) issueSelfDestructs(moves []botMove) {
+// issueHeals heals the robots next to healing robots, as long as they're on the
+// same side, up to their initial health.
+func (b *Board) issueHeals(moves []botMove) {
+	for _, move := range moves {
+		if move.Turn.Kind != botapi.Turn_Which_heal {
+			continue
+		}
+		if _, healer := b.fromID(move.Bot.ID); healer == nil {
+			// Didn't make it through the attacks
+			continue
+		}
+		target := move.target()
+		if !b.isValidLoc(target) {
+			continue
+		}
+		patient := b.at(target)
+		if patient == nil || !b.allies(move.Bot.Faction, patient.Faction) {
+			continue
+		}
+		heal := b.Rules.AllyHeal
+		if max := b.Rules.InitialHealth - patient.Health; heal > max {
+			heal = max
+		}
+		if heal > 0 {
+			patient.Health += heal
+			b.emit(Event{Type: Healed, Robot: patient.ID, Other: move.Bot.ID, Loc: target, Damage: heal})
+		}
+	}
+}
+
+func (b *Board) issueSelfDestructs(moves []botMove, byID map[RobotID]botMove) {
 	for _, move := range moves {
-		if move.Turn.Which() != botapi.Turn_Which_selfDestruct {
+		if move.Turn.Kind != botapi.Turn_Which_selfDestruct {
 			continue
 		}
 
 		// They're Metro-booming on production:
 		// (https://www.youtube.com/watch?v=NiM5ARaexPE)
+		b.emit(Event{Type: SelfDestructed, Robot: move.Bot.ID, Loc: move.Location})
 		for _, boomLoc := range b.surrounding(move.Location) {
 			// If there's a bot in the blast radius
-			victim := b.Locs[boomLoc]
-			if victim != nil {
-				for _, m := range moves {
-					if m.Bot.ID == victim.ID {
-						b.hurtBot(m, Destruct)
-						break
-					}
+			victim := b.at(boomLoc)
+			if b.canHurt(move.Bot, victim) {
+				if m, ok := byID[victim.ID]; ok {
+					b.hurtBot(m, Destruct, move.Bot)
 				}
 			}
 		}
 
 		// Kill 'em
-		b.hurtBot(move, Self)
+		b.hurtBot(move, Self, move.Bot)
+	}
+}
+
+// canHurt reports whether an attack or explosion from one robot damages
+// another.
+func (b *Board) canHurt(from, victim *Robot) bool {
+	if victim == nil {
+		return false
 	}
+	return b.Rules.FriendlyFire || !b.allies(from.Faction, victim.Faction)
 }
 
 func (b *Board) fromID(id RobotID) (Loc, *Robot) {
-	for loc, bot := range b.Locs {
-		if bot.ID == id {
-			return loc, bot
-		}
+	loc, ok := b.ids[id]
+	if !ok {
+		return Loc{}, nil
 	}
-	return Loc{}, nil
+	return loc, b.at(loc)
 }
 
 func (b *Board) surrounding(loc Loc) []Loc {
@@ -335,31 +668,196 @@ func (b *Board) surrounding(loc Loc) []Loc {
 	return vLocs
 }
 
-func (b *Board) addCollisions(c collisionMap, moves []botMove) {
-	for _, move := range moves {
-		nextLoc := b.nextLoc(move)
-		// Add where they want to move
-		c[nextLoc] = append(c[nextLoc], move)
+// moveBots moves every robot at once and hurts the ones that collided.
+func (b *Board) moveBots(moves []botMove, byID map[RobotID]botMove) {
+	slowed := make(map[RobotID]bool)
+	for _, m := range moves {
+		if b.slowedBy(m) {
+			slowed[m.Bot.ID] = true
+		}
+	}
+	dest, bumped := b.resolveMoves(moves)
+
+	bots := make(map[RobotID]*Robot, len(b.Locs))
+	from := make(map[RobotID]Loc, len(b.Locs))
+	for loc, bot := range b.Locs {
+		bots[bot.ID] = bot
+		from[bot.ID] = loc
+		b.grid[b.cell(loc)] = nil
+	}
+	b.Locs = make(map[Loc]*Robot, len(b.Locs))
+
+	n := len(b.events)
+	for id, loc := range dest {
+		b.put(loc, bots[id])
+		switch m := byID[id]; {
+		case loc != from[id]:
+			b.emit(Event{Type: Moved, Robot: id, Loc: from[id], Target: loc})
+		case slowed[id]:
+			b.emit(Event{Type: Slowed, Robot: id, Loc: loc, Target: m.target()})
+		case m.Turn.Kind == botapi.Turn_Which_move:
+			b.emit(Event{Type: Blocked, Robot: id, Loc: loc, Target: m.target()})
+		}
+	}
+	b.sortEvents(n)
+
+	// Robots that started getting into a slow cell can finish next round, and
+	// everyone else has to start over.
+	for id, bot := range bots {
+		bot.Entering = nil
+		if slowed[id] {
+			target := byID[id].target()
+			bot.Entering = &target
+		}
+	}
+
+	ids := make([]RobotID, 0, len(bumped))
+	for id := range bumped {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		m, ok := byID[id]
+		if !ok {
+			// Robots without a turn just sit there
+			m = botMove{Bot: bots[id]}
+		}
+		b.emit(Event{Type: Collided, Robot: id, Loc: dest[id]})
+		b.hurtBot(m, Collision, nil)
+	}
+}
+
+// resolveMoves works out where each robot ends up when every robot moves at the
+// same time, following the RobotGame rules:
+//
+//   - Robots trying to move into the same square all stay where they are.
+//   - A robot moving into a square held by a robot that isn't moving (or can't
+//     move) stays where it is.
+//   - Two robots trying to swap squares both stay where they are.
+//   - Chains of robots moving into squares that are being vacated all move, and
+//     so do cycles, as long as they're longer than a swap.
+//
+// Every robot that collided with another, including stationary robots that got
+// bumped into, is returned in bumped. A robot that gets sent back to its square
+// only ever blocks the robots moving into that square, so blocks are followed
+// from robot to robot rather than rechecking the whole board, and the outcome
+// doesn't depend on the order of the moves.
+func (b *Board) resolveMoves(moves []botMove) (dest map[RobotID]Loc, bumped map[RobotID]bool) {
+	from := make(map[RobotID]Loc, len(b.Locs))
+	dest = make(map[RobotID]Loc, len(b.Locs))
+	for loc, bot := range b.Locs {
+		from[bot.ID] = loc
+		dest[bot.ID] = loc
+	}
+	for _, m := range moves {
+		dest[m.Bot.ID] = b.nextLoc(m)
+	}
+
+	// Everyone headed to (or staying in) each square.
+	claims := make(map[Loc][]RobotID, len(dest))
+	for id, loc := range dest {
+		claims[loc] = append(claims[loc], id)
+	}
+
+	bumped = make(map[RobotID]bool)
+	// Robots that need to be sent back to where they started.
+	var blocked []RobotID
+
+	// Anyone headed to a square someone else is also headed to (or staying in)
+	// is blocked.
+	contest := func(loc Loc) {
+		ids := claims[loc]
+		if len(ids) < 2 {
+			return
+		}
+		for _, id := range ids {
+			bumped[id] = true
+			if dest[id] != from[id] {
+				blocked = append(blocked, id)
+			}
+		}
+	}
+	for loc := range claims {
+		contest(loc)
+	}
+
+	// Robots can't pass through each other.
+	for id, to := range dest {
+		other := b.at(to)
+		if to == from[id] || other == nil {
+			continue
+		}
+		if dest[other.ID] == from[id] {
+			bumped[id], bumped[other.ID] = true, true
+			blocked = append(blocked, id)
+		}
+	}
+
+	for len(blocked) > 0 {
+		id := blocked[len(blocked)-1]
+		blocked = blocked[:len(blocked)-1]
+		to, back := dest[id], from[id]
+		if to == back {
+			// Already sent back
+			continue
+		}
+
+		ids := claims[to]
+		for i, other := range ids {
+			if other == id {
+				claims[to] = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+		dest[id] = back
+		claims[back] = append(claims[back], id)
+		contest(back)
 	}
+	return dest, bumped
 }
 
-func (b *Board) hurtBot(move botMove, dt DamageType) {
+// hurtBot damages the robot making a move, and records that the damage came
+// from the robot from, if it's not nil. The damage counts for from's side even
+// if from has already died this round.
+func (b *Board) hurtBot(move botMove, dt DamageType, from *Robot) {
+	var dmg int
 	switch dt {
 	case Self:
 		move.Bot.Health = 0
-	case Attack, Destruct:
-		// If they are guarding, they take half damage
-		if move.Turn.Which() == botapi.Turn_Which_guard {
-			move.Bot.Health -= damageMap[dt] / 2
+		return
+	case Attack, Ranged, Destruct:
+		// If they are guarding, they take reduced damage
+		if move.Turn.Kind == botapi.Turn_Which_guard {
+			dmg = b.Rules.guarded(b.Rules.damage(dt))
 		} else {
-			move.Bot.Health -= damageMap[dt]
+			dmg = b.Rules.damage(dt)
+		}
+		if dt != Destruct && b.cellAt(b.ids[move.Bot.ID]) == CoverCell {
+			dmg = b.Rules.covered(dmg)
 		}
 	case Collision:
 		// If they aren't guarding, they take damage
-		if move.Turn.Which() != botapi.Turn_Which_guard {
-			move.Bot.Health -= damageMap[dt]
+		if move.Turn.Kind != botapi.Turn_Which_guard {
+			dmg = b.Rules.damage(dt)
 		}
+	case Penalized, Hazard, Camped:
+		dmg = b.Rules.damage(dt)
+	}
+	if dmg == 0 {
+		return
 	}
+	move.Bot.Health -= dmg
+	e := Event{Type: Damaged, Robot: move.Bot.ID, Loc: b.ids[move.Bot.ID], Damage: dmg}
+	if from != nil {
+		e.Other = from.ID
+		if !b.allies(from.Faction, move.Bot.Faction) {
+			if b.DamageDealt == nil {
+				b.DamageDealt = make(map[int]int)
+			}
+			b.DamageDealt[from.Faction] += dmg
+		}
+	}
+	b.emit(e)
 }
 
 func (b *Board) clearTheDead() {
@@ -372,19 +870,12 @@ func (b *Board) clearTheDead() {
 		}
 	}
 
+	n := len(b.events)
 	for _, loc := range killKeys {
-		delete(b.Locs, loc)
-	}
-}
-
-func (b *Board) moveBot(bot *Robot, loc Loc) error {
-	oldLoc := b.robotLoc(bot)
-	if manhattanDistance(oldLoc, loc) > 1 {
-		return errors.New("Teleporting or some ish")
+		b.emit(Event{Type: Died, Robot: b.at(loc).ID, Loc: loc})
+		b.remove(loc)
 	}
-	delete(b.Locs, oldLoc)
-	b.Locs[loc] = bot
-	return nil
+	b.sortEvents(n)
 }
 
 func manhattanDistance(loc1, loc2 Loc) int {
@@ -401,24 +892,28 @@ func abs(x int) int {
 func (b *Board) nextLoc(move botMove) Loc {
 	currentLoc := b.robotLoc(move.Bot)
 	// If they aren't moving, return their current loc
-	if move.Turn.Which() != botapi.Turn_Which_move {
+	if move.Turn.Kind != botapi.Turn_Which_move {
 		return currentLoc
 	}
 
 	// They're moving, return where they're going
 
-	xOff, yOff := directionOffsets(move.Turn.Move())
+	xOff, yOff := directionOffsets(move.Turn.Direction)
 	nextLoc := Loc{
 		X: currentLoc.X + xOff,
 		Y: currentLoc.Y + yOff,
 	}
 
+	if b.slowedBy(move) {
+		// They're only partway into a slow cell
+		return currentLoc
+	}
 	if b.isValidLoc(nextLoc) {
 		return nextLoc
 	}
 
-	// TODO: Penalize people for creating incompetent bots that like travelling
-	// to invalid locations, which is the case if we've reached here.
+	// They're trying to go somewhere they can't, so they stay put. checkIllegal
+	// takes care of penalizing them for it.
 	return currentLoc
 }
 
@@ -437,20 +932,70 @@ func directionOffsets(dir botapi.Direction) (x, y int) {
 }
 
 func (b *Board) robotLoc(r *Robot) Loc {
-	for loc, bot := range b.Locs {
-		if bot.ID == r.ID {
-			return loc
-		}
-	}
-	return Loc{}
+	return b.ids[r.ID]
 }
 
-// IsFinished reports whether the game is finished.
+// IsFinished reports whether the game is finished, either because every round
+// has been played, because a side was eliminated, or because the other sides
+// forfeited. See Result for how it turned out.
 func (b *Board) IsFinished() bool {
-	return b.Round >= 100
+	return b.eliminated || b.Round >= b.Rules.MaxRounds || b.forfeited()
 }
 
 // At returns the robot at a location or nil if not found.
+func (b *Board) At(loc Loc) *Robot {
+	if b.grid == nil {
+		// Boards decoded from a Playback don't have their indices.
+		return b.Locs[loc]
+	}
+	if loc.X < 0 || loc.X >= b.Size.X || loc.Y < 0 || loc.Y >= b.Size.Y {
+		return nil
+	}
+	return b.at(loc)
+}
+
+// Set puts a robot at a location, replacing any robot already there. A nil
+// robot clears the location.
+func (b *Board) Set(loc Loc, r *Robot) {
+	b.remove(loc)
+	if r == nil {
+		return
+	}
+	if old, ok := b.ids[r.ID]; ok {
+		b.remove(old)
+	}
+	b.put(loc, r)
+}
+
+// cell returns the index of a location in the grid.
+func (b *Board) cell(loc Loc) int {
+	return loc.X*b.Size.Y + loc.Y
+}
+
+// at returns the robot at a location on the board.
+func (b *Board) at(loc Loc) *Robot {
+	return b.grid[b.cell(loc)]
+}
+
+// put adds a robot to an empty location.
+func (b *Board) put(loc Loc, r *Robot) {
+	b.Locs[loc] = r
+	b.ids[r.ID] = loc
+	b.grid[b.cell(loc)] = r
+}
+
+// remove takes whatever robot is at a location off the board.
+func (b *Board) remove(loc Loc) {
+	r, ok := b.Locs[loc]
+	if !ok {
+		return
+	}
+	delete(b.Locs, loc)
+	delete(b.ids, r.ID)
+	b.grid[b.cell(loc)] = nil
+}
+
+// AtXY returns the robot and cell type at a location.
 func (b *Board) AtXY(x, y int) CellInfo {
 	if !b.isValidLoc(Loc{x, y}) {
 		return CellInfo{
@@ -460,7 +1005,7 @@ func (b *Board) AtXY(x, y int) CellInfo {
 	}
 
 	return CellInfo{
-		Bot:      b.Locs[Loc{X: x, Y: y}],
+		Bot:      b.At(Loc{X: x, Y: y}),
 		CellType: b.Cells[x][y],
 	}
 }
@@ -469,17 +1014,44 @@ func (b *Board) isValidLoc(loc Loc) bool {
 	if loc.X >= b.Size.X || loc.X < 0 || loc.Y < 0 || loc.Y >= b.Size.Y {
 		return false
 	}
-	return b.Cells[loc.X][loc.Y] == Valid || b.Cells[loc.X][loc.Y] == Spawn
+	return b.Cells[loc.X][loc.Y] != Invalid
+}
+
+// sortedLocs returns the locations of every robot on the board, ordered by the
+// ID of the robot there.
+func (b *Board) sortedLocs() []Loc {
+	ids := make([]RobotID, 0, len(b.ids))
+	for id := range b.ids {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	locs := make([]Loc, len(ids))
+	for i, id := range ids {
+		locs[i] = b.ids[id]
+	}
+	return locs
 }
 
 // ToWire converts the board to the wire representation with respect to the
-// given faction (since the wire factions are us vs. them).
+// given faction (since the wire factions are us, our allies, and them). Robots
+// the faction can't see under the rules' vision radius are left out, unless
+// it's a Spectator, to whom every robot is an opponent.
 func (b *Board) ToWire(out botapi.Board, faction int) error {
 	out.SetWidth(uint16(b.Size.X))
 	out.SetHeight(uint16(b.Size.Y))
 	out.SetRound(int32(b.Round))
 
-	robots, err := botapi.NewRobot_List(out.Segment(), int32(len(b.Locs)))
+	// Send the robots in ID order, so that bots see the same board every time a
+	// seeded match is replayed, leaving out the ones the faction can't see.
+	var locs []Loc
+	for _, loc := range b.sortedLocs() {
+		if b.visible(faction, loc, b.Locs[loc]) {
+			locs = append(locs, loc)
+		}
+	}
+
+	robots, err := botapi.NewRobot_List(out.Segment(), int32(len(locs)))
 	if err != nil {
 		return err
 	}
@@ -487,19 +1059,23 @@ func (b *Board) ToWire(out botapi.Board, faction int) error {
 		return err
 	}
 
-	n := 0
-	for loc, r := range b.Locs {
+	for n, loc := range locs {
+		r := b.Locs[loc]
 		outr := robots.At(n)
 		outr.SetId(uint32(r.ID))
 		outr.SetX(uint16(loc.X))
 		outr.SetY(uint16(loc.Y))
 		outr.SetHealth(int16(r.Health))
-		if r.Faction == faction {
+		outr.SetPlayer(uint8(r.Faction))
+		outr.SetEntering(enteringToWire(loc, r))
+		switch {
+		case r.Faction == faction:
 			outr.SetFaction(botapi.Faction_mine)
-		} else {
+		case b.allies(r.Faction, faction):
+			outr.SetFaction(botapi.Faction_ally)
+		default:
 			outr.SetFaction(botapi.Faction_opponent)
 		}
-		n++
 	}
 	return nil
 }
@@ -509,7 +1085,33 @@ func (b *Board) ToWire(out botapi.Board, faction int) error {
 // information about which cells are which type.
 func (b *Board) ToWireWithInitial(out botapi.InitialBoard, faction int) error {
 	wireBoard, err := out.NewBoard()
-	b.ToWire(wireBoard, faction)
+	if err != nil {
+		return err
+	}
+	if err = b.ToWire(wireBoard, faction); err != nil {
+		return err
+	}
+
+	rules, err := out.NewRules()
+	if err != nil {
+		return err
+	}
+	b.Rules.ToWire(rules)
+	out.SetSymmetry(symmetryToWire[b.Symmetry])
+	out.SetPlayers(uint8(b.Factions))
+	out.SetPlayer(uint8(faction))
+	out.SetTeams(uint8(b.Teams))
+
+	if b.schedule != nil {
+		rs := rounds(b.schedule, b.Rules.MaxRounds)
+		wireRounds, err := out.NewSpawnRounds(int32(len(rs)))
+		if err != nil {
+			return err
+		}
+		for i, r := range rs {
+			wireRounds.Set(i, int32(r))
+		}
+	}
 
 	cells, err := botapi.NewCellType_List(out.Segment(), int32(b.Size.X*b.Size.Y))
 	if err != nil {
@@ -533,6 +1135,10 @@ type Robot struct {
 	ID      RobotID
 	Health  int
 	Faction int
+
+	// Entering is the slow cell the robot spent the last round getting partway
+	// into, or nil if it didn't.
+	Entering *Loc
 }
 
 type RobotID uint32
@@ -1,6 +1,9 @@
 package engine
 
 import (
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/bcspragu/Gobots/botapi"
@@ -8,7 +11,7 @@ import (
 )
 
 func TestEmptyBoardIsEmpty(t *testing.T) {
-	b := EmptyBoard(3, 5)
+	b := EmptyBoard(BoardConfig{Size: Loc{3, 5}})
 	if b.Size.X != 3 {
 		t.Errorf("b.Size.X = %d; want 3", b.Size.X)
 	}
@@ -26,7 +29,7 @@ func TestEmptyBoardIsEmpty(t *testing.T) {
 }
 
 func TestBoard_Set(t *testing.T) {
-	b := EmptyBoard(3, 5)
+	b := EmptyBoard(BoardConfig{Size: Loc{3, 5}})
 	loc := Loc{1, 2}
 	b.Set(loc, &Robot{
 		ID:      1234,
@@ -76,7 +79,7 @@ func TestUpdate(t *testing.T) {
 	}
 	for i, test := range tests {
 		t.Logf("tests[%d], size = %v, round = %d", i, test.size, test.initRound)
-		b := EmptyBoard(test.size.X, test.size.Y)
+		b := EmptyBoard(BoardConfig{Size: test.size})
 		b.Round = test.initRound
 		for l, r := range test.init {
 			t.Logf("  -> set %v to %#v", l, r)
@@ -87,10 +90,7 @@ func TestUpdate(t *testing.T) {
 
 		t.Logf("  -> Update()")
 		// TODO: add parameters
-		// TODO Even harder: This line being commented out breaks the tests, need
-		// to insert blank Turn_Lists, but I don't even know how to properly call
-		// NewTurn_List. TF is a segment?!
-		//b.Update()
+		b.Update(turnList(t), turnList(t))
 
 		if b.Round != test.wantRound {
 			t.Errorf("  !! b.Round = %d; want %d", b.Round, test.wantRound)
@@ -120,8 +120,865 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestInitBoardSeeded(t *testing.T) {
+	newBoard := func(seed int64) *Board {
+		bc := BoardConfig{
+			Size:      Loc{17, 17},
+			Spawner:   NewRandomSpawn(2),
+			CellTyper: NewCircleSpawn(Loc{17, 17}),
+			Seed:      seed,
+		}
+		b := EmptyBoard(bc)
+		b.InitBoard(bc)
+		return b
+	}
+
+	a, b := newBoard(42), newBoard(42)
+	if !sameRobots(a, b) {
+		t.Errorf("boards with seed 42 spawned differently: %v vs %v", a.Locs, b.Locs)
+	}
+	if c := newBoard(43); sameRobots(a, c) {
+		t.Errorf("boards with seeds 42 and 43 spawned identically: %v", a.Locs)
+	}
+}
+
+func sameRobots(a, b *Board) bool {
+	if len(a.Locs) != len(b.Locs) {
+		return false
+	}
+	for loc, r := range a.Locs {
+		if rb := b.Locs[loc]; rb == nil || *rb != *r {
+			return false
+		}
+	}
+	return true
+}
+
+func TestUpdateMoves(t *testing.T) {
+	const (
+		n = botapi.Direction_north
+		s = botapi.Direction_south
+		e = botapi.Direction_east
+		w = botapi.Direction_west
+	)
+	full := DefaultRules.InitialHealth
+	hurt := DefaultRules.InitialHealth - DefaultRules.CollisionDamage
+
+	tests := []struct {
+		desc  string
+		init  map[Loc]RobotID
+		turns []testTurn
+		want  map[Loc]RobotID
+		// health of each robot after the round, only robots that should be hurt
+		// need to be listed
+		hurt map[RobotID]int
+	}{
+		{
+			desc:  "single move into an empty square",
+			init:  map[Loc]RobotID{{1, 1}: 1},
+			turns: []testTurn{move(1, e)},
+			want:  map[Loc]RobotID{{2, 1}: 1},
+		},
+		{
+			desc:  "move off the board",
+			init:  map[Loc]RobotID{{0, 0}: 1},
+			turns: []testTurn{move(1, w)},
+			want:  map[Loc]RobotID{{0, 0}: 1},
+		},
+		{
+			desc:  "two robots into the same square",
+			init:  map[Loc]RobotID{{1, 1}: 1, {3, 1}: 2},
+			turns: []testTurn{move(1, e), move(2, w)},
+			want:  map[Loc]RobotID{{1, 1}: 1, {3, 1}: 2},
+			hurt:  map[RobotID]int{1: hurt, 2: hurt},
+		},
+		{
+			desc:  "blocked by a stationary robot",
+			init:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2},
+			turns: []testTurn{move(1, e)},
+			want:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2},
+			hurt:  map[RobotID]int{1: hurt, 2: hurt},
+		},
+		{
+			desc:  "guarding robots don't take collision damage",
+			init:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2},
+			turns: []testTurn{move(1, e), guard(2)},
+			want:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2},
+			hurt:  map[RobotID]int{1: hurt, 2: full},
+		},
+		{
+			desc:  "head-on swap",
+			init:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2},
+			turns: []testTurn{move(1, e), move(2, w)},
+			want:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2},
+			hurt:  map[RobotID]int{1: hurt, 2: hurt},
+		},
+		{
+			desc:  "chain into a square being vacated",
+			init:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2, {3, 1}: 3},
+			turns: []testTurn{move(1, e), move(2, e), move(3, e)},
+			want:  map[Loc]RobotID{{2, 1}: 1, {3, 1}: 2, {4, 1}: 3},
+		},
+		{
+			desc:  "chain blocked at the front",
+			init:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2, {3, 1}: 3},
+			turns: []testTurn{move(1, e), move(2, e)},
+			want:  map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2, {3, 1}: 3},
+			hurt:  map[RobotID]int{1: hurt, 2: hurt, 3: hurt},
+		},
+		{
+			desc:  "chain blocked by a contested square",
+			init:  map[Loc]RobotID{{0, 1}: 1, {1, 1}: 2, {2, 0}: 3},
+			turns: []testTurn{move(1, e), move(2, e), move(3, s)},
+			want:  map[Loc]RobotID{{0, 1}: 1, {1, 1}: 2, {2, 0}: 3},
+			hurt:  map[RobotID]int{1: hurt, 2: hurt, 3: hurt},
+		},
+		{
+			desc: "rotation",
+			init: map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2, {2, 2}: 3, {1, 2}: 4},
+			turns: []testTurn{
+				move(1, e), move(2, s), move(3, w), move(4, n),
+			},
+			want: map[Loc]RobotID{{2, 1}: 1, {2, 2}: 2, {1, 2}: 3, {1, 1}: 4},
+		},
+		{
+			desc: "rotation with a swap inside",
+			init: map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2, {2, 2}: 3, {1, 2}: 4},
+			turns: []testTurn{
+				move(1, e), move(2, w), move(3, w), move(4, n),
+			},
+			want: map[Loc]RobotID{{1, 1}: 1, {2, 1}: 2, {2, 2}: 3, {1, 2}: 4},
+			hurt: map[RobotID]int{1: hurt, 2: hurt, 3: hurt, 4: hurt},
+		},
+	}
+
+	for _, test := range tests {
+		// Run every test with the turns in both orders, the outcome shouldn't
+		// depend on which robot is processed first.
+		for _, reverse := range []bool{false, true} {
+			b := openBoard(Loc{5, 5})
+			for loc, id := range test.init {
+				b.Set(loc, &Robot{ID: id, Health: DefaultRules.InitialHealth, Faction: P1Faction})
+			}
+			turns := append([]testTurn(nil), test.turns...)
+			if reverse {
+				for i, j := 0, len(turns)-1; i < j; i, j = i+1, j-1 {
+					turns[i], turns[j] = turns[j], turns[i]
+				}
+			}
+
+			b.Update(turnList(t, turns...), turnList(t))
+
+			got := make(map[Loc]RobotID)
+			for loc, r := range b.Locs {
+				got[loc] = r.ID
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s (reversed: %t): robots = %v; want %v", test.desc, reverse, got, test.want)
+			}
+			for _, r := range b.Locs {
+				want, ok := test.hurt[r.ID]
+				if !ok {
+					want = full
+				}
+				if r.Health != want {
+					t.Errorf("%s (reversed: %t): robot %d health = %d; want %d", test.desc, reverse, r.ID, r.Health, want)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateTurns(t *testing.T) {
+	b := openBoard(Loc{5, 5})
+	b.Set(Loc{1, 1}, &Robot{ID: 1, Health: DefaultRules.InitialHealth, Faction: P1Faction})
+	b.Set(Loc{1, 2}, &Robot{ID: 2, Health: DefaultRules.InitialHealth, Faction: P1Faction})
+	b.Set(Loc{3, 3}, &Robot{ID: 3, Health: DefaultRules.InitialHealth, Faction: P2Faction})
+	// Robot 4 was on the board once, but it's gone now.
+	b.NextID = 4
+	b.Round = 7
+
+	in := turnList(t,
+		move(1, botapi.Direction_east),
+		guard(1),
+		move(3, botapi.Direction_west),
+		guard(4),
+		guard(9),
+	)
+	out, vs, err := b.ValidateTurns(P1Faction, in)
+	if err != nil {
+		t.Fatal("ValidateTurns:", err)
+	}
+
+	if out.Len() != 2 {
+		t.Fatalf("len(turns) = %d; want 2", out.Len())
+	}
+	if got := out.At(0); got.Id() != 1 || got.Which() != botapi.Turn_Which_move || got.Move() != botapi.Direction_east {
+		t.Errorf("turns[0] = %v; want robot 1 moving east", got)
+	}
+	if got := out.At(1); got.Id() != 2 || got.Which() != botapi.Turn_Which_wait {
+		t.Errorf("turns[1] = %v; want robot 2 waiting", got)
+	}
+
+	want := []Violation{
+		{Round: 7, Robot: 1, Type: DuplicateTurn},
+		{Round: 7, Robot: 3, Type: ForeignRobot},
+		{Round: 7, Robot: 4, Type: DeadRobot},
+		{Round: 7, Robot: 9, Type: NoSuchRobot},
+		{Round: 7, Robot: 2, Type: MissingTurn},
+	}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("violations = %v; want %v", vs, want)
+	}
+}
+
+func TestUpdateRules(t *testing.T) {
+	noFF := DefaultRules
+	noFF.FriendlyFire = false
+	heavy := DefaultRules
+	heavy.AttackDamage = 30
+	heavy.GuardMultiplier = 0.2
+
+	tests := []struct {
+		desc  string
+		rules RuleSet
+		turns []testTurn
+		// health of robots 2 (a teammate) and 3 (an opponent) after the round
+		want2, want3 int
+	}{
+		{
+			desc:  "attacking a teammate",
+			rules: DefaultRules,
+			turns: []testTurn{attack(1, botapi.Direction_east)},
+			want2: 40,
+			want3: 50,
+		},
+		{
+			desc:  "attacking a teammate without friendly fire",
+			rules: noFF,
+			turns: []testTurn{attack(1, botapi.Direction_east)},
+			want2: 50,
+			want3: 50,
+		},
+		{
+			desc:  "self-destructing without friendly fire",
+			rules: noFF,
+			turns: []testTurn{selfDestruct(1)},
+			want2: 50,
+			want3: 35,
+		},
+		{
+			desc:  "heavy attack on an opponent",
+			rules: heavy,
+			turns: []testTurn{attack(1, botapi.Direction_south)},
+			want2: 50,
+			want3: 20,
+		},
+		{
+			desc:  "heavy attack on a guarding opponent",
+			rules: heavy,
+			turns: []testTurn{attack(1, botapi.Direction_south), guard(3)},
+			want2: 50,
+			want3: 44,
+		},
+	}
+
+	for _, test := range tests {
+		b := openBoard(Loc{5, 5})
+		b.Rules = test.rules
+		b.Set(Loc{1, 1}, &Robot{ID: 1, Health: 50, Faction: P1Faction})
+		b.Set(Loc{2, 1}, &Robot{ID: 2, Health: 50, Faction: P1Faction})
+		b.Set(Loc{1, 2}, &Robot{ID: 3, Health: 50, Faction: P2Faction})
+		b.NextID = 3
+
+		// Everyone waits unless the test says otherwise.
+		ta := []testTurn{{id: 1}, {id: 2}}
+		tb := []testTurn{{id: 3}}
+		for _, tt := range test.turns {
+			if tt.id == 3 {
+				tb[0] = tt
+			} else {
+				ta[tt.id-1] = tt
+			}
+		}
+		b.Update(turnList(t, ta...), turnList(t, tb...))
+
+		if got := b.At(Loc{2, 1}).Health; got != test.want2 {
+			t.Errorf("%s: teammate health = %d; want %d", test.desc, got, test.want2)
+		}
+		if got := b.At(Loc{1, 2}).Health; got != test.want3 {
+			t.Errorf("%s: opponent health = %d; want %d", test.desc, got, test.want3)
+		}
+	}
+}
+
+func TestHealAndRangedAttack(t *testing.T) {
+	b := openBoard(Loc{6, 3})
+	b.Rules.AllyHeal = 10
+	b.Rules.RangedDamage = 6
+	b.Set(Loc{0, 1}, &Robot{ID: 1, Health: 50, Faction: P1Faction})
+	b.Set(Loc{1, 1}, &Robot{ID: 2, Health: 35, Faction: P1Faction})
+	b.Set(Loc{2, 1}, &Robot{ID: 3, Health: 50, Faction: P2Faction})
+	b.Set(Loc{3, 1}, &Robot{ID: 4, Health: 30, Faction: P2Faction})
+	b.Set(Loc{5, 1}, &Robot{ID: 5, Health: 50, Faction: P2Faction})
+	b.NextID = 5
+
+	// Robot 1 heals its teammate, robot 2 shoots over robot 3 at robot 4,
+	// robot 3 tries to heal its opponent, and robot 5 shoots off the board.
+	b.Update(
+		turnList(t, heal(1, botapi.Direction_east), rangedAttack(2, botapi.Direction_east)),
+		turnList(t, heal(3, botapi.Direction_west), testTurn{id: 4}, rangedAttack(5, botapi.Direction_east)),
+	)
+	for id, want := range map[RobotID]int{1: 50, 2: 45, 3: 50, 4: 24, 5: 50} {
+		if _, r := b.fromID(id); r.Health != want {
+			t.Errorf("robot %d health = %d; want %d", id, r.Health, want)
+		}
+	}
+	if got := b.Illegal[P2Faction].AttackWall; got != 1 {
+		t.Errorf("P2 attacks on walls = %d; want 1", got)
+	}
+
+	// The default rules don't allow either action, so they're turned into waits.
+	b.Rules = DefaultRules
+	out, vs, err := b.ValidateTurns(P1Faction, turnList(t, heal(1, botapi.Direction_east), rangedAttack(2, botapi.Direction_east)))
+	if err != nil {
+		t.Fatal("ValidateTurns:", err)
+	}
+	for i := 0; i < out.Len(); i++ {
+		if got := out.At(i).Which(); got != botapi.Turn_Which_wait {
+			t.Errorf("turns[%d] = %v; want wait", i, got)
+		}
+	}
+	want := []Violation{
+		{Round: 1, Robot: 1, Type: DisallowedAction},
+		{Round: 1, Robot: 2, Type: DisallowedAction},
+	}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("violations = %v; want %v", vs, want)
+	}
+}
+
+func TestIllegalActions(t *testing.T) {
+	damage := DefaultRules
+	damage.IllegalPenalty = DamagePenalty
+	none := DefaultRules
+	none.IllegalPenalty = NoPenalty
+
+	tests := []struct {
+		rules      RuleSet
+		wantP1     IllegalActions
+		wantP2     IllegalActions
+		wantHealth int
+		wantEvents int
+	}{
+		{DefaultRules, IllegalActions{OffBoard: 1, IntoWall: 1}, IllegalActions{AttackWall: 1}, 50, 3},
+		{damage, IllegalActions{OffBoard: 1, IntoWall: 1}, IllegalActions{AttackWall: 1}, 45, 6},
+		// Illegal actions are counted even when they aren't punished.
+		{none, IllegalActions{OffBoard: 1, IntoWall: 1}, IllegalActions{AttackWall: 1}, 50, 0},
+	}
+
+	for _, test := range tests {
+		// Robot 1 walks off the top of the board, robot 2 walks into the wall
+		// in the middle, and robot 3 attacks it.
+		b := openBoard(Loc{3, 3})
+		b.Cells[1][1] = Invalid
+		b.Rules = test.rules
+		b.Set(Loc{0, 0}, &Robot{ID: 1, Health: 50, Faction: P1Faction})
+		b.Set(Loc{0, 1}, &Robot{ID: 2, Health: 50, Faction: P1Faction})
+		b.Set(Loc{2, 1}, &Robot{ID: 3, Health: 50, Faction: P2Faction})
+		b.NextID = 3
+
+		evs := b.Update(
+			turnList(t, move(1, botapi.Direction_north), move(2, botapi.Direction_east)),
+			turnList(t, attack(3, botapi.Direction_west)))
+
+		if got := b.Illegal[P1Faction]; got != test.wantP1 {
+			t.Errorf("%v: P1 illegal actions = %+v; want %+v", test.rules.IllegalPenalty, got, test.wantP1)
+		}
+		if got := b.Illegal[P2Faction]; got != test.wantP2 {
+			t.Errorf("%v: P2 illegal actions = %+v; want %+v", test.rules.IllegalPenalty, got, test.wantP2)
+		}
+		for _, loc := range []Loc{{0, 0}, {0, 1}, {2, 1}} {
+			if got := b.At(loc).Health; got != test.wantHealth {
+				t.Errorf("%v: health at %s = %d; want %d", test.rules.IllegalPenalty, loc, got, test.wantHealth)
+			}
+		}
+
+		var n int
+		for _, e := range evs {
+			switch e.Type {
+			case IllegalMove, IllegalAttack, Damaged:
+				n++
+			}
+		}
+		if n != test.wantEvents {
+			t.Errorf("%v: got %d illegal action and damage events, want %d: %v", test.rules.IllegalPenalty, n, test.wantEvents, evs)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	noElim := DefaultRules
+	noElim.EndOnElimination = false
+	noTiebreaks := DefaultRules
+	noTiebreaks.Tiebreaks = [2]Tiebreak{}
+	damageFirst := DefaultRules
+	damageFirst.Tiebreaks = [2]Tiebreak{DamageDealt, TotalHealth}
+
+	tests := []struct {
+		desc  string
+		rules RuleSet
+		// The robots on the board, as ID: health, and the turn for robot 1.
+		p1, p2       map[RobotID]int
+		turn         testTurn
+		wantFinished bool
+		want         Result
+	}{
+		{
+			desc:         "elimination",
+			rules:        DefaultRules,
+			p1:           map[RobotID]int{1: 50},
+			p2:           map[RobotID]int{2: 10},
+			turn:         attack(1, botapi.Direction_east),
+			wantFinished: true,
+			want: Result{
+				Reason: Elimination,
+				Winner: P1Faction,
+				Robots: map[int]int{P1Faction: 1},
+				Health: map[int]int{P1Faction: 50},
+				Damage: map[int]int{P1Faction: 10},
+			},
+		},
+		{
+			desc:  "elimination without ending on it",
+			rules: noElim,
+			p1:    map[RobotID]int{1: 50},
+			p2:    map[RobotID]int{2: 10},
+			turn:  attack(1, botapi.Direction_east),
+			want: Result{
+				Winner: P1Faction,
+				Robots: map[int]int{P1Faction: 1},
+				Health: map[int]int{P1Faction: 50},
+				Damage: map[int]int{P1Faction: 10},
+			},
+		},
+		{
+			desc:  "total health tiebreak",
+			rules: DefaultRules,
+			p1:    map[RobotID]int{1: 50},
+			p2:    map[RobotID]int{2: 30},
+			turn:  attack(1, botapi.Direction_east),
+			want: Result{
+				Winner:   P1Faction,
+				Tiebreak: TotalHealth,
+				Robots:   map[int]int{P1Faction: 1, P2Faction: 1},
+				Health:   map[int]int{P1Faction: 50, P2Faction: 20},
+				Damage:   map[int]int{P1Faction: 10},
+			},
+		},
+		{
+			desc:  "damage dealt tiebreak",
+			rules: damageFirst,
+			p1:    map[RobotID]int{1: 10},
+			p2:    map[RobotID]int{2: 50},
+			turn:  attack(1, botapi.Direction_east),
+			want: Result{
+				Winner:   P1Faction,
+				Tiebreak: DamageDealt,
+				Robots:   map[int]int{P1Faction: 1, P2Faction: 1},
+				Health:   map[int]int{P1Faction: 10, P2Faction: 40},
+				Damage:   map[int]int{P1Faction: 10},
+			},
+		},
+		{
+			desc:  "no tiebreaks",
+			rules: noTiebreaks,
+			p1:    map[RobotID]int{1: 50},
+			p2:    map[RobotID]int{2: 30},
+			turn:  attack(1, botapi.Direction_east),
+			want: Result{
+				Robots: map[int]int{P1Faction: 1, P2Faction: 1},
+				Health: map[int]int{P1Faction: 50, P2Faction: 20},
+				Damage: map[int]int{P1Faction: 10},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		b := openBoard(Loc{3, 3})
+		b.Rules = test.rules
+		b.Set(Loc{0, 1}, &Robot{ID: 1, Health: test.p1[1], Faction: P1Faction})
+		b.Set(Loc{1, 1}, &Robot{ID: 2, Health: test.p2[2], Faction: P2Faction})
+		b.NextID = 2
+
+		b.Update(turnList(t, test.turn), turnList(t, testTurn{id: 2}))
+		if got := b.IsFinished(); got != test.wantFinished {
+			t.Errorf("%s: IsFinished() = %t; want %t", test.desc, got, test.wantFinished)
+		}
+		if got := b.Result(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Result() = %+v; want %+v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestDamageDealtByTheDead(t *testing.T) {
+	// Robot 1 is killed by robot 2's attack, but its self-destruct still goes
+	// off, and still counts for its side.
+	b := openBoard(Loc{3, 3})
+	b.Set(Loc{0, 1}, &Robot{ID: 1, Health: 10, Faction: P1Faction})
+	b.Set(Loc{1, 1}, &Robot{ID: 2, Health: 50, Faction: P2Faction})
+	b.NextID = 2
+
+	b.Update(turnList(t, selfDestruct(1)), turnList(t, attack(2, botapi.Direction_west)))
+	if b.At(Loc{0, 1}) != nil {
+		t.Fatal("robot 1 survived the round")
+	}
+	want := map[int]int{
+		P1Faction: DefaultRules.DestructDamage,
+		P2Faction: DefaultRules.AttackDamage,
+	}
+	if !reflect.DeepEqual(b.DamageDealt, want) {
+		t.Errorf("DamageDealt = %v; want %v", b.DamageDealt, want)
+	}
+}
+
+func TestRulesRounds(t *testing.T) {
+	rs := DefaultRules
+	rs.MaxRounds = 4
+	rs.SpawnEvery = 2
+	rs.SpawnUntil = 3
+	bc := BoardConfig{
+		Size:      Loc{17, 17},
+		Spawner:   AllSpawn,
+		CellTyper: NewCircleSpawn(Loc{17, 17}),
+		Rules:     &rs,
+	}
+	b := EmptyBoard(bc)
+	b.InitBoard(bc)
+
+	var spawned []int
+	for !b.IsFinished() {
+		before := b.NextID
+		b.Update(turnList(t), turnList(t))
+		if b.NextID != before {
+			spawned = append(spawned, b.Round)
+		}
+	}
+	if b.Round != 4 {
+		t.Errorf("game finished after %d rounds; want 4", b.Round)
+	}
+	if want := []int{2}; !reflect.DeepEqual(spawned, want) {
+		t.Errorf("robots spawned after rounds %v; want %v", spawned, want)
+	}
+}
+
+func TestSpawnSchedule(t *testing.T) {
+	rs := DefaultRules
+	rs.MaxRounds = 4
+	bc := BoardConfig{
+		Size:      Loc{17, 17},
+		Spawner:   AllSpawn,
+		CellTyper: NewCircleSpawn(Loc{17, 17}),
+		Rules:     &rs,
+		Schedule:  SpawnRounds{1, 3},
+	}
+	b := EmptyBoard(bc)
+	b.InitBoard(bc)
+
+	// Bots are told the schedule, so they can simulate it.
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal("capnp.NewMessage:", err)
+	}
+	ib, err := botapi.NewRootInitialBoard(seg)
+	if err != nil {
+		t.Fatal("botapi.NewRootInitialBoard:", err)
+	}
+	if err := b.ToWireWithInitial(ib, P1Faction); err != nil {
+		t.Fatal("ToWireWithInitial:", err)
+	}
+	wb, err := boardFromWireWithInitial(ib)
+	if err != nil {
+		t.Fatal("boardFromWireWithInitial:", err)
+	}
+	if want := (SpawnRounds{1, 3}); !reflect.DeepEqual(wb.schedule, want) {
+		t.Errorf("schedule from the wire = %v; want %v", wb.schedule, want)
+	}
+
+	var spawned []int
+	for !b.IsFinished() {
+		before := b.NextID
+		b.Update(turnList(t), turnList(t))
+		if b.NextID != before {
+			spawned = append(spawned, b.Round)
+		}
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(spawned, want) {
+		t.Errorf("robots spawned after rounds %v; want %v", spawned, want)
+	}
+}
+
+func TestParseSpawnRounds(t *testing.T) {
+	tests := []struct {
+		list string
+		want SpawnRounds
+		// The error should contain this, or be nil if it's empty
+		err string
+	}{
+		{"10", SpawnRounds{10}, ""},
+		{"1, 3,20", SpawnRounds{1, 3, 20}, ""},
+		{"1,,3", nil, "bad spawn round"},
+		{"ten", nil, "bad spawn round"},
+		{"5,-2", nil, "spawn round -2"},
+		{"0", nil, "spawn round 0"},
+	}
+	for _, test := range tests {
+		got, err := ParseSpawnRounds(test.list)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("ParseSpawnRounds(%q): %v", test.list, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("ParseSpawnRounds(%q) error = %v; want one containing %q", test.list, err, test.err)
+		case !reflect.DeepEqual(got, test.want):
+			t.Errorf("ParseSpawnRounds(%q) = %v; want %v", test.list, got, test.want)
+		}
+	}
+}
+
+func TestSpawnCamping(t *testing.T) {
+	m := &Map{Name: "test", Size: Loc{5, 1}, Symmetry: Horizontal}
+	if err := m.setRows([]string{"S...S"}); err != nil {
+		t.Fatal("setRows:", err)
+	}
+
+	type robot struct {
+		ID     RobotID
+		Health int
+	}
+	tests := []struct {
+		camping Camping
+		// The robots at either end of the board after the first round, when the
+		// robot at (0, 0) starts it with 5 health.
+		want [2]robot
+	}{
+		{KillCampers, [2]robot{{3, 50}, {4, 50}}},
+		{SkipOccupied, [2]robot{{1, 5}, {2, 50}}},
+		{DamageCampers, [2]robot{{3, 50}, {2, 40}}},
+	}
+	for _, test := range tests {
+		rs := DefaultRules
+		rs.SpawnEvery = 1
+		rs.SpawnCamping = test.camping
+		bc := BoardConfig{Size: m.Size, Spawner: AllSpawn, CellTyper: m, Rules: &rs}
+		b := EmptyBoard(bc)
+		b.InitBoard(bc)
+		b.At(Loc{0, 0}).Health = 5
+
+		b.Update(turnList(t, testTurn{id: 1}), turnList(t, testTurn{id: 2}))
+		for i, loc := range []Loc{{0, 0}, {4, 0}} {
+			r := b.At(loc)
+			if r == nil {
+				t.Errorf("%s: no robot at %s", test.camping, loc)
+				continue
+			}
+			if got := (robot{r.ID, r.Health}); got != test.want[i] {
+				t.Errorf("%s: robot at %s = %+v; want %+v", test.camping, loc, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestCatchUpSpawn(t *testing.T) {
+	m := &Map{Name: "test", Size: Loc{5, 1}, Symmetry: Horizontal}
+	if err := m.setRows([]string{"SS.SS"}); err != nil {
+		t.Fatal("setRows:", err)
+	}
+	rs := DefaultRules
+	rs.SpawnEvery = 1
+	rs.SpawnCamping = SkipOccupied
+	bc := BoardConfig{
+		Size:      m.Size,
+		Spawner:   NewCatchUpSpawn(EveryOtherSpawn, 1),
+		CellTyper: m,
+		Rules:     &rs,
+	}
+	b := EmptyBoard(bc)
+	b.InitBoard(bc)
+	if n := len(b.Locs); n != 2 {
+		t.Fatalf("%d robots at the start; want 2", n)
+	}
+
+	// Put P2 ahead, so P1 gets an extra robot in the spawn cell nobody uses.
+	b.Set(Loc{2, 0}, &Robot{ID: 3, Health: 50, Faction: P2Faction})
+	b.NextID = 3
+	b.Update(turnList(t, testTurn{id: 1}), turnList(t, testTurn{id: 2}, testTurn{id: 3}))
+	if r := b.At(Loc{1, 0}); r == nil || r.Faction != P1Faction {
+		t.Errorf("robot at (1, 0) = %+v; want a new P1 robot", r)
+	}
+	if r := b.At(Loc{3, 0}); r != nil {
+		t.Errorf("robot at (3, 0) = %+v; want none", r)
+	}
+}
+
+func TestRulesCheck(t *testing.T) {
+	tests := []struct {
+		desc  string
+		apply func(*RuleSet)
+		// The error should contain this, or be nil if it's empty
+		err string
+	}{
+		{"default", func(rs *RuleSet) {}, ""},
+		{"ranged", func(rs *RuleSet) { rs.RangedDamage = 5 }, ""},
+		{"ranged as strong as melee", func(rs *RuleSet) { rs.RangedDamage = rs.AttackDamage }, "isn't less than attack damage"},
+		{"negative vision", func(rs *RuleSet) { rs.VisionRadius = -1 }, "negative vision radius"},
+		{"negative heal", func(rs *RuleSet) { rs.AllyHeal = -5 }, "negative ally heal"},
+	}
+	for _, test := range tests {
+		rs := DefaultRules
+		test.apply(&rs)
+		err := rs.Check()
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: Check: %v", test.desc, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: Check error = %v; want one containing %q", test.desc, err, test.err)
+		}
+	}
+}
+
+func TestRulesWire(t *testing.T) {
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal("capnp.NewMessage:", err)
+	}
+	ib, err := botapi.NewRootInitialBoard(seg)
+	if err != nil {
+		t.Fatal("botapi.NewRootInitialBoard:", err)
+	}
+
+	// Boards sent before rule sets existed were played with the defaults, back
+	// when games didn't end on elimination or break ties.
+	old, err := ib.Rules()
+	if err != nil {
+		t.Fatal("Rules:", err)
+	}
+	want := DefaultRules
+	want.EndOnElimination = false
+	want.Tiebreaks = [2]Tiebreak{NoTiebreak, NoTiebreak}
+	if got := RulesFromWire(old); got != want {
+		t.Errorf("RulesFromWire(missing) = %+v; want %+v", got, want)
+	}
+
+	rs := RuleSet{
+		InitialHealth:   20,
+		CollisionDamage: 1,
+		AttackDamage:    25,
+		DestructDamage:  40,
+		GuardMultiplier: 0.25,
+		SpawnEvery:      5,
+		SpawnUntil:      30,
+		MaxRounds:       40,
+		FriendlyFire:    false,
+		IllegalPenalty:  DamagePenalty,
+		IllegalDamage:   3,
+		Tiebreaks:       [2]Tiebreak{DamageDealt, NoTiebreak},
+		VisionRadius:    4,
+		VisionMetric:    Chebyshev,
+		HazardDamage:    8,
+		CoverMultiplier: 0.75,
+		HealAmount:      2,
+		AllyHeal:        7,
+		RangedDamage:    4,
+		SpawnCamping:    DamageCampers,
+		CampingDamage:   6,
+	}
+	wr, err := ib.NewRules()
+	if err != nil {
+		t.Fatal("NewRules:", err)
+	}
+	for _, rs := range []RuleSet{rs, DefaultRules} {
+		rs.ToWire(wr)
+		if got := RulesFromWire(wr); got != rs {
+			t.Errorf("RulesFromWire(ToWire(%+v)) = %+v", rs, got)
+		}
+	}
+}
+
+// openBoard returns a board of the given size where every cell is valid.
+func openBoard(size Loc) *Board {
+	b := EmptyBoard(BoardConfig{Size: size})
+	for x := range b.Cells {
+		for y := range b.Cells[x] {
+			b.Cells[x][y] = Valid
+		}
+	}
+	return b
+}
+
+type testTurn struct {
+	id   RobotID
+	kind botapi.Turn_Which
+	dir  botapi.Direction
+}
+
+func move(id RobotID, d botapi.Direction) testTurn {
+	return testTurn{id: id, kind: botapi.Turn_Which_move, dir: d}
+}
+
+func attack(id RobotID, d botapi.Direction) testTurn {
+	return testTurn{id: id, kind: botapi.Turn_Which_attack, dir: d}
+}
+
+func selfDestruct(id RobotID) testTurn {
+	return testTurn{id: id, kind: botapi.Turn_Which_selfDestruct}
+}
+
+func guard(id RobotID) testTurn {
+	return testTurn{id: id, kind: botapi.Turn_Which_guard}
+}
+
+func heal(id RobotID, d botapi.Direction) testTurn {
+	return testTurn{id: id, kind: botapi.Turn_Which_heal, dir: d}
+}
+
+func rangedAttack(id RobotID, d botapi.Direction) testTurn {
+	return testTurn{id: id, kind: botapi.Turn_Which_rangedAttack, dir: d}
+}
+
+func turnList(t *testing.T, turns ...testTurn) botapi.Turn_List {
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal("capnp.NewMessage:", err)
+	}
+	tl, err := botapi.NewTurn_List(seg, int32(len(turns)))
+	if err != nil {
+		t.Fatal("botapi.NewTurn_List:", err)
+	}
+	for i, tt := range turns {
+		wt := tl.At(i)
+		wt.SetId(uint32(tt.id))
+		switch tt.kind {
+		case botapi.Turn_Which_wait:
+			wt.SetWait()
+		case botapi.Turn_Which_move:
+			wt.SetMove(tt.dir)
+		case botapi.Turn_Which_attack:
+			wt.SetAttack(tt.dir)
+		case botapi.Turn_Which_selfDestruct:
+			wt.SetSelfDestruct()
+		case botapi.Turn_Which_guard:
+			wt.SetGuard()
+		case botapi.Turn_Which_heal:
+			wt.SetHeal(tt.dir)
+		case botapi.Turn_Which_rangedAttack:
+			wt.SetRangedAttack(tt.dir)
+		}
+	}
+	return tl
+}
+
 func TestToWire(t *testing.T) {
-	b := EmptyBoard(4, 6)
+	b := EmptyBoard(BoardConfig{Size: Loc{4, 6}})
 	b.Set(Loc{1, 2}, &Robot{ID: 254, Health: 50, Faction: 0})
 	b.Set(Loc{3, 4}, &Robot{ID: 973, Health: 12, Faction: 1})
 	b.Round = 42
@@ -183,3 +1040,838 @@ func TestToWire(t *testing.T) {
 		t.Errorf("robots error: %v", err)
 	}
 }
+
+func TestUpdateEvents(t *testing.T) {
+	const (
+		e = botapi.Direction_east
+		w = botapi.Direction_west
+	)
+	b := openBoard(Loc{6, 6})
+	for _, r := range []struct {
+		loc     Loc
+		id      RobotID
+		health  int
+		faction int
+	}{
+		{Loc{0, 0}, 1, 50, P1Faction},
+		{Loc{0, 2}, 2, 50, P1Faction},
+		{Loc{2, 2}, 3, 50, P2Faction},
+		{Loc{4, 4}, 4, 50, P1Faction},
+		{Loc{5, 4}, 5, 50, P2Faction},
+		{Loc{4, 0}, 6, 5, P2Faction},
+		{Loc{5, 1}, 7, 10, P1Faction},
+	} {
+		b.Set(r.loc, &Robot{ID: r.id, Health: r.health, Faction: r.faction})
+	}
+	b.NextID = 7
+
+	got := b.Update(
+		turnList(t, move(1, e), move(2, e), attack(4, e), testTurn{id: 7}),
+		turnList(t, move(3, w), guard(5), selfDestruct(6)),
+	)
+	want := []Event{
+		{Type: Guarded, Robot: 5, Loc: Loc{5, 4}},
+		{Type: Moved, Robot: 1, Loc: Loc{0, 0}, Target: Loc{1, 0}},
+		{Type: Blocked, Robot: 2, Loc: Loc{0, 2}, Target: Loc{1, 2}},
+		{Type: Blocked, Robot: 3, Loc: Loc{2, 2}, Target: Loc{1, 2}},
+		{Type: Collided, Robot: 2, Loc: Loc{0, 2}},
+		{Type: Damaged, Robot: 2, Loc: Loc{0, 2}, Damage: 5},
+		{Type: Collided, Robot: 3, Loc: Loc{2, 2}},
+		{Type: Damaged, Robot: 3, Loc: Loc{2, 2}, Damage: 5},
+		{Type: Attacked, Robot: 4, Other: 5, Loc: Loc{4, 4}, Target: Loc{5, 4}},
+		{Type: Damaged, Robot: 5, Other: 4, Loc: Loc{5, 4}, Damage: 5},
+		{Type: SelfDestructed, Robot: 6, Loc: Loc{4, 0}},
+		{Type: Damaged, Robot: 7, Other: 6, Loc: Loc{5, 1}, Damage: 15},
+		{Type: Died, Robot: 6, Loc: Loc{4, 0}},
+		{Type: Died, Robot: 7, Loc: Loc{5, 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v; want %v", got, want)
+	}
+
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal("capnp.NewMessage:", err)
+	}
+	wes, err := botapi.NewEvent_List(seg, int32(len(want)))
+	if err != nil {
+		t.Fatal("botapi.NewEvent_List:", err)
+	}
+	for i, e := range want {
+		e.ToWire(wes.At(i))
+		if got := EventFromWire(wes.At(i)); got != e {
+			t.Errorf("EventFromWire(ToWire(%v)) = %v", e, got)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	size := Loc{17, 17}
+	bc := BoardConfig{
+		Size:      size,
+		Spawner:   NewRandomSpawn(2),
+		CellTyper: NewCircleSpawn(size),
+		Seed:      7,
+	}
+	b := EmptyBoard(bc)
+	b.InitBoard(bc)
+	for i := 0; i < 5; i++ {
+		b.Update(benchTurns(t, b, P1Faction), benchTurns(t, b, P2Faction))
+	}
+
+	c := b.Clone()
+	// Play both through a round with random spawns, which should match.
+	for b.Round < 12 {
+		ta, tb := benchTurns(t, b, P1Faction), benchTurns(t, b, P2Faction)
+		be := b.Update(ta, tb)
+		ce := c.Update(ta, tb)
+		if !reflect.DeepEqual(be, ce) {
+			t.Fatalf("round %d: clone events = %v; want %v", b.Round, ce, be)
+		}
+	}
+	if !sameRobots(b, c) {
+		t.Fatalf("clone robots = %v; want %v", c.Locs, b.Locs)
+	}
+
+	// Changing the clone shouldn't change the original.
+	for loc, r := range c.Locs {
+		r.Health = 1
+		c.Set(loc, nil)
+		if got := b.At(loc); got == nil || got.Health == 1 {
+			t.Errorf("changing the clone at %s changed the original to %v", loc, got)
+		}
+		break
+	}
+}
+
+func TestParseMap(t *testing.T) {
+	const header = "name: tiny\nsize: 5x3\nsymmetry: horizontal\n\n"
+	tests := []struct {
+		desc string
+		text string
+		// The error should contain this, or be nil if it's empty
+		err string
+	}{
+		{"valid", header + "#...#\nS...S\n#...#\n", ""},
+		{"missing name", "size: 5x3\nsymmetry: horizontal\n\n#...#\nS...S\n#...#\n", "no name"},
+		{"missing symmetry", "name: tiny\nsize: 5x3\n\n#...#\nS...S\n#...#\n", "no symmetry"},
+		{"unknown symmetry", "name: tiny\nsize: 5x3\nsymmetry: sideways\n\n#...#\nS...S\n#...#\n", "unknown symmetry"},
+		{"bad size", "name: tiny\nsize: 5\nsymmetry: horizontal\n\n#...#\nS...S\n#...#\n", "bad size"},
+		{"too few rows", header + "#...#\nS...S\n", "2 rows"},
+		{"short row", header + "#...#\nS..S\n#...#\n", "row 2 has 4 cells"},
+		{"unknown cell", header + "#...#\nS.x.S\n#...#\n", "unknown cell"},
+		{"asymmetric", header + "#...#\nS...S\n##..#\n", "isn't horizontal symmetric"},
+		{"spawn in the middle", header + "#.S.#\nS...S\n#...#\n", "line of symmetry"},
+		{"no spawns", header + "#...#\n.....\n#...#\n", "no spawn points"},
+		{"unreachable", header + "#.#.#\nS#.#S\n#.#.#\n", "can't reach"},
+		{"not vertical", "name: tiny\nsize: 5x3\nsymmetry: vertical\n\n#...#\nS...S\n#...#\n", "on its line of symmetry"},
+		{"not rotational", "name: tiny\nsize: 5x3\nsymmetry: rotational\n\n#...#\nS...S\n##..#\n", "isn't rotational symmetric"},
+		{"rotational center", "name: tiny\nsize: 5x3\nsymmetry: rotational\n\n#...#\nS.S.S\n#...#\n", "on its line of symmetry"},
+		{"diagonal not square", "name: tiny\nsize: 5x3\nsymmetry: diagonal\n\n#...#\nS...S\n#...#\n", "isn't square"},
+		{"terrain", "hazard_damage: 10\ncover_multiplier: 0.25\nheal_amount: 0\n" + header + "#...#\nS...S\n#...#\n", ""},
+		{"bad hazard damage", "hazard_damage: lots\n" + header + "#...#\nS...S\n#...#\n", "bad hazard_damage"},
+		{"negative heal amount", "heal_amount: -1\n" + header + "#...#\nS...S\n#...#\n", "negative heal amount"},
+	}
+
+	for _, test := range tests {
+		m, err := ParseMap(strings.NewReader(test.text))
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: ParseMap: %v", test.desc, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: ParseMap error = %v; want one containing %q", test.desc, err, test.err)
+		case err == nil:
+			if m.Name != "tiny" || m.Size != (Loc{5, 3}) || m.Symmetry != Horizontal {
+				t.Errorf("%s: ParseMap = %s %s %s; want tiny (5, 3) horizontal", test.desc, m.Name, m.Size, m.Symmetry)
+			}
+		}
+	}
+}
+
+func TestParseMapJSON(t *testing.T) {
+	tm, err := ParseMap(strings.NewReader("name: tiny\nsize: 5x3\nsymmetry: horizontal\nhazard_damage: 10\nheal_amount: 0\n\n#...#\nS...S\n#...#\n"))
+	if err != nil {
+		t.Fatal("ParseMap:", err)
+	}
+	jm, err := ParseMapJSON(strings.NewReader(`{"name": "tiny", "width": 5, "height": 3, "symmetry": "horizontal",
+		"hazard_damage": 10, "heal_amount": 0, "rows": ["#...#", "S...S", "#...#"]}`))
+	if err != nil {
+		t.Fatal("ParseMapJSON:", err)
+	}
+	if !reflect.DeepEqual(tm, jm) {
+		t.Errorf("ParseMapJSON = %+v; want %+v", jm, tm)
+	}
+
+	// The map works as a Typer
+	bc := BoardConfig{Size: jm.Size, Spawner: AllSpawn, CellTyper: jm}
+	b := EmptyBoard(bc)
+	b.InitBoard(bc)
+	if b.At(Loc{0, 1}) == nil || b.At(Loc{4, 1}) == nil || len(b.Locs) != 2 {
+		t.Errorf("robots on a new board = %v; want robots at (0, 1) and (4, 1)", b.Locs)
+	}
+
+	// The map's terrain numbers override the rules, and the rest are left
+	// alone.
+	want := DefaultRules
+	want.HazardDamage, want.HealAmount = 10, 0
+	if b.Rules != want {
+		t.Errorf("rules on the map = %+v; want %+v", b.Rules, want)
+	}
+}
+
+func TestSymmetrySpawns(t *testing.T) {
+	tests := []struct {
+		sym    Symmetry
+		rows   []string
+		p1, p2 []Loc
+	}{
+		{
+			sym:  Horizontal,
+			rows: []string{"#...#", "S...S", "#...#"},
+			p1:   []Loc{{0, 1}},
+			p2:   []Loc{{4, 1}},
+		},
+		{
+			sym:  Vertical,
+			rows: []string{"#S#", "...", "...", "...", "#S#"},
+			p1:   []Loc{{1, 0}},
+			p2:   []Loc{{1, 4}},
+		},
+		{
+			sym:  Rotational,
+			rows: []string{"S...#", ".....", "#...S"},
+			p1:   []Loc{{0, 0}},
+			p2:   []Loc{{4, 2}},
+		},
+		{
+			sym:  Diagonal,
+			rows: []string{"#SS.", "S...", "S...", "...#"},
+			p1:   []Loc{{0, 1}, {0, 2}},
+			p2:   []Loc{{1, 0}, {2, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		m := &Map{
+			Name:     "test",
+			Size:     Loc{len(test.rows[0]), len(test.rows)},
+			Symmetry: test.sym,
+		}
+		if err := m.setRows(test.rows); err != nil {
+			t.Fatalf("%s: setRows: %v", test.sym, err)
+		}
+		if err := m.Validate(); err != nil {
+			t.Errorf("%s: Validate: %v", test.sym, err)
+		}
+
+		bc := BoardConfig{Size: m.Size, Spawner: AllSpawn, CellTyper: m, Symmetry: test.sym}
+		b := EmptyBoard(bc)
+		b.InitBoard(bc)
+		if len(b.Locs) != len(test.p1)+len(test.p2) {
+			t.Errorf("%s: robots = %v; want %d", test.sym, b.Locs, len(test.p1)+len(test.p2))
+		}
+		for faction, locs := range map[int][]Loc{P1Faction: test.p1, P2Faction: test.p2} {
+			for _, loc := range locs {
+				if r := b.At(loc); r == nil || r.Faction != faction {
+					t.Errorf("%s: robot at %s = %v; want one for faction %d", test.sym, loc, r, faction)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	for _, sym := range []Symmetry{Horizontal, Vertical, Rotational, Diagonal, Quartered} {
+		g := &Generator{
+			Name:     "random",
+			Size:     Loc{17, 17},
+			Symmetry: sym,
+			Density:  0.2,
+			Spawns:   6,
+		}
+		if err := g.Check(); err != nil {
+			t.Fatalf("%s: Check: %v", sym, err)
+		}
+		open := *g
+		open.Density = 0
+		if err := open.Check(); err != nil {
+			t.Fatalf("%s: Check with no walls: %v", sym, err)
+		}
+		openWalls := 0
+		om := open.Generate(rand.New(rand.NewSource(0)))
+		for x := 0; x < om.Size.X; x++ {
+			for y := 0; y < om.Size.Y; y++ {
+				if om.Type(x, y) == Invalid {
+					openWalls++
+				}
+			}
+		}
+
+		for seed := int64(0); seed < 20; seed++ {
+			m := g.Generate(rand.New(rand.NewSource(seed)))
+			if err := m.Validate(); err != nil {
+				t.Fatalf("%s, seed %d: Validate: %v", sym, seed, err)
+			}
+			if err := m.checkSpawnDistances(); err != nil {
+				t.Errorf("%s, seed %d: %v", sym, seed, err)
+			}
+
+			var valid, spawns []Loc
+			walls := 0
+			for x := 0; x < m.Size.X; x++ {
+				for y := 0; y < m.Size.Y; y++ {
+					loc := Loc{x, y}
+					switch m.Type(x, y) {
+					case Invalid:
+						walls++
+					case Spawn:
+						spawns = append(spawns, loc)
+						fallthrough
+					default:
+						valid = append(valid, loc)
+					}
+				}
+			}
+			if want := sym.Players() * g.Spawns; len(spawns) != want {
+				t.Errorf("%s, seed %d: %d spawn points; want %d", sym, seed, len(spawns), want)
+			}
+			if reached := m.reachable(valid[:1]); len(reached) != len(valid) {
+				t.Errorf("%s, seed %d: %d of %d valid cells are connected", sym, seed, len(reached), len(valid))
+			}
+			if walls <= openWalls {
+				t.Errorf("%s, seed %d: %d invalid cells, no more than the %d in an open arena", sym, seed, walls, openWalls)
+			}
+
+			if again := g.Generate(rand.New(rand.NewSource(seed))); !reflect.DeepEqual(m, again) {
+				t.Errorf("%s, seed %d: generated different maps from the same seed", sym, seed)
+			}
+		}
+	}
+}
+
+func TestGeneratorCheck(t *testing.T) {
+	good := Generator{Name: "random", Size: Loc{17, 17}, Symmetry: Quartered, Density: 0.2, Spawns: 5}
+	tests := []struct {
+		desc   string
+		change func(g *Generator)
+		ok     bool
+	}{
+		{"good", func(g *Generator) {}, true},
+		{"no name", func(g *Generator) { g.Name = "" }, false},
+		{"no symmetry", func(g *Generator) { g.Symmetry = UnknownSymmetry }, false},
+		{"too small", func(g *Generator) { g.Size = Loc{2, 2} }, false},
+		{"not square", func(g *Generator) { g.Size = Loc{17, 13} }, false},
+		{"negative density", func(g *Generator) { g.Density = -0.1 }, false},
+		{"full density", func(g *Generator) { g.Density = 1 }, false},
+		{"no spawns", func(g *Generator) { g.Spawns = 0 }, false},
+		{"too many spawns", func(g *Generator) { g.Spawns = 100 }, false},
+	}
+	for _, test := range tests {
+		g := good
+		test.change(&g)
+		if err := g.Check(); (err == nil) != test.ok {
+			t.Errorf("%s: Check = %v; want ok = %t", test.desc, err, test.ok)
+		}
+		// Whether or not it checks out, it shouldn't panic.
+		m := g.Generate(rand.New(rand.NewSource(0)))
+		if test.ok {
+			if err := m.Validate(); err != nil {
+				t.Errorf("%s: Validate: %v", test.desc, err)
+			}
+			if got, want := g.Type(8, 8), m.Type(8, 8); got != want {
+				t.Errorf("%s: Type(8, 8) = %v; want %v, like seed 0", test.desc, got, want)
+			}
+		} else if got := g.Type(8, 8); got != Invalid {
+			t.Errorf("%s: Type(8, 8) = %v; want %v", test.desc, got, Invalid)
+		}
+	}
+}
+
+func TestFactions(t *testing.T) {
+	m := &Map{Name: "test", Size: Loc{5, 5}, Symmetry: Quartered}
+	if err := m.setRows([]string{
+		"S...S",
+		"..S..",
+		".S#S.",
+		"..S..",
+		"S...S",
+	}); err != nil {
+		t.Fatal("setRows:", err)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatal("Validate:", err)
+	}
+
+	tests := []struct {
+		factions int
+		want     map[Loc]int
+	}{
+		{2, map[Loc]int{{0, 0}: 1, {4, 4}: 2}},
+		{3, map[Loc]int{{0, 0}: 1, {4, 0}: 2, {4, 4}: 3}},
+		{4, map[Loc]int{{0, 0}: 1, {4, 0}: 2, {4, 4}: 3, {0, 4}: 4}},
+	}
+	for _, test := range tests {
+		bc := BoardConfig{Size: m.Size, Spawner: AllSpawn, CellTyper: m, Symmetry: Quartered, Factions: test.factions}
+		b := EmptyBoard(bc)
+		b.InitBoard(bc)
+		got := make(map[Loc]int)
+		for loc, r := range b.Locs {
+			got[loc] = r.Faction
+		}
+		// The corners, (0, 0) and its images, all go to different players.
+		for loc, f := range test.want {
+			if got[loc] != f {
+				t.Errorf("%d players: faction at %s = %d; want %d", test.factions, loc, got[loc], f)
+			}
+		}
+		for f := 1; f <= test.factions; f++ {
+			if n := b.BotCount(f); n != len(b.Locs)/test.factions {
+				t.Errorf("%d players: faction %d has %d of the %d robots", test.factions, f, n, len(b.Locs))
+			}
+		}
+
+		// The game goes on until only one player is left.
+		for f := test.factions; f > 1; f-- {
+			if b.IsFinished() {
+				t.Errorf("%d players: finished with %d players left", test.factions, f)
+			}
+			for loc, r := range b.Locs {
+				if r.Faction == f {
+					b.Set(loc, nil)
+				}
+			}
+			var turns [][]Turn
+			for _, loc := range b.sortedLocs() {
+				turns = append(turns, []Turn{{ID: b.At(loc).ID, Kind: botapi.Turn_Which_wait}})
+			}
+			b.Apply(turns...)
+		}
+		if !b.IsFinished() {
+			t.Errorf("%d players: not finished with one player left", test.factions)
+		}
+		if res := b.Result(); res.Reason != Elimination || res.Winner != P1Faction {
+			t.Errorf("%d players: Result() = %+v; want P1 to win by elimination", test.factions, res)
+		}
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	tests := []struct {
+		factions, teams int
+		// forfeits are the factions that forfeit, in order, and the game should
+		// be over after the last one.
+		forfeits []int
+		winner   int
+	}{
+		{2, 0, []int{2}, P1Faction},
+		{3, 0, []int{1, 3}, 2},
+		{4, 2, []int{1, 2}, 2},
+	}
+	for _, test := range tests {
+		b := openBoard(Loc{5, 5})
+		b.Factions, b.Teams = test.factions, test.teams
+		for f := 1; f <= test.factions; f++ {
+			b.Set(Loc{f - 1, 0}, &Robot{ID: RobotID(f), Health: 50, Faction: f})
+		}
+		b.NextID = RobotID(test.factions)
+
+		for i, f := range test.forfeits {
+			want := []Event{{Type: Died, Robot: RobotID(f), Loc: Loc{f - 1, 0}}}
+			if evs := b.Forfeit(f); !reflect.DeepEqual(evs, want) {
+				t.Errorf("%d players: Forfeit(%d) = %v; want %v", test.factions, f, evs, want)
+			}
+			if i < len(test.forfeits)-1 && b.IsFinished() {
+				t.Errorf("%d players: finished after %d forfeited", test.factions, f)
+			}
+		}
+		if !b.IsFinished() {
+			t.Errorf("%d players: not finished after %v forfeited", test.factions, test.forfeits)
+		}
+		if res := b.Result(); res.Reason != Forfeit || res.Winner != test.winner {
+			t.Errorf("%d players: Result() = %+v; want %d to win by forfeit", test.factions, res, test.winner)
+		}
+	}
+}
+
+func TestResultToWire(t *testing.T) {
+	b := openBoard(Loc{5, 5})
+	b.Set(Loc{0, 0}, &Robot{ID: 1, Health: 50, Faction: P1Faction})
+	b.Set(Loc{1, 0}, &Robot{ID: 2, Health: 50, Faction: P1Faction})
+	b.Set(Loc{4, 4}, &Robot{ID: 3, Health: 50, Faction: P2Faction})
+	b.NextID = 3
+
+	tests := []struct {
+		// forfeit is a faction that forfeits first, or 0.
+		forfeit int
+		reason  botapi.EndReason
+		winner  uint8
+		scores  []int32
+	}{
+		// The game isn't over yet, so it was aborted and nobody won.
+		{0, botapi.EndReason_aborted, 0, []int32{2, 1}},
+		{P2Faction, botapi.EndReason_forfeit, P1Faction, []int32{2, 0}},
+	}
+	for _, test := range tests {
+		if test.forfeit != 0 {
+			b.Forfeit(test.forfeit)
+		}
+		_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+		if err != nil {
+			t.Fatal("capnp.NewMessage:", err)
+		}
+		res, err := botapi.NewRootGameResult(seg)
+		if err != nil {
+			t.Fatal("botapi.NewRootGameResult:", err)
+		}
+		if err := b.ResultToWire(res, P1Faction); err != nil {
+			t.Fatal("ResultToWire:", err)
+		}
+
+		if res.Reason() != test.reason || res.Winner() != test.winner {
+			t.Errorf("forfeit %d: reason, winner = %v, %d; want %v, %d", test.forfeit, res.Reason(), res.Winner(), test.reason, test.winner)
+		}
+		scores, err := res.Scores()
+		if err != nil {
+			t.Fatal("Scores:", err)
+		}
+		var got []int32
+		for i := 0; i < scores.Len(); i++ {
+			got = append(got, scores.At(i))
+		}
+		if !reflect.DeepEqual(got, test.scores) {
+			t.Errorf("forfeit %d: scores = %v; want %v", test.forfeit, got, test.scores)
+		}
+		ib, err := res.Board()
+		if err != nil {
+			t.Fatal("Board:", err)
+		}
+		if ib.Player() != P1Faction {
+			t.Errorf("forfeit %d: board is for player %d; want %d", test.forfeit, ib.Player(), P1Faction)
+		}
+	}
+}
+
+func TestTeams(t *testing.T) {
+	b := EmptyBoard(BoardConfig{Size: Loc{5, 5}, Factions: 4, Teams: 2})
+	for x := range b.Cells {
+		for y := range b.Cells[x] {
+			b.Cells[x][y] = Valid
+		}
+	}
+	b.Rules.FriendlyFire = false
+	b.Set(Loc{1, 1}, &Robot{ID: 1, Health: 50, Faction: 1})
+	b.Set(Loc{2, 1}, &Robot{ID: 2, Health: 50, Faction: 2})
+	b.Set(Loc{1, 2}, &Robot{ID: 3, Health: 50, Faction: 3})
+	b.Set(Loc{4, 4}, &Robot{ID: 4, Health: 50, Faction: 4})
+	b.NextID = 4
+
+	for f, want := range map[int]int{1: 1, 2: 1, 3: 2, 4: 2} {
+		if got := b.Team(f); got != want {
+			t.Errorf("Team(%d) = %d; want %d", f, got, want)
+		}
+	}
+	if got := b.Sides(); got != 2 {
+		t.Errorf("Sides() = %d; want 2", got)
+	}
+
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal("capnp.NewMessage:", err)
+	}
+	wb, err := botapi.NewRootBoard(seg)
+	if err != nil {
+		t.Fatal("botapi.NewRootBoard:", err)
+	}
+	if err := b.ToWire(wb, 1); err != nil {
+		t.Fatal("b.ToWire:", err)
+	}
+	robots, err := wb.Robots()
+	if err != nil {
+		t.Fatal("wb.Robots:", err)
+	}
+	wantFactions := []botapi.Faction{botapi.Faction_mine, botapi.Faction_ally, botapi.Faction_opponent, botapi.Faction_opponent}
+	for i, want := range wantFactions {
+		if got := robots.At(i).Faction(); got != want {
+			t.Errorf("robots[%d].faction = %v; want %v", i, got, want)
+		}
+	}
+
+	// Robot 1 attacks its ally, which doesn't hurt without friendly fire, and
+	// gets attacked by the opponent next to it.
+	b.Update(
+		turnList(t, attack(1, botapi.Direction_east)),
+		turnList(t, testTurn{id: 2}),
+		turnList(t, attack(3, botapi.Direction_north)),
+		turnList(t, testTurn{id: 4}),
+	)
+	if got := b.At(Loc{2, 1}).Health; got != 50 {
+		t.Errorf("ally health = %d; want 50", got)
+	}
+	if got := b.At(Loc{1, 1}).Health; got != 40 {
+		t.Errorf("attacked robot health = %d; want 40", got)
+	}
+	if got := b.TeamCount(1); got != 2 {
+		t.Errorf("TeamCount(1) = %d; want 2", got)
+	}
+	if b.IsFinished() {
+		t.Error("finished with both teams left")
+	}
+
+	// Once one team is gone, the other wins, even though one of its players
+	// has no robots left.
+	b.Set(Loc{1, 1}, nil)
+	b.Set(Loc{1, 2}, nil)
+	b.Set(Loc{4, 4}, nil)
+	b.Update(turnList(t), turnList(t, testTurn{id: 2}), turnList(t), turnList(t))
+	if !b.IsFinished() {
+		t.Error("not finished with one team left")
+	}
+	if res := b.Result(); res.Reason != Elimination || res.Winner != 1 {
+		t.Errorf("Result() = %+v; want team 1 to win by elimination", res)
+	}
+}
+
+func TestVision(t *testing.T) {
+	tests := []struct {
+		metric Metric
+		// The IDs of the robots faction 1 gets sent
+		want []RobotID
+	}{
+		{Manhattan, []RobotID{1, 2, 3}},
+		{Chebyshev, []RobotID{1, 2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		b := openBoard(Loc{8, 8})
+		b.Rules.VisionRadius = 2
+		b.Rules.VisionMetric = test.metric
+		b.Set(Loc{1, 1}, &Robot{ID: 1, Health: 50, Faction: P1Faction})
+		b.Set(Loc{7, 7}, &Robot{ID: 2, Health: 50, Faction: P1Faction})
+		b.Set(Loc{1, 3}, &Robot{ID: 3, Health: 50, Faction: P2Faction})
+		b.Set(Loc{3, 3}, &Robot{ID: 4, Health: 50, Faction: P2Faction})
+		b.Set(Loc{4, 0}, &Robot{ID: 5, Health: 50, Faction: P2Faction})
+
+		for _, tt := range []struct {
+			faction int
+			want    []RobotID
+		}{
+			{P1Faction, test.want},
+			{Spectator, []RobotID{1, 2, 3, 4, 5}},
+		} {
+			_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+			if err != nil {
+				t.Fatal("capnp.NewMessage:", err)
+			}
+			wb, err := botapi.NewRootBoard(seg)
+			if err != nil {
+				t.Fatal("botapi.NewRootBoard:", err)
+			}
+			if err := b.ToWire(wb, tt.faction); err != nil {
+				t.Fatal("b.ToWire:", err)
+			}
+			robots, err := wb.Robots()
+			if err != nil {
+				t.Fatal("wb.Robots:", err)
+			}
+			var got []RobotID
+			for i := 0; i < robots.Len(); i++ {
+				got = append(got, RobotID(robots.At(i).Id()))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s vision, faction %d: sent robots %v; want %v", test.metric, tt.faction, got, tt.want)
+			}
+		}
+
+		if ci := b.ViewAtXY(P1Faction, 4, 0); !ci.Fogged || ci.Bot != nil {
+			t.Errorf("%s vision: ViewAtXY(P1Faction, 4, 0) = %+v; want fogged and empty", test.metric, ci)
+		}
+		if ci := b.ViewAtXY(P2Faction, 7, 7); !ci.Fogged || ci.Bot != nil {
+			t.Errorf("%s vision: ViewAtXY(P2Faction, 7, 7) = %+v; want fogged with P1's robot hidden", test.metric, ci)
+		}
+		if ci := b.ViewAtXY(Spectator, 4, 0); ci.Fogged || ci.Bot == nil {
+			t.Errorf("%s vision: ViewAtXY(Spectator, 4, 0) = %+v; want the robot there", test.metric, ci)
+		}
+	}
+}
+
+func TestTerrain(t *testing.T) {
+	b := openBoard(Loc{6, 3})
+	b.Cells[0][0] = HazardCell
+	b.Cells[1][0] = HealingCell
+	b.Cells[2][0] = HealingCell
+	b.Cells[4][0] = CoverCell
+	b.Cells[2][2] = SlowCell
+	b.Set(Loc{0, 0}, &Robot{ID: 1, Health: 50, Faction: P1Faction})
+	b.Set(Loc{1, 0}, &Robot{ID: 2, Health: 40, Faction: P1Faction})
+	b.Set(Loc{2, 0}, &Robot{ID: 3, Health: 48, Faction: P1Faction})
+	b.Set(Loc{4, 0}, &Robot{ID: 4, Health: 50, Faction: P1Faction})
+	b.Set(Loc{1, 2}, &Robot{ID: 5, Health: 50, Faction: P1Faction})
+	b.Set(Loc{5, 0}, &Robot{ID: 6, Health: 50, Faction: P2Faction})
+	b.NextID = 6
+
+	evs := b.Update(
+		turnList(t, testTurn{id: 1}, testTurn{id: 2}, testTurn{id: 3}, testTurn{id: 4}, move(5, botapi.Direction_east)),
+		turnList(t, attack(6, botapi.Direction_west)),
+	)
+
+	for id, want := range map[RobotID]int{1: 45, 2: 45, 3: 50, 4: 45} {
+		if _, r := b.fromID(id); r.Health != want {
+			t.Errorf("robot %d health = %d; want %d", id, r.Health, want)
+		}
+	}
+	if r := b.At(Loc{1, 2}); r == nil || r.ID != 5 {
+		t.Fatalf("robot 5 moved into the slow cell in one round")
+	}
+	if e := b.At(Loc{1, 2}).Entering; e == nil || *e != (Loc{2, 2}) {
+		t.Errorf("robot 5 entering %v; want (2, 2)", e)
+	}
+	var slowed bool
+	for _, e := range evs {
+		if e.Type == Slowed && e.Robot == 5 {
+			slowed = true
+		}
+	}
+	if !slowed {
+		t.Errorf("no Slowed event for robot 5 in %v", evs)
+	}
+
+	// The robot on the hazard keeps getting hurt, and the second round of
+	// moving gets the slow robot in.
+	b.Update(
+		turnList(t, testTurn{id: 1}, testTurn{id: 2}, testTurn{id: 3}, testTurn{id: 4}, move(5, botapi.Direction_east)),
+		turnList(t, testTurn{id: 6}),
+	)
+	if _, r := b.fromID(1); r.Health != 40 {
+		t.Errorf("robot 1 health after two rounds = %d; want 40", r.Health)
+	}
+	if r := b.At(Loc{2, 2}); r == nil || r.ID != 5 {
+		t.Errorf("robot 5 didn't get into the slow cell in two rounds")
+	} else if r.Entering != nil {
+		t.Errorf("robot 5 still entering %v", r.Entering)
+	}
+
+	// Stopping partway starts it over.
+	b.Update(
+		turnList(t, testTurn{id: 1}, testTurn{id: 2}, testTurn{id: 3}, testTurn{id: 4}, move(5, botapi.Direction_west)),
+		turnList(t, testTurn{id: 6}),
+	)
+	b.Update(
+		turnList(t, testTurn{id: 1}, testTurn{id: 2}, testTurn{id: 3}, testTurn{id: 4}, move(5, botapi.Direction_east)),
+		turnList(t, testTurn{id: 6}),
+	)
+	b.Update(
+		turnList(t, testTurn{id: 1}, testTurn{id: 2}, testTurn{id: 3}, testTurn{id: 4}, testTurn{id: 5}),
+		turnList(t, testTurn{id: 6}),
+	)
+	b.Update(
+		turnList(t, testTurn{id: 1}, testTurn{id: 2}, testTurn{id: 3}, testTurn{id: 4}, move(5, botapi.Direction_east)),
+		turnList(t, testTurn{id: 6}),
+	)
+	if r := b.At(Loc{1, 2}); r == nil || r.ID != 5 {
+		t.Errorf("robot 5 got into the slow cell after waiting partway")
+	}
+}
+
+func TestBoardIndex(t *testing.T) {
+	size := Loc{17, 17}
+	bc := BoardConfig{
+		Size:      size,
+		Spawner:   AllSpawn,
+		CellTyper: NewCircleSpawn(size),
+	}
+	b := EmptyBoard(bc)
+	b.InitBoard(bc)
+	for !b.IsFinished() {
+		b.Update(benchTurns(t, b, P1Faction), benchTurns(t, b, P2Faction))
+
+		if len(b.ids) != len(b.Locs) {
+			t.Fatalf("round %d: %d robots indexed, %d on the board", b.Round, len(b.ids), len(b.Locs))
+		}
+		for loc, r := range b.Locs {
+			if got := b.ids[r.ID]; got != loc {
+				t.Fatalf("round %d: robot %s indexed at %s, on the board at %s", b.Round, r.ID, got, loc)
+			}
+		}
+		for x := 0; x < size.X; x++ {
+			for y := 0; y < size.Y; y++ {
+				loc := Loc{x, y}
+				if got, want := b.At(loc), b.Locs[loc]; got != want {
+					t.Fatalf("round %d: At(%s) = %v; want %v", b.Round, loc, got, want)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkGame plays full games where every spawn point gets a robot every
+// spawn round, so the board gets crowded.
+func BenchmarkGame(b *testing.B) {
+	size := Loc{17, 17}
+	bc := BoardConfig{
+		Size:      size,
+		Spawner:   AllSpawn,
+		CellTyper: NewCircleSpawn(size),
+	}
+	for i := 0; i < b.N; i++ {
+		bc.Seed = int64(i)
+		board := EmptyBoard(bc)
+		board.InitBoard(bc)
+		for !board.IsFinished() {
+			board.Update(benchTurns(b, board, P1Faction), benchTurns(b, board, P2Faction))
+		}
+	}
+}
+
+// benchTurns has every robot in a faction attack an adjacent enemy if there is
+// one, and head for the center of the board otherwise.
+func benchTurns(tb testing.TB, b *Board, faction int) botapi.Turn_List {
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		tb.Fatal("capnp.NewMessage:", err)
+	}
+	locs := b.sortedLocs()
+	mine := locs[:0]
+	for _, loc := range locs {
+		if b.At(loc).Faction == faction {
+			mine = append(mine, loc)
+		}
+	}
+	tl, err := botapi.NewTurn_List(seg, int32(len(mine)))
+	if err != nil {
+		tb.Fatal("botapi.NewTurn_List:", err)
+	}
+
+	dirs := []botapi.Direction{
+		botapi.Direction_north,
+		botapi.Direction_south,
+		botapi.Direction_east,
+		botapi.Direction_west,
+	}
+	center := Loc{b.Size.X / 2, b.Size.Y / 2}
+	for i, loc := range mine {
+		t := tl.At(i)
+		t.SetId(uint32(b.At(loc).ID))
+		t.SetWait()
+		for _, d := range dirs {
+			x, y := directionOffsets(d)
+			if r := b.At(Loc{loc.X + x, loc.Y + y}); r != nil && r.Faction != faction {
+				t.SetAttack(d)
+				break
+			}
+		}
+		if t.Which() == botapi.Turn_Which_attack {
+			continue
+		}
+		switch {
+		case loc.X < center.X:
+			t.SetMove(botapi.Direction_east)
+		case loc.X > center.X:
+			t.SetMove(botapi.Direction_west)
+		case loc.Y < center.Y:
+			t.SetMove(botapi.Direction_south)
+		case loc.Y > center.Y:
+			t.SetMove(botapi.Direction_north)
+		}
+	}
+	return tl
+}
@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bcspragu/Gobots/botapi"
+)
+
+// EventType is the kind of thing that happened to a robot during a round.
+type EventType int
+
+const (
+	UnknownEvent   EventType = iota
+	Moved                    // Moved from Loc to Target
+	Blocked                  // Tried to move to Target, but stayed at Loc
+	Collided                 // Bumped into another robot, or got bumped into
+	Attacked                 // Attacked Target, hitting Other if it's set
+	Damaged                  // Lost Damage health, to Other if it's set
+	Guarded                  // Guarded for the round
+	SelfDestructed           // Blew up
+	Died                     // Was removed from the board
+	Spawned                  // Was put on the board
+	IllegalMove              // Tried to move to Target, which is off the board or a wall
+	IllegalAttack            // Tried to attack Target, which is off the board or a wall
+	Slowed                   // Spent the round getting partway into Target, a slow cell
+	Healed                   // Got Damage health back, from Other if it's set or a healing cell if not
+	RangedAttacked           // Attacked Target from range, hitting Other if it's set
+)
+
+// An Event is something that happened to a robot during a round.
+type Event struct {
+	Type  EventType
+	Robot RobotID
+	// Other is the other robot involved, or 0 if there isn't one.
+	Other RobotID
+	// Loc is where Robot was when the event happened.
+	Loc Loc
+	// Target is where Robot was moving to or attacking.
+	Target Loc
+	Damage int
+}
+
+var (
+	eventToWire = map[EventType]botapi.EventType{
+		UnknownEvent:   botapi.EventType_unknown,
+		Moved:          botapi.EventType_moved,
+		Blocked:        botapi.EventType_blocked,
+		Collided:       botapi.EventType_collided,
+		Attacked:       botapi.EventType_attacked,
+		Damaged:        botapi.EventType_damaged,
+		Guarded:        botapi.EventType_guarded,
+		SelfDestructed: botapi.EventType_selfDestructed,
+		Died:           botapi.EventType_died,
+		Spawned:        botapi.EventType_spawned,
+		IllegalMove:    botapi.EventType_illegalMove,
+		IllegalAttack:  botapi.EventType_illegalAttack,
+		Slowed:         botapi.EventType_slowed,
+		Healed:         botapi.EventType_healed,
+		RangedAttacked: botapi.EventType_rangedAttacked,
+	}
+
+	eventFromWire = map[botapi.EventType]EventType{
+		botapi.EventType_unknown:        UnknownEvent,
+		botapi.EventType_moved:          Moved,
+		botapi.EventType_blocked:        Blocked,
+		botapi.EventType_collided:       Collided,
+		botapi.EventType_attacked:       Attacked,
+		botapi.EventType_damaged:        Damaged,
+		botapi.EventType_guarded:        Guarded,
+		botapi.EventType_selfDestructed: SelfDestructed,
+		botapi.EventType_died:           Died,
+		botapi.EventType_spawned:        Spawned,
+		botapi.EventType_illegalMove:    IllegalMove,
+		botapi.EventType_illegalAttack:  IllegalAttack,
+		botapi.EventType_slowed:         Slowed,
+		botapi.EventType_healed:         Healed,
+		botapi.EventType_rangedAttacked: RangedAttacked,
+	}
+)
+
+func (e Event) String() string {
+	switch e.Type {
+	case Moved:
+		return fmt.Sprintf("robot %s moved from %s to %s", e.Robot, e.Loc, e.Target)
+	case Blocked:
+		return fmt.Sprintf("robot %s at %s couldn't move to %s", e.Robot, e.Loc, e.Target)
+	case Collided:
+		return fmt.Sprintf("robot %s collided at %s", e.Robot, e.Loc)
+	case Attacked:
+		if e.Other == 0 {
+			return fmt.Sprintf("robot %s at %s attacked %s", e.Robot, e.Loc, e.Target)
+		}
+		return fmt.Sprintf("robot %s at %s attacked robot %s at %s", e.Robot, e.Loc, e.Other, e.Target)
+	case Damaged:
+		if e.Other == 0 {
+			return fmt.Sprintf("robot %s at %s took %d damage", e.Robot, e.Loc, e.Damage)
+		}
+		return fmt.Sprintf("robot %s at %s took %d damage from robot %s", e.Robot, e.Loc, e.Damage, e.Other)
+	case Guarded:
+		return fmt.Sprintf("robot %s guarded at %s", e.Robot, e.Loc)
+	case SelfDestructed:
+		return fmt.Sprintf("robot %s self-destructed at %s", e.Robot, e.Loc)
+	case Died:
+		return fmt.Sprintf("robot %s died at %s", e.Robot, e.Loc)
+	case Spawned:
+		return fmt.Sprintf("robot %s spawned at %s", e.Robot, e.Loc)
+	case IllegalMove:
+		return fmt.Sprintf("robot %s at %s tried to move to %s, which it can't", e.Robot, e.Loc, e.Target)
+	case IllegalAttack:
+		return fmt.Sprintf("robot %s at %s tried to attack %s, which it can't", e.Robot, e.Loc, e.Target)
+	case Slowed:
+		return fmt.Sprintf("robot %s at %s started getting into %s", e.Robot, e.Loc, e.Target)
+	case Healed:
+		if e.Other == 0 {
+			return fmt.Sprintf("robot %s at %s healed %d", e.Robot, e.Loc, e.Damage)
+		}
+		return fmt.Sprintf("robot %s at %s was healed %d by robot %s", e.Robot, e.Loc, e.Damage, e.Other)
+	case RangedAttacked:
+		if e.Other == 0 {
+			return fmt.Sprintf("robot %s at %s fired at %s", e.Robot, e.Loc, e.Target)
+		}
+		return fmt.Sprintf("robot %s at %s fired at robot %s at %s", e.Robot, e.Loc, e.Other, e.Target)
+	}
+	return fmt.Sprintf("robot %s: unknown event", e.Robot)
+}
+
+// ToWire converts the event to the wire representation.
+func (e Event) ToWire(out botapi.Event) {
+	out.SetType(eventToWire[e.Type])
+	out.SetRobot(uint32(e.Robot))
+	out.SetOther(uint32(e.Other))
+	out.SetX(uint16(e.Loc.X))
+	out.SetY(uint16(e.Loc.Y))
+	out.SetTargetX(int16(e.Target.X))
+	out.SetTargetY(int16(e.Target.Y))
+	out.SetDamage(int32(e.Damage))
+}
+
+// EventFromWire converts the wire representation of an event.
+func EventFromWire(wire botapi.Event) Event {
+	return Event{
+		Type:   eventFromWire[wire.Type()],
+		Robot:  RobotID(wire.Robot()),
+		Other:  RobotID(wire.Other()),
+		Loc:    Loc{X: int(wire.X()), Y: int(wire.Y())},
+		Target: Loc{X: int(wire.TargetX()), Y: int(wire.TargetY())},
+		Damage: int(wire.Damage()),
+	}
+}
+
+// emit records an event for the round being played.
+func (b *Board) emit(e Event) {
+	b.events = append(b.events, e)
+}
+
+// sortEvents puts the events emitted since the nth one in robot order, for the
+// steps of a round that go through robots in no particular order.
+func (b *Board) sortEvents(n int) {
+	evs := b.events[n:]
+	sort.SliceStable(evs, func(i, j int) bool {
+		return evs[i].Robot < evs[j].Robot
+	})
+}
@@ -0,0 +1,303 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// maxGenerateTries is how many layouts a Generator tries before giving up on
+// walls and falling back to an open arena.
+const maxGenerateTries = 20
+
+// A Generator makes random arenas with walls in them. It's a SeededTyper, so
+// every board gets a fresh layout drawn from its seed, and replaying a seed
+// gives the same layout. Boards using a Generator need the same Size and
+// Symmetry as it.
+//
+// Every layout is checked like a Map is: the valid cells are all connected,
+// and since the players' parts mirror each other, everyone gets the same number
+// of spawn points at the same distances from the center.
+//
+// A Generator has to pass Check before it's used, and shouldn't be changed
+// after.
+type Generator struct {
+	// Name is what the generated maps are called.
+	Name     string
+	Size     Loc
+	Symmetry Symmetry
+
+	// Density is roughly the fraction of the arena to fill with walls. Past
+	// about 0.3, there's usually not much left.
+	Density float64
+
+	// Spawns is the number of spawn points each player gets.
+	Spawns int
+
+	// unseeded is the layout used when the Generator is used as a plain Typer.
+	unseeded *Map
+	// open is the arena without walls that Generate falls back to. Check makes
+	// both, so a checked Generator is never written to and can be shared.
+	open *Map
+}
+
+// Check makes sure the Generator can make maps, which it can't if its Density
+// isn't between 0 and 1, say, or its Size has no room for its Spawns.
+func (g *Generator) Check() error {
+	switch {
+	case g.Name == "":
+		return errors.New("engine: generator has no name")
+	case g.Symmetry == UnknownSymmetry:
+		return fmt.Errorf("engine: generator %q has no symmetry", g.Name)
+	case g.Size.X < 3 || g.Size.Y < 3:
+		return fmt.Errorf("engine: generator %q is %s, which is too small", g.Name, g.Size)
+	case (g.Symmetry == Diagonal || g.Symmetry == Quartered) && g.Size.X != g.Size.Y:
+		return fmt.Errorf("engine: generator %q is %s, but isn't square", g.Name, g.Symmetry)
+	case g.Density < 0 || g.Density >= 1:
+		return fmt.Errorf("engine: generator %q has density %v, which isn't between 0 and 1", g.Name, g.Density)
+	case g.Spawns < 1:
+		return fmt.Errorf("engine: generator %q has no spawn points", g.Name)
+	}
+	// An open arena is always fair, so if there's no room for one, there's no
+	// room for anything.
+	m, err := g.generate(rand.New(rand.NewSource(0)), 0)
+	if err != nil {
+		return fmt.Errorf("engine: generator %q can't make a map: %v", g.Name, err)
+	}
+	g.open = m
+	g.unseeded = g.Generate(rand.New(rand.NewSource(0)))
+	return nil
+}
+
+// Type returns the type of a cell in the layout generated with seed 0, for
+// boards that don't seed their Typers. Every cell of a Generator that hasn't
+// passed Check is invalid.
+func (g *Generator) Type(x, y int) CellType {
+	if g.unseeded == nil {
+		return Invalid
+	}
+	return g.unseeded.Type(x, y)
+}
+
+// Seeded returns a layout drawn from r.
+func (g *Generator) Seeded(r *rand.Rand) Typer {
+	return g.Generate(r)
+}
+
+// Generate returns a new layout drawn from r. A Generator that passed Check
+// always returns a valid map, falling back to an arena without walls if it
+// can't make a fair one with them. One that didn't returns an arena without
+// any cells robots can stand on.
+func (g *Generator) Generate(r *rand.Rand) *Map {
+	if g.open == nil {
+		return g.empty()
+	}
+	for i := 0; i < maxGenerateTries; i++ {
+		if m, err := g.generate(r, g.Density); err == nil {
+			return m
+		}
+	}
+	return g.open
+}
+
+// empty returns a map of the Generator's size where every cell is invalid.
+func (g *Generator) empty() *Map {
+	m := &Map{
+		Name:     g.Name,
+		Size:     g.Size,
+		Symmetry: g.Symmetry,
+		cells:    make([][]CellType, g.Size.X),
+	}
+	for x := range m.cells {
+		m.cells[x] = make([]CellType, g.Size.Y)
+	}
+	return m
+}
+
+func (g *Generator) generate(r *rand.Rand, density float64) (*Map, error) {
+	m := g.empty()
+
+	// Start with an open oval that fills the board, like the circle layouts.
+	cx, cy := float64(g.Size.X-1)/2, float64(g.Size.Y-1)/2
+	rx, ry := float64(g.Size.X-2)/2, float64(g.Size.Y-2)/2
+	var open int
+	for x := 0; x < g.Size.X; x++ {
+		for y := 0; y < g.Size.Y; y++ {
+			dx, dy := (float64(x)-cx)/rx, (float64(y)-cy)/ry
+			if math.Sqrt(dx*dx+dy*dy) < 1 {
+				m.cells[x][y] = Valid
+				open++
+			}
+		}
+	}
+
+	// Throw in short mirrored walls until enough of the arena is filled. Walls
+	// next to each other make chokepoints. The middle of the board is kept
+	// clear, so there's always something to fight over.
+	want := int(density * float64(open))
+	for walls, tries := 0, 0; walls < want && tries < 100*want; tries++ {
+		loc := Loc{r.Intn(g.Size.X), r.Intn(g.Size.Y)}
+		xOff, yOff := 1, 0
+		if r.Intn(2) == 0 {
+			xOff, yOff = 0, 1
+		}
+		for n := 2 + r.Intn(3); n > 0; n-- {
+			if math.Abs(float64(loc.X)-cx) <= 1 && math.Abs(float64(loc.Y)-cy) <= 1 {
+				break
+			}
+			if !m.inside(loc) || m.cells[loc.X][loc.Y] != Valid {
+				break
+			}
+			for _, l := range m.Symmetry.images(m.Size, loc) {
+				if m.cells[l.X][l.Y] == Valid {
+					m.cells[l.X][l.Y] = Invalid
+					walls++
+				}
+			}
+			loc = Loc{loc.X + xOff, loc.Y + yOff}
+		}
+	}
+
+	// Anything cut off from the middle is filled in, so all the valid cells
+	// are connected.
+	reached := m.reachable(m.center())
+	for x := 0; x < g.Size.X; x++ {
+		for y := 0; y < g.Size.Y; y++ {
+			if !reached[Loc{x, y}] {
+				m.cells[x][y] = Invalid
+			}
+		}
+	}
+
+	if err := g.placeSpawns(r, m); err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.checkSpawnDistances(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// placeSpawns puts the first player's spawn points on the edge of their part
+// of the arena, as far from the center as possible, and mirrors them.
+func (g *Generator) placeSpawns(r *rand.Rand, m *Map) error {
+	dist := m.distances(m.center())
+	var edge []Loc
+	for x := 0; x < g.Size.X; x++ {
+		for y := 0; y < g.Size.Y; y++ {
+			loc := Loc{x, y}
+			if m.cells[x][y] == Valid && m.Symmetry.firstHalf(m.Size, loc) && m.onEdge(loc) {
+				edge = append(edge, loc)
+			}
+		}
+	}
+	if len(edge) < g.Spawns {
+		return fmt.Errorf("only room for %d spawn points, want %d", len(edge), g.Spawns)
+	}
+
+	r.Shuffle(len(edge), func(i, j int) { edge[i], edge[j] = edge[j], edge[i] })
+	sort.SliceStable(edge, func(i, j int) bool {
+		return dist[edge[i]] > dist[edge[j]]
+	})
+	for _, loc := range edge[:g.Spawns] {
+		for _, l := range m.Symmetry.images(m.Size, loc) {
+			m.cells[l.X][l.Y] = Spawn
+		}
+	}
+	return nil
+}
+
+// checkSpawnDistances makes sure every player's spawn points are the same
+// number of steps from the center.
+func (m *Map) checkSpawnDistances() error {
+	dist := m.distances(m.center())
+	for x := range m.cells {
+		for y, c := range m.cells[x] {
+			loc := Loc{x, y}
+			if c != Spawn || !m.Symmetry.firstHalf(m.Size, loc) {
+				continue
+			}
+			for _, l := range m.Symmetry.images(m.Size, loc)[1:] {
+				if dist[l] != dist[loc] {
+					return fmt.Errorf("map %q has spawn points %s and %s at different distances from the center", m.Name, loc, l)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// center returns the cells in the middle of the map, which is more than one
+// when a side has even length.
+func (m *Map) center() []Loc {
+	xs := []int{(m.Size.X - 1) / 2, m.Size.X / 2}
+	ys := []int{(m.Size.Y - 1) / 2, m.Size.Y / 2}
+	seen := make(map[Loc]bool)
+	var locs []Loc
+	for _, x := range xs {
+		for _, y := range ys {
+			loc := Loc{x, y}
+			if !seen[loc] {
+				seen[loc] = true
+				locs = append(locs, loc)
+			}
+		}
+	}
+	return locs
+}
+
+// distances returns the number of steps from the given locations to every cell
+// that can be reached from them.
+func (m *Map) distances(from []Loc) map[Loc]int {
+	dist := make(map[Loc]int)
+	var queue []Loc
+	for _, loc := range from {
+		if m.inside(loc) && m.cells[loc.X][loc.Y] != Invalid {
+			dist[loc] = 0
+			queue = append(queue, loc)
+		}
+	}
+	for len(queue) > 0 {
+		loc := queue[0]
+		queue = queue[1:]
+		for _, next := range m.neighbors(loc) {
+			if _, ok := dist[next]; ok {
+				continue
+			}
+			dist[next] = dist[loc] + 1
+			queue = append(queue, next)
+		}
+	}
+	return dist
+}
+
+// neighbors returns the cells next to loc that a robot could move to.
+func (m *Map) neighbors(loc Loc) []Loc {
+	var locs []Loc
+	for _, next := range []Loc{
+		{loc.X - 1, loc.Y},
+		{loc.X + 1, loc.Y},
+		{loc.X, loc.Y - 1},
+		{loc.X, loc.Y + 1},
+	} {
+		if m.inside(next) && m.cells[next.X][next.Y] != Invalid {
+			locs = append(locs, next)
+		}
+	}
+	return locs
+}
+
+// onEdge reports whether loc is next to a wall or the side of the map.
+func (m *Map) onEdge(loc Loc) bool {
+	return len(m.neighbors(loc)) < 4
+}
+
+// inside reports whether loc is on the map.
+func (m *Map) inside(loc Loc) bool {
+	return loc.X >= 0 && loc.X < m.Size.X && loc.Y >= 0 && loc.Y < m.Size.Y
+}
@@ -0,0 +1,59 @@
+package engine
+
+import "github.com/bcspragu/Gobots/botapi"
+
+// IllegalActions counts the actions a faction's robots tried that the board
+// doesn't allow. They usually mean a bot has a pathing bug.
+type IllegalActions struct {
+	OffBoard   int // Moves off the edge of the board
+	IntoWall   int // Moves into an invalid cell
+	AttackWall int // Attacks on a cell that's invalid or off the board
+}
+
+// Total returns the number of illegal actions of every kind.
+func (ia IllegalActions) Total() int {
+	return ia.OffBoard + ia.IntoWall + ia.AttackWall
+}
+
+// checkIllegal finds the moves and attacks that go somewhere no robot can be,
+// counts them whatever the rules say, and punishes them as the rules say. The
+// robots that tried them stay put either way.
+func (b *Board) checkIllegal(moves []botMove) {
+	n := len(b.events)
+	for _, m := range moves {
+		switch m.Turn.Kind {
+		case botapi.Turn_Which_move, botapi.Turn_Which_attack, botapi.Turn_Which_rangedAttack:
+		default:
+			continue
+		}
+		target := m.target()
+		if b.isValidLoc(target) {
+			continue
+		}
+
+		if b.Illegal == nil {
+			b.Illegal = make(map[int]IllegalActions)
+		}
+		ia := b.Illegal[m.Bot.Faction]
+		e := Event{Robot: m.Bot.ID, Loc: m.Location, Target: target}
+		switch {
+		case m.Turn.Kind != botapi.Turn_Which_move:
+			ia.AttackWall++
+			e.Type = IllegalAttack
+		case target.X < 0 || target.X >= b.Size.X || target.Y < 0 || target.Y >= b.Size.Y:
+			ia.OffBoard++
+			e.Type = IllegalMove
+		default:
+			ia.IntoWall++
+			e.Type = IllegalMove
+		}
+		b.Illegal[m.Bot.Faction] = ia
+
+		if b.Rules.IllegalPenalty == NoPenalty {
+			continue
+		}
+		b.emit(e)
+		b.hurtBot(m, Penalized, nil)
+	}
+	b.sortEvents(n)
+}
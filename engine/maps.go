@@ -0,0 +1,370 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// A Map is a board layout loaded from a map file. It's a Typer, so it can be
+// used as the CellTyper of a BoardConfig with the same Size.
+//
+// The text format is a header of "key: value" lines giving the name, size and
+// symmetry of the map, a blank line, and then a row of cells for each line,
+// where '#' is invalid, '.' is valid, and 'S' is a spawn point. Terrain cells
+// are '^' for hazards, '%' for cover, '~' for slow cells and '+' for healing
+// cells, see HazardCell and the others for what they do. The header can also
+// set the map's own hazard_damage, cover_multiplier and heal_amount:
+//
+//	name: tiny
+//	size: 5x3
+//	symmetry: horizontal
+//	hazard_damage: 10
+//
+//	#...#
+//	S...S
+//	#...#
+//
+// The JSON format has the same fields:
+//
+//	{"name": "tiny", "width": 5, "height": 3, "symmetry": "horizontal",
+//	 "hazard_damage": 10, "rows": ["#...#", "S...S", "#...#"]}
+type Map struct {
+	Name     string
+	Size     Loc
+	Symmetry Symmetry
+
+	// Terrain is the map's own numbers for its terrain cells.
+	Terrain Terrain
+
+	cells [][]CellType
+}
+
+// Terrain overrides the terrain numbers of the rules a game is played with,
+// so a map can make its hazards deadlier, say. Nil ones are left as the rules
+// have them.
+type Terrain struct {
+	HazardDamage    *int
+	CoverMultiplier *float64
+	HealAmount      *int
+}
+
+// apply returns the rules with the terrain's numbers in them.
+func (t Terrain) apply(rs RuleSet) RuleSet {
+	if t.HazardDamage != nil {
+		rs.HazardDamage = *t.HazardDamage
+	}
+	if t.CoverMultiplier != nil {
+		rs.CoverMultiplier = *t.CoverMultiplier
+	}
+	if t.HealAmount != nil {
+		rs.HealAmount = *t.HealAmount
+	}
+	return rs
+}
+
+// check makes sure none of the terrain's numbers are negative.
+func (t Terrain) check() error {
+	switch {
+	case t.HazardDamage != nil && *t.HazardDamage < 0:
+		return fmt.Errorf("negative hazard damage %d", *t.HazardDamage)
+	case t.CoverMultiplier != nil && *t.CoverMultiplier < 0:
+		return fmt.Errorf("negative cover multiplier %v", *t.CoverMultiplier)
+	case t.HealAmount != nil && *t.HealAmount < 0:
+		return fmt.Errorf("negative heal amount %d", *t.HealAmount)
+	}
+	return nil
+}
+
+// Type returns the type of a cell, which makes a Map a Typer.
+func (m *Map) Type(x, y int) CellType {
+	return m.cells[x][y]
+}
+
+var cellChars = map[rune]CellType{
+	'#': Invalid,
+	'.': Valid,
+	'S': Spawn,
+	'^': HazardCell,
+	'%': CoverCell,
+	'~': SlowCell,
+	'+': HealingCell,
+}
+
+// ParseMap reads a map in the text format, and checks that it's playable.
+func ParseMap(r io.Reader) (*Map, error) {
+	var (
+		m    Map
+		rows []string
+		sc   = bufio.NewScanner(r)
+	)
+	// The header ends at the first blank line.
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			break
+		}
+		i := strings.Index(line, ":")
+		if i == -1 {
+			return nil, fmt.Errorf("bad header line %q, want \"key: value\"", line)
+		}
+		key, val := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch key {
+		case "name":
+			m.Name = val
+		case "size":
+			size, err := parseSize(val)
+			if err != nil {
+				return nil, err
+			}
+			m.Size = size
+		case "symmetry":
+			s, err := ParseSymmetry(val)
+			if err != nil {
+				return nil, err
+			}
+			m.Symmetry = s
+		case "hazard_damage", "heal_amount":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("bad %s %q: %v", key, val, err)
+			}
+			if key == "hazard_damage" {
+				m.Terrain.HazardDamage = &n
+			} else {
+				m.Terrain.HealAmount = &n
+			}
+		case "cover_multiplier":
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad %s %q: %v", key, val, err)
+			}
+			m.Terrain.CoverMultiplier = &f
+		default:
+			return nil, fmt.Errorf("unknown header %q", key)
+		}
+	}
+	for sc.Scan() {
+		if line := strings.TrimRight(sc.Text(), " \t\r"); line != "" {
+			rows = append(rows, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := m.setRows(rows); err != nil {
+		return nil, err
+	}
+	return &m, m.Validate()
+}
+
+func parseSize(s string) (Loc, error) {
+	parts := strings.Split(s, "x")
+	if len(parts) != 2 {
+		return Loc{}, fmt.Errorf("bad size %q, want WIDTHxHEIGHT", s)
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return Loc{}, fmt.Errorf("bad width in size %q: %v", s, err)
+	}
+	h, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Loc{}, fmt.Errorf("bad height in size %q: %v", s, err)
+	}
+	return Loc{X: w, Y: h}, nil
+}
+
+type jsonMap struct {
+	Name     string   `json:"name"`
+	Width    int      `json:"width"`
+	Height   int      `json:"height"`
+	Symmetry string   `json:"symmetry"`
+	Rows     []string `json:"rows"`
+
+	HazardDamage    *int     `json:"hazard_damage"`
+	CoverMultiplier *float64 `json:"cover_multiplier"`
+	HealAmount      *int     `json:"heal_amount"`
+}
+
+// ParseMapJSON reads a map in the JSON format, and checks that it's playable.
+func ParseMapJSON(r io.Reader) (*Map, error) {
+	var jm jsonMap
+	if err := json.NewDecoder(r).Decode(&jm); err != nil {
+		return nil, err
+	}
+	m := Map{
+		Name: jm.Name,
+		Size: Loc{X: jm.Width, Y: jm.Height},
+		Terrain: Terrain{
+			HazardDamage:    jm.HazardDamage,
+			CoverMultiplier: jm.CoverMultiplier,
+			HealAmount:      jm.HealAmount,
+		},
+	}
+	if jm.Symmetry != "" {
+		s, err := ParseSymmetry(jm.Symmetry)
+		if err != nil {
+			return nil, err
+		}
+		m.Symmetry = s
+	}
+	if err := m.setRows(jm.Rows); err != nil {
+		return nil, err
+	}
+	return &m, m.Validate()
+}
+
+// setRows fills in the cells of the map from its rows, which have to match the
+// size of the map.
+func (m *Map) setRows(rows []string) error {
+	if m.Size.X <= 0 || m.Size.Y <= 0 {
+		return fmt.Errorf("bad size %dx%d", m.Size.X, m.Size.Y)
+	}
+	if len(rows) != m.Size.Y {
+		return fmt.Errorf("map has %d rows, want %d", len(rows), m.Size.Y)
+	}
+	m.cells = make([][]CellType, m.Size.X)
+	for x := range m.cells {
+		m.cells[x] = make([]CellType, m.Size.Y)
+	}
+	for y, row := range rows {
+		if n := len([]rune(row)); n != m.Size.X {
+			return fmt.Errorf("row %d has %d cells, want %d", y+1, n, m.Size.X)
+		}
+		for x, c := range []rune(row) {
+			t, ok := cellChars[c]
+			if !ok {
+				return fmt.Errorf("row %d has unknown cell %q", y+1, c)
+			}
+			m.cells[x][y] = t
+		}
+	}
+	return nil
+}
+
+// Validate checks that the map is fair and playable: it has to have a name and
+// a symmetry, its terrain numbers can't be negative, all the players' parts have to mirror each other, and every spawn
+// point has to be able to reach the other players' spawns.
+func (m *Map) Validate() error {
+	if m.Name == "" {
+		return errors.New("map has no name")
+	}
+	if m.Symmetry == UnknownSymmetry {
+		return fmt.Errorf("map %q has no symmetry", m.Name)
+	}
+
+	if (m.Symmetry == Diagonal || m.Symmetry == Quartered) && m.Size.X != m.Size.Y {
+		return fmt.Errorf("map %q is %s, but isn't square", m.Name, m.Symmetry)
+	}
+	if err := m.Terrain.check(); err != nil {
+		return fmt.Errorf("map %q has %v", m.Name, err)
+	}
+
+	var spawns, mirrored []Loc
+	for x := 0; x < m.Size.X; x++ {
+		for y := 0; y < m.Size.Y; y++ {
+			loc := Loc{x, y}
+			mloc := m.Symmetry.mirror(m.Size, loc)
+			if m.cells[x][y] != m.cells[mloc.X][mloc.Y] {
+				return fmt.Errorf("map %q isn't %s symmetric: %s doesn't match %s", m.Name, m.Symmetry, loc, mloc)
+			}
+			if m.cells[x][y] != Spawn {
+				continue
+			}
+			// Spawns that mirror to themselves would belong to more than one
+			// player.
+			if mloc == loc {
+				return fmt.Errorf("map %q has a spawn point on its line of symmetry at %s", m.Name, loc)
+			}
+			if m.Symmetry.firstHalf(m.Size, loc) {
+				spawns = append(spawns, loc)
+				mirrored = append(mirrored, m.Symmetry.images(m.Size, loc)[1:]...)
+			}
+		}
+	}
+	if len(spawns) == 0 {
+		return fmt.Errorf("map %q has no spawn points", m.Name)
+	}
+
+	reached := m.reachable(mirrored)
+	for _, loc := range spawns {
+		if !reached[loc] {
+			return fmt.Errorf("map %q: spawn point %s can't reach the other players", m.Name, loc)
+		}
+	}
+	return nil
+}
+
+// reachable returns every location a robot starting from one of the given
+// locations could walk to.
+func (m *Map) reachable(from []Loc) map[Loc]bool {
+	seen := make(map[Loc]bool)
+	queue := append([]Loc(nil), from...)
+	for _, loc := range from {
+		seen[loc] = true
+	}
+	for len(queue) > 0 {
+		loc := queue[0]
+		queue = queue[1:]
+		for _, next := range m.neighbors(loc) {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// LoadMap reads a map file, which is in the JSON format if its name ends in
+// .json, and the text format otherwise.
+func LoadMap(path string) (*Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var m *Map
+	if filepath.Ext(path) == ".json" {
+		m, err = ParseMapJSON(f)
+	} else {
+		m, err = ParseMap(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return m, nil
+}
+
+// LoadMaps reads every map file in a directory, and returns them by name.
+func LoadMaps(dir string) (map[string]*Map, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	maps := make(map[string]*Map)
+	for _, fi := range fis {
+		if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		m, err := LoadMap(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := maps[m.Name]; ok {
+			return nil, fmt.Errorf("%s: there's already a map named %q", path, m.Name)
+		}
+		maps[m.Name] = m
+	}
+	return maps, nil
+}
@@ -0,0 +1,253 @@
+package engine
+
+import "github.com/bcspragu/Gobots/botapi"
+
+// EndReason is why a game ended.
+type EndReason int
+
+const (
+	NotEnded    EndReason = iota
+	RoundLimit            // Every round was played
+	Elimination           // A side had no robots left
+	Forfeit               // Every other side forfeited
+)
+
+var endReasonNames = map[EndReason]string{
+	NotEnded:    "not ended",
+	RoundLimit:  "round limit",
+	Elimination: "elimination",
+	Forfeit:     "forfeit",
+}
+
+func (r EndReason) String() string {
+	return endReasonNames[r]
+}
+
+// endReasonToWire converts why a game ended to the wire representation. A game
+// that's sent before it ended was aborted.
+var endReasonToWire = map[EndReason]botapi.EndReason{
+	NotEnded:    botapi.EndReason_aborted,
+	RoundLimit:  botapi.EndReason_roundLimit,
+	Elimination: botapi.EndReason_elimination,
+	Forfeit:     botapi.EndReason_forfeit,
+}
+
+// Tiebreak is a way of deciding a game where both sides end up with the same
+// number of robots.
+type Tiebreak int
+
+const (
+	NoTiebreak  Tiebreak = iota
+	TotalHealth          // The side with the most health left wins
+	DamageDealt          // The side that did the most damage to the others wins
+)
+
+var (
+	tiebreakNames = map[Tiebreak]string{
+		NoTiebreak:  "none",
+		TotalHealth: "total health",
+		DamageDealt: "damage dealt",
+	}
+
+	tiebreakToWire = map[Tiebreak]botapi.Tiebreak{
+		NoTiebreak:  botapi.Tiebreak_none,
+		TotalHealth: botapi.Tiebreak_totalHealth,
+		DamageDealt: botapi.Tiebreak_damageDealt,
+	}
+
+	tiebreakFromWire = map[botapi.Tiebreak]Tiebreak{
+		botapi.Tiebreak_none:        NoTiebreak,
+		botapi.Tiebreak_totalHealth: TotalHealth,
+		botapi.Tiebreak_damageDealt: DamageDealt,
+	}
+)
+
+func (t Tiebreak) String() string {
+	return tiebreakNames[t]
+}
+
+// Result is how a finished game turned out. The maps are keyed by faction, and
+// the winner is picked from the totals for each side.
+type Result struct {
+	Reason EndReason
+
+	// Winner is the side that won, or 0 for a tie. It's a team in team games,
+	// and a faction otherwise.
+	Winner int
+	// Tiebreak is the tiebreak that decided the winner, or NoTiebreak if the
+	// winner had more robots, or if it's a tie.
+	Tiebreak Tiebreak
+
+	// Robots is how many robots each faction has left, which is its score.
+	Robots map[int]int
+	// Health is the total health of each faction's robots.
+	Health map[int]int
+	// Damage is how much damage each faction did to other sides' robots.
+	Damage map[int]int
+}
+
+// Result returns how the game turned out. It can be called before the game is
+// finished, in which case the Reason is NotEnded.
+func (b *Board) Result() Result {
+	res := Result{
+		Robots: make(map[int]int),
+		Health: make(map[int]int),
+		Damage: make(map[int]int),
+	}
+	switch {
+	case b.forfeited():
+		res.Reason = Forfeit
+	case b.eliminated:
+		res.Reason = Elimination
+	case b.Round >= b.Rules.MaxRounds:
+		res.Reason = RoundLimit
+	}
+
+	for _, r := range b.Locs {
+		res.Robots[r.Faction]++
+		res.Health[r.Faction] += r.Health
+	}
+	for f, dmg := range b.DamageDealt {
+		res.Damage[f] = dmg
+	}
+
+	if res.Reason == Forfeit {
+		res.Winner = b.lastStanding()
+		return res
+	}
+	res.Winner = b.leader(res.Robots)
+	for _, t := range b.Rules.Tiebreaks {
+		if res.Winner != 0 {
+			break
+		}
+		switch t {
+		case TotalHealth:
+			res.Winner = b.leader(res.Health)
+		case DamageDealt:
+			res.Winner = b.leader(res.Damage)
+		default:
+			continue
+		}
+		if res.Winner != 0 {
+			res.Tiebreak = t
+		}
+	}
+	return res
+}
+
+// leader returns the side with the highest total of its factions' scores, or 0
+// if more than one side has it.
+func (b *Board) leader(scores map[int]int) int {
+	totals := make(map[int]int)
+	for f := 1; f <= b.Factions; f++ {
+		totals[b.Team(f)] += scores[f]
+	}
+	best, tied := 1, false
+	for s := 2; s <= b.Sides(); s++ {
+		switch {
+		case totals[s] > totals[best]:
+			best, tied = s, false
+		case totals[s] == totals[best]:
+			tied = true
+		}
+	}
+	if tied {
+		return 0
+	}
+	return best
+}
+
+// checkElimination ends the game if the rules say to and at most one side has
+// any robots left.
+func (b *Board) checkElimination() {
+	if !b.Rules.EndOnElimination {
+		return
+	}
+	alive := make(map[int]bool)
+	for _, r := range b.Locs {
+		alive[b.Team(r.Faction)] = true
+	}
+	b.eliminated = len(alive) <= 1
+}
+
+// Forfeit takes a faction out of the game, say because its bot stopped
+// answering, and returns the events for its robots being removed from the
+// board. No more robots spawn for it, and once only one side has a faction
+// that hasn't forfeited, the game is over and that side wins.
+func (b *Board) Forfeit(faction int) []Event {
+	if b.Forfeited == nil {
+		b.Forfeited = make(map[int]bool)
+	}
+	b.Forfeited[faction] = true
+
+	b.events = nil
+	for _, loc := range b.sortedLocs() {
+		if r := b.at(loc); r.Faction == faction {
+			b.emit(Event{Type: Died, Robot: r.ID, Loc: loc})
+			b.remove(loc)
+		}
+	}
+	evs := b.events
+	b.events = nil
+	return evs
+}
+
+// forfeited reports whether the game is over because all but one side (or
+// every side) forfeited.
+func (b *Board) forfeited() bool {
+	if len(b.Forfeited) == 0 {
+		return false
+	}
+	left := make(map[int]bool)
+	for f := 1; f <= b.Factions; f++ {
+		if !b.Forfeited[f] {
+			left[b.Team(f)] = true
+		}
+	}
+	return len(left) <= 1
+}
+
+// lastStanding returns the side with a faction that hasn't forfeited, or 0 if
+// there isn't exactly one.
+func (b *Board) lastStanding() int {
+	side := 0
+	for f := 1; f <= b.Factions; f++ {
+		if b.Forfeited[f] {
+			continue
+		}
+		if side != 0 && b.Team(f) != side {
+			return 0
+		}
+		side = b.Team(f)
+	}
+	return side
+}
+
+// ResultToWire converts how the game turned out, along with the board as
+// faction sees it, to the wire representation sent to bots when a game ends. A
+// game that isn't finished is sent as aborted.
+func (b *Board) ResultToWire(out botapi.GameResult, faction int) error {
+	ib, err := out.NewBoard()
+	if err != nil {
+		return err
+	}
+	if err := b.ToWireWithInitial(ib, faction); err != nil {
+		return err
+	}
+
+	res := b.Result()
+	if res.Reason == NotEnded {
+		res.Winner, res.Tiebreak = 0, NoTiebreak
+	}
+	out.SetReason(endReasonToWire[res.Reason])
+	out.SetWinner(uint8(res.Winner))
+	out.SetTiebreak(tiebreakToWire[res.Tiebreak])
+	scores, err := out.NewScores(int32(b.Factions))
+	if err != nil {
+		return err
+	}
+	for f := 1; f <= b.Factions; f++ {
+		scores.Set(f-1, int32(res.Robots[f]))
+	}
+	return nil
+}
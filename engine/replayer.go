@@ -1,12 +1,22 @@
 package engine
 
 import (
+	"fmt"
+
 	"github.com/bcspragu/Gobots/botapi"
 	"github.com/gopherjs/gopherjs/js"
 )
 
 type Playback struct {
 	Boards []*Board
+	// Events holds what happened in the round leading up to each board, so the
+	// first entry is always empty.
+	Events [][]Event
+	// Latencies holds how long each player took to send its turns for the round
+	// leading up to each board, in milliseconds, and TimedOut whether it ran out
+	// of time. They're indexed like Events, then by player.
+	Latencies [][]int
+	TimedOut  [][]bool
 }
 
 func (p *Playback) Board(i int) *js.Object {
@@ -21,14 +31,125 @@ func (p *Playback) BoardsJS() []*Board {
 	return p.Boards
 }
 
+// Log describes what happened in the round leading up to board i.
+func (p *Playback) Log(i int) []string {
+	if i < 0 || i >= len(p.Events) {
+		return nil
+	}
+	log := make([]string, len(p.Events[i]))
+	for j, e := range p.Events[i] {
+		log[j] = e.String()
+	}
+	return log
+}
+
+// EffectAt describes the action that landed on a cell in the round leading up
+// to board i, for the viewer to draw: "attack", "ranged" or "heal", or the
+// empty string if nothing did.
+func (p *Playback) EffectAt(i, x, y int) string {
+	if i < 0 || i >= len(p.Events) {
+		return ""
+	}
+	loc := Loc{X: x, Y: y}
+	for _, e := range p.Events[i] {
+		switch {
+		case e.Type == Attacked && e.Target == loc:
+			return "attack"
+		case e.Type == RangedAttacked && e.Target == loc:
+			return "ranged"
+		case e.Type == Healed && e.Other != 0 && e.Loc == loc:
+			return "heal"
+		}
+	}
+	return ""
+}
+
+// Timing describes how long each player took to send its turns in the round
+// leading up to board i, in player order, or returns nil if the replay doesn't
+// say.
+func (p *Playback) Timing(i int) []string {
+	if i < 0 || i >= len(p.Latencies) {
+		return nil
+	}
+	timing := make([]string, len(p.Latencies[i]))
+	for j, ms := range p.Latencies[i] {
+		if j < len(p.TimedOut[i]) && p.TimedOut[i][j] {
+			timing[j] = fmt.Sprintf("timed out after %dms", ms)
+		} else {
+			timing[j] = fmt.Sprintf("%dms", ms)
+		}
+	}
+	return timing
+}
+
 func NewPlayback(r botapi.Replay) (*Playback, error) {
-	if bs, err := boards(r); err == nil {
-		return &Playback{
-			Boards: bs,
-		}, nil
-	} else {
+	bs, err := boards(r)
+	if err != nil {
 		return nil, err
 	}
+	evs, err := events(r)
+	if err != nil {
+		return nil, err
+	}
+	lats, outs, err := timings(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Playback{
+		Boards:    bs,
+		Events:    evs,
+		Latencies: lats,
+		TimedOut:  outs,
+	}, nil
+}
+
+func timings(replay botapi.Replay) ([][]int, [][]bool, error) {
+	rs, err := replay.Rounds()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lats := make([][]int, rs.Len()+1)
+	outs := make([][]bool, rs.Len()+1)
+	for i := 0; i < rs.Len(); i++ {
+		wls, err := rs.At(i).Latencies()
+		if err != nil {
+			return nil, nil, err
+		}
+		wos, err := rs.At(i).TimedOut()
+		if err != nil {
+			return nil, nil, err
+		}
+		lats[i+1] = make([]int, wls.Len())
+		for j := range lats[i+1] {
+			lats[i+1][j] = int(wls.At(j))
+		}
+		outs[i+1] = make([]bool, wos.Len())
+		for j := range outs[i+1] {
+			outs[i+1][j] = wos.At(j)
+		}
+	}
+	return lats, outs, nil
+}
+
+func events(replay botapi.Replay) ([][]Event, error) {
+	rs, err := replay.Rounds()
+	if err != nil {
+		return nil, err
+	}
+
+	evs := make([][]Event, rs.Len()+1)
+	for i := 0; i < rs.Len(); i++ {
+		wes, err := rs.At(i).Events()
+		if err != nil {
+			return nil, err
+		}
+		evs[i+1] = make([]Event, wes.Len())
+		for j := range evs[i+1] {
+			evs[i+1][j] = EventFromWire(wes.At(j))
+		}
+	}
+	return evs, nil
 }
 
 func boards(replay botapi.Replay) ([]*Board, error) {
@@ -42,6 +163,7 @@ func boards(replay botapi.Replay) ([]*Board, error) {
 		return nil, err
 	}
 	cells = ib.Cells
+	rules := ib.Rules
 
 	// After 0
 	rs, err := replay.Rounds()
@@ -61,6 +183,9 @@ func boards(replay botapi.Replay) ([]*Board, error) {
 			return nil, err
 		}
 		b.Cells = cells
+		b.Rules = rules
+		b.Factions = ib.Factions
+		b.Teams = ib.Teams
 		bs[i+1] = b
 	}
 	return bs, nil
@@ -84,7 +209,7 @@ func boardFromWire(wire botapi.Board) (*Board, error) {
 			X: int(bot.X()),
 			Y: int(bot.Y()),
 		}
-		b.Locs[loc] = robotFromWire(bot)
+		b.Set(loc, robotFromWire(bot))
 	}
 
 	return b, nil
@@ -114,7 +239,28 @@ func boardFromWireWithInitial(wire botapi.InitialBoard) (*Board, error) {
 			X: int(bot.X()),
 			Y: int(bot.Y()),
 		}
-		b.Locs[loc] = robotFromWire(bot)
+		b.Set(loc, robotFromWire(bot))
+	}
+
+	rules, err := wire.Rules()
+	if err != nil {
+		return b, err
+	}
+	b.Rules = RulesFromWire(rules)
+	b.Symmetry = symmetryFromWire[wire.Symmetry()]
+	b.Factions = int(wire.Players())
+	b.Teams = int(wire.Teams())
+
+	spawnRounds, err := wire.SpawnRounds()
+	if err != nil {
+		return b, err
+	}
+	if spawnRounds.Len() > 0 {
+		rs := make(SpawnRounds, spawnRounds.Len())
+		for i := range rs {
+			rs[i] = int(spawnRounds.At(i))
+		}
+		b.schedule = rs
 	}
 
 	cells, err := wire.Cells()
@@ -132,16 +278,22 @@ func boardFromWireWithInitial(wire botapi.InitialBoard) (*Board, error) {
 }
 
 func robotFromWire(wire botapi.Robot) *Robot {
-	var faction int
-	if wire.Faction() == botapi.Faction_mine {
-		faction = P1Faction
-	} else {
+	// Replays are recorded by a Spectator, so they have every robot and who owns
+	// it. Ones from before there could be more than two players were recorded
+	// from the first player's point of view, and don't say who owns what.
+	faction := int(wire.Player())
+	if faction == 0 {
 		faction = P2Faction
+		if wire.Faction() == botapi.Faction_mine {
+			faction = P1Faction
+		}
 	}
 
 	return &Robot{
 		ID:      RobotID(wire.Id()),
 		Health:  int(wire.Health()),
 		Faction: faction,
+
+		Entering: enteringFromWire(Loc{X: int(wire.X()), Y: int(wire.Y())}, wire.Entering()),
 	}
 }
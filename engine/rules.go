@@ -0,0 +1,294 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/bcspragu/Gobots/botapi"
+)
+
+// RuleSet holds the numbers that decide how a game plays out, so variants (short
+// games, high-damage games) can be played without changing the engine.
+type RuleSet struct {
+	InitialHealth   int
+	CollisionDamage int
+	AttackDamage    int
+	DestructDamage  int
+
+	// GuardMultiplier scales the attack and self-destruct damage done to a
+	// robot that's guarding. Guarding robots never take collision damage.
+	GuardMultiplier float64
+
+	// SpawnEvery is the number of rounds between spawns, and no robots spawn on
+	// or after round SpawnUntil. A SpawnEvery of zero only spawns robots at the
+	// start of the game.
+	SpawnEvery int
+	SpawnUntil int
+
+	// MaxRounds is the number of rounds in a game.
+	MaxRounds int
+
+	// FriendlyFire is whether attacks and self-destructs hurt robots on the same
+	// side.
+	FriendlyFire bool
+
+	// IllegalPenalty is what happens to robots that try to move off the board or
+	// into a wall, or attack a wall. IllegalDamage is how much they get hurt for
+	// it under DamagePenalty.
+	IllegalPenalty Penalty
+	IllegalDamage  int
+
+	// EndOnElimination ends the game as soon as a side has no robots left at the
+	// end of a round, instead of playing out the rest of the rounds.
+	EndOnElimination bool
+
+	// Tiebreaks decide games where both sides end up with the same number of
+	// robots. They're tried in order until one picks a winner.
+	Tiebreaks [2]Tiebreak
+
+	// VisionRadius is how far each side can see from its robots, measured with
+	// VisionMetric. Opponents' robots out of sight aren't sent to bots. Zero
+	// means the whole board can be seen.
+	VisionRadius int
+	VisionMetric Metric
+
+	// The numbers for terrain cells: the damage done to robots on hazards each
+	// round, how much attacks on robots in cover are scaled by, and how much
+	// robots on healing cells get back each round. See HazardCell and the other
+	// terrain CellTypes.
+	HazardDamage    int
+	CoverMultiplier float64
+	HealAmount      int
+
+	// AllyHeal is how much a heal action gives back to the robot next to the
+	// healer, and RangedDamage is how much a ranged attack two cells away does.
+	// Robots can only take those actions if these are more than zero, which
+	// they aren't in the DefaultRules.
+	AllyHeal     int
+	RangedDamage int
+
+	// SpawnCamping is what happens to robots standing on spawn cells when robots
+	// spawn, and CampingDamage is how much they get hurt under DamageCampers.
+	SpawnCamping  Camping
+	CampingDamage int
+}
+
+// Penalty is what happens to a robot that tries an illegal action. Whatever
+// the penalty, the robot stays where it is, and the action is counted in the
+// board's Illegal tally.
+type Penalty int
+
+const (
+	NoPenalty     Penalty = iota // The action is quietly turned into a wait
+	CountPenalty                 // The action also shows up in the replay
+	DamagePenalty                // The action shows up, and hurts the robot
+)
+
+func (p Penalty) String() string {
+	return penaltyToWire[p].String()
+}
+
+var (
+	penaltyToWire = map[Penalty]botapi.Penalty{
+		NoPenalty:     botapi.Penalty_none,
+		CountPenalty:  botapi.Penalty_count,
+		DamagePenalty: botapi.Penalty_damage,
+	}
+
+	penaltyFromWire = map[botapi.Penalty]Penalty{
+		botapi.Penalty_none:   NoPenalty,
+		botapi.Penalty_count:  CountPenalty,
+		botapi.Penalty_damage: DamagePenalty,
+	}
+)
+
+// Camping is what happens to robots standing on spawn cells when it's time for
+// robots to spawn.
+type Camping int
+
+const (
+	KillCampers   Camping = iota // Every spawn cell is cleared, destroying the robots on them
+	SkipOccupied                 // Robots are left alone, and nothing spawns under them
+	DamageCampers                // Robots are hurt, and nothing spawns under the ones that survive
+)
+
+func (c Camping) String() string {
+	return campingToWire[c].String()
+}
+
+// ParseCamping returns the spawn camping with the given name: kill, skip, or
+// damage.
+func ParseCamping(name string) (Camping, error) {
+	for c := range campingToWire {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+	return KillCampers, fmt.Errorf("unknown spawn camping %q", name)
+}
+
+var (
+	campingToWire = map[Camping]botapi.Camping{
+		KillCampers:   botapi.Camping_kill,
+		SkipOccupied:  botapi.Camping_skip,
+		DamageCampers: botapi.Camping_damage,
+	}
+
+	campingFromWire = map[botapi.Camping]Camping{
+		botapi.Camping_kill:   KillCampers,
+		botapi.Camping_skip:   SkipOccupied,
+		botapi.Camping_damage: DamageCampers,
+	}
+)
+
+// DefaultRules are the standard rules, which are nearly identical to RobotGame.
+var DefaultRules = RuleSet{
+	InitialHealth:   50,
+	CollisionDamage: 5,
+	AttackDamage:    10,
+	DestructDamage:  15,
+	GuardMultiplier: 0.5,
+	SpawnEvery:      10,
+	SpawnUntil:      100,
+	MaxRounds:       100,
+	FriendlyFire:    true,
+	IllegalPenalty:  CountPenalty,
+	IllegalDamage:   5,
+
+	EndOnElimination: true,
+	Tiebreaks:        [2]Tiebreak{TotalHealth, DamageDealt},
+
+	HazardDamage:    5,
+	CoverMultiplier: 0.5,
+	HealAmount:      5,
+
+	CampingDamage: 10,
+}
+
+// Check makes sure the rules make sense to play with: none of the amounts that
+// can be turned off with zero are negative, and a ranged attack does less
+// damage than one from right next to the target.
+func (rs RuleSet) Check() error {
+	for _, n := range []struct {
+		name string
+		val  int
+	}{
+		{"vision radius", rs.VisionRadius},
+		{"ally heal", rs.AllyHeal},
+		{"ranged damage", rs.RangedDamage},
+		{"camping damage", rs.CampingDamage},
+	} {
+		if n.val < 0 {
+			return fmt.Errorf("engine: negative %s %d", n.name, n.val)
+		}
+	}
+	if rs.RangedDamage > 0 && rs.RangedDamage >= rs.AttackDamage {
+		return fmt.Errorf("engine: ranged damage %d isn't less than attack damage %d", rs.RangedDamage, rs.AttackDamage)
+	}
+	return nil
+}
+
+func (rs RuleSet) damage(dt DamageType) int {
+	switch dt {
+	case Collision:
+		return rs.CollisionDamage
+	case Attack:
+		return rs.AttackDamage
+	case Destruct:
+		return rs.DestructDamage
+	case Penalized:
+		if rs.IllegalPenalty == DamagePenalty {
+			return rs.IllegalDamage
+		}
+	case Hazard:
+		return rs.HazardDamage
+	case Ranged:
+		return rs.RangedDamage
+	case Camped:
+		return rs.CampingDamage
+	}
+	return 0
+}
+
+// guarded returns the damage done to a guarding robot.
+func (rs RuleSet) guarded(dmg int) int {
+	return int(float64(dmg) * rs.GuardMultiplier)
+}
+
+// allows reports whether the rules let robots take the given kind of turn.
+func (rs RuleSet) allows(kind botapi.Turn_Which) bool {
+	switch kind {
+	case botapi.Turn_Which_heal:
+		return rs.AllyHeal > 0
+	case botapi.Turn_Which_rangedAttack:
+		return rs.RangedDamage > 0
+	}
+	return true
+}
+
+// covered returns the attack damage done to a robot in cover.
+func (rs RuleSet) covered(dmg int) int {
+	return int(float64(dmg) * rs.CoverMultiplier)
+}
+
+// ToWire converts the rule set to the wire representation.
+func (rs RuleSet) ToWire(out botapi.RuleSet) {
+	out.SetInitialHealth(int32(rs.InitialHealth))
+	out.SetCollisionDamage(int32(rs.CollisionDamage))
+	out.SetAttackDamage(int32(rs.AttackDamage))
+	out.SetDestructDamage(int32(rs.DestructDamage))
+	out.SetGuardMultiplier(rs.GuardMultiplier)
+	out.SetSpawnEvery(int32(rs.SpawnEvery))
+	out.SetSpawnUntil(int32(rs.SpawnUntil))
+	out.SetMaxRounds(int32(rs.MaxRounds))
+	out.SetFriendlyFire(rs.FriendlyFire)
+	out.SetIllegalPenalty(penaltyToWire[rs.IllegalPenalty])
+	out.SetIllegalDamage(int32(rs.IllegalDamage))
+	out.SetEndOnElimination(rs.EndOnElimination)
+	out.SetFirstTiebreak(tiebreakToWire[rs.Tiebreaks[0]])
+	out.SetSecondTiebreak(tiebreakToWire[rs.Tiebreaks[1]])
+	out.SetVisionRadius(int32(rs.VisionRadius))
+	out.SetVisionMetric(metricToWire[rs.VisionMetric])
+	out.SetHazardDamage(int32(rs.HazardDamage))
+	out.SetCoverMultiplier(rs.CoverMultiplier)
+	out.SetHealAmount(int32(rs.HealAmount))
+	out.SetAllyHeal(int32(rs.AllyHeal))
+	out.SetRangedDamage(int32(rs.RangedDamage))
+	out.SetSpawnCamping(campingToWire[rs.SpawnCamping])
+	out.SetCampingDamage(int32(rs.CampingDamage))
+}
+
+// RulesFromWire converts the wire representation of a rule set. Messages
+// without a rule set get the DefaultRules, except that they don't end on
+// elimination or break ties, since they're from before games could.
+func RulesFromWire(wire botapi.RuleSet) RuleSet {
+	return RuleSet{
+		InitialHealth:   int(wire.InitialHealth()),
+		CollisionDamage: int(wire.CollisionDamage()),
+		AttackDamage:    int(wire.AttackDamage()),
+		DestructDamage:  int(wire.DestructDamage()),
+		GuardMultiplier: wire.GuardMultiplier(),
+		SpawnEvery:      int(wire.SpawnEvery()),
+		SpawnUntil:      int(wire.SpawnUntil()),
+		MaxRounds:       int(wire.MaxRounds()),
+		FriendlyFire:    wire.FriendlyFire(),
+		IllegalPenalty:  penaltyFromWire[wire.IllegalPenalty()],
+		IllegalDamage:   int(wire.IllegalDamage()),
+
+		EndOnElimination: wire.EndOnElimination(),
+		Tiebreaks: [2]Tiebreak{
+			tiebreakFromWire[wire.FirstTiebreak()],
+			tiebreakFromWire[wire.SecondTiebreak()],
+		},
+		VisionRadius: int(wire.VisionRadius()),
+		VisionMetric: metricFromWire[wire.VisionMetric()],
+
+		HazardDamage:    int(wire.HazardDamage()),
+		CoverMultiplier: wire.CoverMultiplier(),
+		HealAmount:      int(wire.HealAmount()),
+		AllyHeal:        int(wire.AllyHeal()),
+		RangedDamage:    int(wire.RangedDamage()),
+
+		SpawnCamping:  campingFromWire[wire.SpawnCamping()],
+		CampingDamage: int(wire.CampingDamage()),
+	}
+}
@@ -0,0 +1,28 @@
+package engine
+
+// source is the random source boards draw from. Unlike the sources in
+// math/rand, its state can be copied, which lets a cloned board spawn robots
+// exactly like the original would have. It's an implementation of SplitMix64.
+type source struct {
+	state uint64
+}
+
+func newSource(seed int64) *source {
+	return &source{state: uint64(seed)}
+}
+
+func (s *source) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
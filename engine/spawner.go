@@ -1,12 +1,31 @@
 package engine
 
-import "math/rand"
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
 
 type Spawner interface {
 	// Given a list of possible spawn locations for the first player, return a
 	// list of locations to spawn players. The program will automatically
-	// mirror the spawn locations for the other faction.
-	Spawn(locs []Loc) []Loc
+	// mirror the spawn locations for the other faction. Any randomness should be
+	// drawn from r, which is seeded from the board's config, so that matches can
+	// be reproduced. b is the board as it is when robots spawn, and b.Round is
+	// the round that just ended, or 0 for the robots the game starts with. It
+	// shouldn't be changed.
+	Spawn(r *rand.Rand, b *Board, locs []Loc) []Loc
+}
+
+// A FactionSpawner is a Spawner that can give some factions more robots than
+// others. After Spawn picks the locations everyone spawns at, SpawnMore is
+// called for each faction in order with the first player's spawn locations that
+// weren't picked, and the locations it returns are mirrored to that faction's
+// part of the board only.
+type FactionSpawner interface {
+	Spawner
+	SpawnMore(r *rand.Rand, b *Board, faction int, locs []Loc) []Loc
 }
 
 type SpawnType int
@@ -18,11 +37,11 @@ var (
 
 type allSpawn struct{}
 
-func (allSpawn) Spawn(locs []Loc) []Loc { return locs }
+func (allSpawn) Spawn(_ *rand.Rand, _ *Board, locs []Loc) []Loc { return locs }
 
 type everyOtherSpawn struct{}
 
-func (everyOtherSpawn) Spawn(locs []Loc) (res []Loc) {
+func (everyOtherSpawn) Spawn(_ *rand.Rand, _ *Board, locs []Loc) (res []Loc) {
 	i := 0
 	for _, loc := range locs {
 		if i%2 == 0 {
@@ -44,11 +63,106 @@ type randomSpawn struct {
 	n int
 }
 
-func (r *randomSpawn) Spawn(locs []Loc) (res []Loc) {
+func (rs *randomSpawn) Spawn(r *rand.Rand, _ *Board, locs []Loc) (res []Loc) {
 	for _, loc := range locs {
-		if rand.Intn(r.n) == 0 {
+		if r.Intn(rs.n) == 0 {
 			res = append(res, loc)
 		}
 	}
 	return
 }
+
+// NewCatchUpSpawn returns a FactionSpawner that spawns like s, and gives every
+// faction on a side with fewer robots than the side with the most up to n more
+// robots, so the losing side has a chance to come back.
+func NewCatchUpSpawn(s Spawner, n int) FactionSpawner {
+	return &catchUpSpawn{s: s, n: n}
+}
+
+type catchUpSpawn struct {
+	s Spawner
+	n int
+}
+
+func (cs *catchUpSpawn) Spawn(r *rand.Rand, b *Board, locs []Loc) []Loc {
+	return cs.s.Spawn(r, b, locs)
+}
+
+func (cs *catchUpSpawn) SpawnMore(r *rand.Rand, b *Board, faction int, locs []Loc) []Loc {
+	most := 0
+	for side := 1; side <= b.Sides(); side++ {
+		if n := b.TeamCount(side); n > most {
+			most = n
+		}
+	}
+	if b.TeamCount(b.Team(faction)) == most {
+		return nil
+	}
+	res := make([]Loc, 0, cs.n)
+	for _, i := range r.Perm(len(locs)) {
+		if len(res) == cs.n {
+			break
+		}
+		res = append(res, locs[i])
+	}
+	return res
+}
+
+// A Schedule decides which rounds robots spawn at the end of. Without one, a
+// board spawns robots every SpawnEvery rounds before SpawnUntil, as set in its
+// rules.
+type Schedule interface {
+	SpawnsAfter(round int) bool
+}
+
+// SpawnRounds is a Schedule that spawns robots at the end of each of the rounds
+// in it.
+type SpawnRounds []int
+
+func (s SpawnRounds) SpawnsAfter(round int) bool {
+	for _, r := range s {
+		if r == round {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSpawnRounds returns the SpawnRounds in a comma-separated list of rounds,
+// like "10,20,35". The game starts with robots anyway, so every round has to be
+// at least 1.
+func ParseSpawnRounds(list string) (SpawnRounds, error) {
+	var s SpawnRounds
+	for _, r := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(r))
+		if err != nil {
+			return nil, fmt.Errorf("bad spawn round %q in %q", r, list)
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("spawn round %d in %q is before the first round ends", n, list)
+		}
+		s = append(s, n)
+	}
+	return s, nil
+}
+
+// SpawnPeriod is a Schedule that spawns robots every Every rounds, and not on or
+// after round Until. An Every of zero never spawns robots.
+type SpawnPeriod struct {
+	Every, Until int
+}
+
+func (s SpawnPeriod) SpawnsAfter(round int) bool {
+	return s.Every > 0 && round%s.Every == 0 && round < s.Until
+}
+
+// rounds returns the rounds that robots spawn at the end of, up to max.
+func rounds(s Schedule, max int) []int {
+	var rs []int
+	for r := 1; r <= max; r++ {
+		if s.SpawnsAfter(r) {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/bcspragu/Gobots/botapi"
+)
+
+// Symmetry is how one player's part of a board mirrors the others'. Spawn
+// points on the first player's part are mirrored to get everyone else's.
+type Symmetry int
+
+const (
+	UnknownSymmetry Symmetry = iota
+	// Horizontal boards mirror left to right, with the first player on the
+	// left.
+	Horizontal
+	// Vertical boards mirror top to bottom, with the first player on the top.
+	Vertical
+	// Rotational boards are the same when turned 180 degrees, with the first
+	// player on the left (and the top of the middle column, if there is one).
+	Rotational
+	// Diagonal boards mirror across the diagonal from the top left corner to
+	// the bottom right one, with the first player below it. They have to be
+	// square.
+	Diagonal
+	// Quartered boards are the same when turned 90 degrees, so they have room
+	// for four players, each a quarter turn clockwise from the last. Games with
+	// two players use opposite quarters. They have to be square.
+	Quartered
+)
+
+var (
+	symmetryNames = map[Symmetry]string{
+		Horizontal: "horizontal",
+		Vertical:   "vertical",
+		Rotational: "rotational",
+		Diagonal:   "diagonal",
+		Quartered:  "quartered",
+	}
+
+	symmetryToWire = map[Symmetry]botapi.Symmetry{
+		Horizontal: botapi.Symmetry_horizontal,
+		Vertical:   botapi.Symmetry_vertical,
+		Rotational: botapi.Symmetry_rotational,
+		Diagonal:   botapi.Symmetry_diagonal,
+		Quartered:  botapi.Symmetry_quartered,
+	}
+
+	symmetryFromWire = map[botapi.Symmetry]Symmetry{
+		botapi.Symmetry_horizontal: Horizontal,
+		botapi.Symmetry_vertical:   Vertical,
+		botapi.Symmetry_rotational: Rotational,
+		botapi.Symmetry_diagonal:   Diagonal,
+		botapi.Symmetry_quartered:  Quartered,
+	}
+)
+
+func (s Symmetry) String() string {
+	if name, ok := symmetryNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseSymmetry returns the symmetry with the given name.
+func ParseSymmetry(name string) (Symmetry, error) {
+	for s, n := range symmetryNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return UnknownSymmetry, fmt.Errorf("unknown symmetry %q", name)
+}
+
+// Players returns the most players a board with the symmetry has room for.
+func (s Symmetry) Players() int {
+	if s == Quartered {
+		return 4
+	}
+	return 2
+}
+
+// mirror returns the location matching loc on the next player's part of a
+// board of the given size.
+func (s Symmetry) mirror(size, loc Loc) Loc {
+	switch s {
+	case Vertical:
+		return Loc{X: loc.X, Y: size.Y - 1 - loc.Y}
+	case Rotational:
+		return Loc{X: size.X - 1 - loc.X, Y: size.Y - 1 - loc.Y}
+	case Diagonal:
+		return Loc{X: loc.Y, Y: loc.X}
+	case Quartered:
+		return Loc{X: size.X - 1 - loc.Y, Y: loc.X}
+	}
+	return Loc{X: size.X - 1 - loc.X, Y: loc.Y}
+}
+
+// images returns the locations matching loc on every player's part of a board
+// of the given size, starting with loc itself, for as many players as the
+// symmetry has room for.
+func (s Symmetry) images(size, loc Loc) []Loc {
+	locs := []Loc{loc}
+	for len(locs) < s.Players() {
+		locs = append(locs, s.mirror(size, locs[len(locs)-1]))
+	}
+	return locs
+}
+
+// seat returns which of the images of a location faction gets in a game with n
+// players, spreading the players out when there are fewer than there's room
+// for.
+func (s Symmetry) seat(faction, n int) int {
+	return (faction - 1) * s.Players() / n
+}
+
+// firstHalf reports whether loc is on the first player's part of a board of the
+// given size. Locations that mirror to themselves aren't on anyone's part.
+func (s Symmetry) firstHalf(size, loc Loc) bool {
+	for _, m := range s.images(size, loc)[1:] {
+		if !(loc.X < m.X || loc.X == m.X && loc.Y < m.Y) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package engine
+
+import "github.com/bcspragu/Gobots/botapi"
+
+// cellAt returns the type of the cell at loc, which has to be on the board.
+func (b *Board) cellAt(loc Loc) CellType {
+	return b.Cells[loc.X][loc.Y]
+}
+
+// slowedBy reports whether the move is the first round of getting into a slow
+// cell, which leaves the robot where it is.
+func (b *Board) slowedBy(m botMove) bool {
+	if m.Turn.Kind != botapi.Turn_Which_move {
+		return false
+	}
+	target := m.target()
+	if !b.isValidLoc(target) || b.cellAt(target) != SlowCell {
+		return false
+	}
+	return m.Bot.Entering == nil || *m.Bot.Entering != target
+}
+
+// applyTerrain hurts the robots on hazards and heals the ones on healing cells,
+// in ID order.
+func (b *Board) applyTerrain() {
+	for _, loc := range b.sortedLocs() {
+		r := b.at(loc)
+		switch b.cellAt(loc) {
+		case HazardCell:
+			b.hurtBot(botMove{Bot: r, Location: loc}, Hazard, nil)
+		case HealingCell:
+			heal := b.Rules.HealAmount
+			if max := b.Rules.InitialHealth - r.Health; heal > max {
+				heal = max
+			}
+			if heal > 0 {
+				r.Health += heal
+				b.emit(Event{Type: Healed, Robot: r.ID, Loc: loc, Damage: heal})
+			}
+		}
+	}
+}
+
+// enteringToWire returns the direction of the slow cell the robot at loc is
+// partway into, or none.
+func enteringToWire(loc Loc, r *Robot) botapi.Direction {
+	if r.Entering == nil {
+		return botapi.Direction_none
+	}
+	for _, d := range []botapi.Direction{botapi.Direction_north, botapi.Direction_south, botapi.Direction_east, botapi.Direction_west} {
+		if xOff, yOff := directionOffsets(d); (Loc{X: loc.X + xOff, Y: loc.Y + yOff}) == *r.Entering {
+			return d
+		}
+	}
+	return botapi.Direction_none
+}
+
+// enteringFromWire returns the slow cell the robot at loc is partway into,
+// given the direction it's in, or nil if it's none.
+func enteringFromWire(loc Loc, d botapi.Direction) *Loc {
+	if d == botapi.Direction_none {
+		return nil
+	}
+	xOff, yOff := directionOffsets(d)
+	return &Loc{X: loc.X + xOff, Y: loc.Y + yOff}
+}
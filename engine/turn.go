@@ -0,0 +1,73 @@
+package engine
+
+import "github.com/bcspragu/Gobots/botapi"
+
+// A Turn is what a single robot does in a round.
+type Turn struct {
+	ID   RobotID
+	Kind botapi.Turn_Which
+	// Direction is where the robot is moving, attacking or healing, for those
+	// kinds of turns.
+	Direction botapi.Direction
+}
+
+// TurnFromWire converts the wire representation of a turn.
+func TurnFromWire(wire botapi.Turn) Turn {
+	t := Turn{
+		ID:   RobotID(wire.Id()),
+		Kind: wire.Which(),
+	}
+	switch t.Kind {
+	case botapi.Turn_Which_move:
+		t.Direction = wire.Move()
+	case botapi.Turn_Which_attack:
+		t.Direction = wire.Attack()
+	case botapi.Turn_Which_heal:
+		t.Direction = wire.Heal()
+	case botapi.Turn_Which_rangedAttack:
+		t.Direction = wire.RangedAttack()
+	}
+	return t
+}
+
+// ToWire converts the turn to the wire representation.
+func (t Turn) ToWire(out botapi.Turn) {
+	out.SetId(uint32(t.ID))
+	switch t.Kind {
+	case botapi.Turn_Which_move:
+		out.SetMove(t.Direction)
+	case botapi.Turn_Which_attack:
+		out.SetAttack(t.Direction)
+	case botapi.Turn_Which_selfDestruct:
+		out.SetSelfDestruct()
+	case botapi.Turn_Which_guard:
+		out.SetGuard()
+	case botapi.Turn_Which_heal:
+		out.SetHeal(t.Direction)
+	case botapi.Turn_Which_rangedAttack:
+		out.SetRangedAttack(t.Direction)
+	default:
+		out.SetWait()
+	}
+}
+
+// TurnsFromWire converts a list of turns from the wire representation.
+func TurnsFromWire(wire botapi.Turn_List) []Turn {
+	ts := make([]Turn, wire.Len())
+	for i := range ts {
+		ts[i] = TurnFromWire(wire.At(i))
+	}
+	return ts
+}
+
+// rangedDistance is how far away ranged attacks hit.
+const rangedDistance = 2
+
+// target returns the location the move is moving to, attacking or healing.
+func (m botMove) target() Loc {
+	xOff, yOff := directionOffsets(m.Turn.Direction)
+	if m.Turn.Kind == botapi.Turn_Which_rangedAttack {
+		xOff, yOff = xOff*rangedDistance, yOff*rangedDistance
+	}
+	return Loc{X: m.Location.X + xOff, Y: m.Location.Y + yOff}
+}
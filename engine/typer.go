@@ -1,12 +1,23 @@
 package engine
 
-import "math"
+import (
+	"math"
+	"math/rand"
+)
 
 type Typer interface {
 	// Return the type of cell those x, y coordinates should be
 	Type(x, y int) CellType
 }
 
+// SeededTyper is implemented by Typers whose layout is random. The board calls
+// Seeded once with its random source and uses the returned Typer, so the same
+// seed always produces the same map.
+type SeededTyper interface {
+	Typer
+	Seeded(r *rand.Rand) Typer
+}
+
 type baseCircle struct {
 	size Loc
 	c    [][]CellType
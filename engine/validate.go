@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/bcspragu/Gobots/botapi"
+	"zombiezen.com/go/capnproto2"
+)
+
+// ViolationType is the kind of problem found with a bot's turns.
+type ViolationType int
+
+const (
+	UnknownViolation ViolationType = iota
+	ForeignRobot                   // A turn for a robot owned by another faction
+	DeadRobot                      // A turn for a robot that was destroyed
+	NoSuchRobot                    // A turn for an ID that was never handed out
+	DuplicateTurn                  // More than one turn for the same robot
+	MissingTurn                    // No turn for a robot the faction owns
+	DisallowedAction               // A kind of turn the rules don't allow
+)
+
+// A Violation is a turn a bot sent that the engine refused to play as-is.
+type Violation struct {
+	Round int
+	Robot RobotID
+	Type  ViolationType
+}
+
+func (v Violation) String() string {
+	var msg string
+	switch v.Type {
+	case ForeignRobot:
+		msg = "belongs to another player, ignoring its turn"
+	case DeadRobot:
+		msg = "has been destroyed, ignoring its turn"
+	case NoSuchRobot:
+		msg = "doesn't exist, ignoring its turn"
+	case DuplicateTurn:
+		msg = "was given more than one turn, using the first"
+	case MissingTurn:
+		msg = "wasn't given a turn, so it waits"
+	case DisallowedAction:
+		msg = "was given an action the rules don't allow, so it waits"
+	default:
+		msg = "had an unknown problem"
+	}
+	return fmt.Sprintf("round %d: robot %s %s", v.Round, v.Robot, msg)
+}
+
+// ValidateTurns checks the turns a bot sent for faction against the board, and
+// returns a list holding exactly one turn for each of the faction's robots, in
+// a new message. Turns for robots the faction doesn't own (or that aren't on
+// the board) are dropped, only the first turn for a robot is kept, and robots
+// without a turn, or with an action the rules don't allow, wait. Everything
+// that had to be changed is returned as a Violation. Turns should always pass
+// through ValidateTurns before being given to Update.
+func (b *Board) ValidateTurns(faction int, turns botapi.Turn_List) (botapi.Turn_List, []Violation, error) {
+	ts, vs := b.CheckTurns(faction, TurnsFromWire(turns))
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		return botapi.Turn_List{}, vs, err
+	}
+	out, err := botapi.NewTurn_List(seg, int32(len(ts)))
+	if err != nil {
+		return botapi.Turn_List{}, vs, err
+	}
+	for i, t := range ts {
+		t.ToWire(out.At(i))
+	}
+	return out, vs, nil
+}
+
+// CheckTurns is ValidateTurns for turns in the engine's representation, which
+// returns exactly one turn for each of the faction's robots, ready for Apply.
+func (b *Board) CheckTurns(faction int, turns []Turn) ([]Turn, []Violation) {
+	var vs []Violation
+	violation := func(id RobotID, vt ViolationType) {
+		vs = append(vs, Violation{Round: b.Round, Robot: id, Type: vt})
+	}
+
+	// The index of the turn we'll use for each robot, or -1 if it has none yet.
+	owned := make(map[RobotID]int)
+	var order []RobotID
+	for _, loc := range b.sortedLocs() {
+		if r := b.at(loc); r.Faction == faction {
+			owned[r.ID] = -1
+			order = append(order, r.ID)
+		}
+	}
+
+	for i, t := range turns {
+		idx, ok := owned[t.ID]
+		switch {
+		case ok && idx == -1:
+			owned[t.ID] = i
+		case ok:
+			violation(t.ID, DuplicateTurn)
+		case t.ID == 0 || t.ID > b.NextID:
+			violation(t.ID, NoSuchRobot)
+		default:
+			if _, r := b.fromID(t.ID); r != nil {
+				violation(t.ID, ForeignRobot)
+			} else {
+				violation(t.ID, DeadRobot)
+			}
+		}
+	}
+
+	out := make([]Turn, len(order))
+	for i, id := range order {
+		idx := owned[id]
+		switch {
+		case idx == -1:
+			violation(id, MissingTurn)
+		case !b.Rules.allows(turns[idx].Kind):
+			violation(id, DisallowedAction)
+		default:
+			out[i] = turns[idx]
+			continue
+		}
+		out[i] = Turn{ID: id, Kind: botapi.Turn_Which_wait}
+	}
+	return out, vs
+}
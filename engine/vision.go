@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/bcspragu/Gobots/botapi"
+)
+
+// Spectator is the faction of someone watching a game rather than playing in it,
+// who sees every robot on the board no matter the vision rules. Replays are
+// recorded as a Spectator.
+const Spectator = 0
+
+// Metric is how distances are measured for a RuleSet's VisionRadius.
+type Metric int
+
+const (
+	Manhattan Metric = iota // The number of moves it takes, ignoring walls
+	Chebyshev               // Diagonal steps count as one, so vision is a square
+)
+
+func (m Metric) String() string {
+	return metricToWire[m].String()
+}
+
+var (
+	metricToWire = map[Metric]botapi.Metric{
+		Manhattan: botapi.Metric_manhattan,
+		Chebyshev: botapi.Metric_chebyshev,
+	}
+
+	metricFromWire = map[botapi.Metric]Metric{
+		botapi.Metric_manhattan: Manhattan,
+		botapi.Metric_chebyshev: Chebyshev,
+	}
+)
+
+// ParseMetric returns the metric with the given name.
+func ParseMetric(name string) (Metric, error) {
+	for m := range metricToWire {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+	return Manhattan, fmt.Errorf("unknown metric %q", name)
+}
+
+// distance returns the distance between two locations.
+func (m Metric) distance(loc1, loc2 Loc) int {
+	if m == Chebyshev {
+		dx, dy := abs(loc1.X-loc2.X), abs(loc1.Y-loc2.Y)
+		if dx > dy {
+			return dx
+		}
+		return dy
+	}
+	return manhattanDistance(loc1, loc2)
+}
+
+// Sees reports whether the given faction can see a location. A faction sees
+// everything within the rules' vision radius of any robot on its side, and the
+// whole board if the rules don't limit vision or it's a Spectator.
+func (b *Board) Sees(faction int, loc Loc) bool {
+	if b.Rules.VisionRadius <= 0 || faction == Spectator {
+		return true
+	}
+	for l, r := range b.Locs {
+		if b.allies(r.Faction, faction) && b.Rules.VisionMetric.distance(l, loc) <= b.Rules.VisionRadius {
+			return true
+		}
+	}
+	return false
+}
+
+// visible reports whether the given faction can see the robot r at loc, which
+// it always can if r is on its side.
+func (b *Board) visible(faction int, loc Loc, r *Robot) bool {
+	return b.allies(r.Faction, faction) || b.Sees(faction, loc)
+}
+
+// ViewAtXY is like AtXY, but as the given faction sees the board, so robots it
+// can't see are left out, and the cell is Fogged if it's out of sight.
+func (b *Board) ViewAtXY(faction, x, y int) CellInfo {
+	ci := b.AtXY(x, y)
+	loc := Loc{X: x, Y: y}
+	if !b.Sees(faction, loc) {
+		ci.Fogged = true
+		if ci.Bot != nil && !b.allies(ci.Bot.Faction, faction) {
+			ci.Bot = nil
+		}
+	}
+	return ci
+}
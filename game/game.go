@@ -23,11 +23,22 @@ example AI is included below:
 The winner of a game is determined by who has more robots on the board after
 100 rounds, with robots spawning at the left and right edges of the board every
 10 turns. The rules are nearly identical to those of RobotGame, available at
-https://robotgame.net/rules.
+https://robotgame.net/rules. Some games are played with different numbers
+(shorter games, more damage), which are available in Board.Rules.
 */
 package game
 
-import "github.com/bcspragu/Gobots/botapi"
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	"github.com/bcspragu/Gobots/botapi"
+	"github.com/bcspragu/Gobots/engine"
+	"zombiezen.com/go/capnproto2"
+	"zombiezen.com/go/capnproto2/server"
+)
 
 // Board represents the state of the board in a round.
 type Board struct {
@@ -35,31 +46,235 @@ type Board struct {
 	Size  Loc
 	Cells [][]*Robot
 	LType [][]LocType
+	Rules RuleSet
+	// Symmetry is how the players' parts of the board mirror each other.
+	Symmetry Symmetry
+
+	// Players is the number of players in the game, and Player is which one of
+	// them you are, from 1 to Players.
+	Players int
+	Player  int
+
+	// Teams is the number of teams the players are split into, or 0 if everyone
+	// plays for themselves. Players next to each other in order are on the same
+	// team, so in a 2v2 game players 1 and 2 are against 3 and 4.
+	Teams int
+
+	// SpawnRounds are the rounds robots spawn at the end of, when the game has
+	// its own spawn schedule. When it's empty, Rules.SpawnEvery and
+	// Rules.SpawnUntil decide.
+	SpawnRounds []int
 }
 
+// RuleSet holds the numbers that decide how a game plays out.
+type RuleSet struct {
+	InitialHealth   int
+	CollisionDamage int
+	AttackDamage    int
+	DestructDamage  int
+
+	// GuardMultiplier scales the attack and self-destruct damage done to a
+	// robot that's guarding.
+	GuardMultiplier float64
+
+	// SpawnEvery is the number of rounds between spawns, and no robots spawn on
+	// or after round SpawnUntil.
+	SpawnEvery int
+	SpawnUntil int
+
+	MaxRounds int
+
+	// FriendlyFire is whether attacks and self-destructs hurt your own robots.
+	FriendlyFire bool
+
+	// IllegalPenalty is what happens to robots that try to move off the board
+	// or into a wall, or attack a wall. IllegalDamage is how much they get hurt
+	// for it under DamagePenalty.
+	IllegalPenalty Penalty
+	IllegalDamage  int
+
+	// EndOnElimination is whether the game ends as soon as a side has no robots
+	// left at the end of a round.
+	EndOnElimination bool
+
+	// Tiebreaks decide games where both sides end up with the same number of
+	// robots. They're tried in order until one picks a winner.
+	Tiebreaks [2]Tiebreak
+
+	// VisionRadius is how far each side can see from its robots, measured with
+	// VisionMetric. Opponents' robots out of sight aren't on the Board at all.
+	// Zero means the whole board can be seen.
+	VisionRadius int
+	VisionMetric Metric
+
+	// The numbers for terrain: the damage done to robots on hazards each round,
+	// how much attacks on robots in cover are scaled by, and how much robots on
+	// healing cells get back each round, up to InitialHealth.
+	HazardDamage    int
+	CoverMultiplier float64
+	HealAmount      int
+
+	// AllyHeal is how much a Heal action gives back, and RangedDamage is how
+	// much a RangedAttack does. Robots can only take those actions when these
+	// are more than zero, otherwise they wait instead.
+	AllyHeal     int
+	RangedDamage int
+
+	// SpawnCamping is what happens to robots standing on spawn cells when
+	// robots spawn, and CampingDamage is how much they get hurt under
+	// DamageCampers.
+	SpawnCamping  Camping
+	CampingDamage int
+}
+
+// Camping is what happens to robots standing on spawn cells when it's time for
+// robots to spawn.
+type Camping int
+
+// The kinds of spawn camping.
+const (
+	// KillCampers destroys every robot on a spawn cell.
+	KillCampers = Camping(botapi.Camping_kill)
+	// SkipOccupied leaves robots on spawn cells alone, and nothing spawns under
+	// them.
+	SkipOccupied = Camping(botapi.Camping_skip)
+	// DamageCampers does CampingDamage to robots on spawn cells, and nothing
+	// spawns under the ones that survive.
+	DamageCampers = Camping(botapi.Camping_damage)
+)
+
+// Metric is how distances are measured for the vision radius.
+type Metric int
+
+// The kinds of metric.
+const (
+	// Manhattan is the number of moves it takes to get somewhere, ignoring
+	// walls.
+	Manhattan = Metric(botapi.Metric_manhattan)
+	// Chebyshev counts diagonal steps as one, so vision is a square.
+	Chebyshev = Metric(botapi.Metric_chebyshev)
+)
+
+// Tiebreak is a way of deciding a game where both sides end up with the same
+// number of robots.
+type Tiebreak int
+
+// The kinds of tiebreak.
+const (
+	NoTiebreak = Tiebreak(botapi.Tiebreak_none)
+	// TotalHealth gives the game to the side with the most health left.
+	TotalHealth = Tiebreak(botapi.Tiebreak_totalHealth)
+	// DamageDealt gives the game to the side that did the most damage to the
+	// other side's robots.
+	DamageDealt = Tiebreak(botapi.Tiebreak_damageDealt)
+)
+
+// Penalty is what happens to a robot that tries an illegal action. Whatever the
+// penalty, the robot stays where it is, and the action is counted on your bot's
+// match history, to help find pathing bugs.
+type Penalty int
+
+// The kinds of penalty.
+const (
+	// NoPenalty quietly turns illegal actions into waits.
+	NoPenalty = Penalty(botapi.Penalty_none)
+	// CountPenalty also marks illegal actions in the game's replay.
+	CountPenalty = Penalty(botapi.Penalty_count)
+	// DamagePenalty marks illegal actions, and hurts the robots that try them.
+	DamagePenalty = Penalty(botapi.Penalty_damage)
+)
+
 // A Robot is a piece on the board.
 type Robot struct {
 	ID      uint32
 	Loc     Loc
 	Faction Faction
 	Health  int
+
+	// Player is the player that owns the robot, from 1 to Board.Players, which
+	// tells different opponents apart in games with more than two players.
+	Player int
+
+	// Entering is the slow cell the robot spent the last round getting partway
+	// into, or nil. Moving into it again this round gets the robot there.
+	Entering *Loc
 }
 
-// Faction identifies who owns a robot.
+// Faction identifies whether a robot is yours. In games with more than two
+// players, every other player's robots are OpponentFaction, see Robot.Player,
+// except for your teammates' in team games, which are AllyFaction.
 type Faction int
 
 const (
 	MyFaction Faction = iota + 1
 	OpponentFaction
+	AllyFaction
 )
 
-// LocType identifies what properties (invalid, valid, spawn) the location has
+// LocType identifies what properties (invalid, valid, spawn, or a kind of
+// terrain) the location has
 type LocType int
 
 const (
 	Invalid LocType = iota
 	Valid
 	Spawn
+
+	// Hazard hurts the robot on it by the rules' HazardDamage every round.
+	Hazard
+	// Cover scales attacks on the robot on it by the rules' CoverMultiplier.
+	Cover
+	// Slow takes two rounds of moving the same way to get into, see
+	// Robot.Entering.
+	Slow
+	// Healing gives the robot on it the rules' HealAmount back every round.
+	Healing
+)
+
+// Walkable reports whether robots can stand on the location, which is
+// anywhere but Invalid.
+func (t LocType) Walkable() bool {
+	return t != Invalid
+}
+
+// Hurts reports whether robots standing on the location get hurt every round.
+func (t LocType) Hurts() bool {
+	return t == Hazard
+}
+
+// Heals reports whether robots standing on the location heal every round.
+func (t LocType) Heals() bool {
+	return t == Healing
+}
+
+// Covers reports whether robots standing on the location take less damage from
+// attacks.
+func (t LocType) Covers() bool {
+	return t == Cover
+}
+
+// Slows reports whether getting into the location takes two rounds.
+func (t LocType) Slows() bool {
+	return t == Slow
+}
+
+// Symmetry is how one player's half of the board mirrors the other's.
+type Symmetry int
+
+// The kinds of symmetry.
+const (
+	// Horizontal boards mirror left to right.
+	Horizontal = Symmetry(botapi.Symmetry_horizontal)
+	// Vertical boards mirror top to bottom.
+	Vertical = Symmetry(botapi.Symmetry_vertical)
+	// Rotational boards are the same when turned 180 degrees.
+	Rotational = Symmetry(botapi.Symmetry_rotational)
+	// Diagonal boards mirror across the diagonal from the top left corner to
+	// the bottom right one.
+	Diagonal = Symmetry(botapi.Symmetry_diagonal)
+	// Quartered boards are the same when turned 90 degrees, with up to four
+	// players, each a quarter turn clockwise from the last.
+	Quartered = Symmetry(botapi.Symmetry_quartered)
 )
 
 // An AI is an algorithm that makes moves for a particular game.
@@ -67,6 +282,60 @@ type AI interface {
 	Act(board *Board, r *Robot) Action
 }
 
+// A TeamAI is an AI that plans the actions for all of your robots at once, so
+// they can work together. ActAll is called once a round with the board, and
+// returns the action for each of your robots, keyed by robot ID. Robots without
+// an action wait.
+//
+// An AI from a Factory that also has an ActAll method is used as a TeamAI, and
+// its Act method is never called. TeamBot turns a TeamAI into an AI for a
+// Factory.
+type TeamAI interface {
+	ActAll(board *Board) map[uint32]Action
+}
+
+// PerRobot returns a TeamAI that asks ai for each of your robots' actions in
+// turn.
+func PerRobot(ai AI) TeamAI {
+	return perRobot{ai}
+}
+
+type perRobot struct {
+	ai AI
+}
+
+func (p perRobot) ActAll(b *Board) map[uint32]Action {
+	actions := make(map[uint32]Action)
+	for _, r := range b.Bots(MyFaction) {
+		actions[r.ID] = p.ai.Act(b, r)
+	}
+	return actions
+}
+
+// TeamBot returns an AI that plays like t, for passing to ToFactory or
+// returning from a Factory.
+func TeamBot(t TeamAI) AI {
+	return teamBot{t}
+}
+
+type teamBot struct {
+	TeamAI
+}
+
+// Act plans all of the robots' actions and picks out r's, which only happens if
+// the AI is used outside of a game.
+func (t teamBot) Act(b *Board, r *Robot) Action {
+	return t.ActAll(b)[r.ID]
+}
+
+// asTeam returns ai as a TeamAI, wrapping it with PerRobot if it isn't one.
+func asTeam(ai AI) TeamAI {
+	if t, ok := ai.(TeamAI); ok {
+		return t
+	}
+	return PerRobot(ai)
+}
+
 // Loc is a coordinate pair.
 type Loc struct {
 	X, Y int
@@ -76,6 +345,11 @@ var locFromWire = map[botapi.CellType]LocType{
 	botapi.CellType_invalid: Invalid,
 	botapi.CellType_valid:   Valid,
 	botapi.CellType_spawn:   Spawn,
+
+	botapi.CellType_hazard:  Hazard,
+	botapi.CellType_cover:   Cover,
+	botapi.CellType_slow:    Slow,
+	botapi.CellType_healing: Healing,
 }
 
 // A Action represents what a robot will do.  The zero value waits the turn.
@@ -97,6 +371,10 @@ func (a Action) ToWire(id uint32, wire botapi.Turn) {
 		wire.SetSelfDestruct()
 	case Guard:
 		wire.SetGuard()
+	case Heal:
+		wire.SetHeal(a.Direction.toWire())
+	case RangedAttack:
+		wire.SetRangedAttack(a.Direction.toWire())
 	}
 }
 
@@ -110,6 +388,12 @@ const (
 	Attack
 	SelfDestruct
 	Guard
+	// Heal gives health back to the robot next to yours in Direction, if it's
+	// on your side. Only some rules allow it, see RuleSet.AllyHeal.
+	Heal
+	// RangedAttack attacks the cell two away in Direction, over anything in
+	// between. Only some rules allow it, see RuleSet.RangedDamage.
+	RangedAttack
 )
 
 // Direction is a cardinal direction.
@@ -131,20 +415,206 @@ func (d Direction) toWire() botapi.Direction {
 // Factory is a function that creates an AI per game.
 type Factory func(gameID string) AI
 
+// A SharedFactory is a Factory that's also told whether the AI it returns may
+// be used by several goroutines at once. That's the case when the bot plays its
+// games concurrently, see ServerConfig.Concurrent, where different games' AIs
+// take their turns at the same time, so an AI returned for more than one game
+// has to be safe for concurrent use. Each game's AI is only used by one
+// goroutine at a time either way. A SharedFactory for concurrent games can
+// itself be called concurrently.
+type SharedFactory func(gameID string, shared bool) AI
+
+// shared returns f as a SharedFactory, which ignores whether its AI is shared.
+func (f Factory) shared() SharedFactory {
+	return func(gameID string, _ bool) AI {
+		return f(gameID)
+	}
+}
+
+// A GameStarter is an AI that wants to know when its game starts. GameStarted
+// is called with the board the game starts on, before the AI's first turn.
+// Servers from before this existed don't say when games start, in which case it
+// isn't called.
+type GameStarter interface {
+	GameStarted(board *Board)
+}
+
+// A GameEnder is an AI that wants to know how its game turned out. GameEnded is
+// called once the game is over, however it ended, and the AI isn't used again
+// after it returns.
+type GameEnder interface {
+	GameEnded(result *Result)
+}
+
+// EndReason is why a game ended.
+type EndReason int
+
+// The reasons a game can end.
+const (
+	// RoundLimit means every round was played.
+	RoundLimit = EndReason(botapi.EndReason_roundLimit)
+	// Elimination means a side had no robots left.
+	Elimination = EndReason(botapi.EndReason_elimination)
+	// Forfeited means every other side forfeited, by running out of time or
+	// disconnecting.
+	Forfeited = EndReason(botapi.EndReason_forfeit)
+	// Aborted means the server stopped the game before it was over, and nobody
+	// won.
+	Aborted = EndReason(botapi.EndReason_aborted)
+)
+
+func (r EndReason) String() string {
+	switch r {
+	case RoundLimit:
+		return "round limit"
+	case Elimination:
+		return "elimination"
+	case Forfeited:
+		return "forfeit"
+	case Aborted:
+		return "aborted"
+	}
+	return "unknown"
+}
+
+// Result is how a game turned out.
+type Result struct {
+	GameID string
+	// Board is the board at the end of the game.
+	Board  *Board
+	Reason EndReason
+
+	// Winner is the side that won: a team in team games, a player otherwise,
+	// or 0 for a tie. Tiebreak is the tiebreak that decided it, if one did.
+	Winner   int
+	Tiebreak Tiebreak
+
+	// Scores are the number of robots each player had left, in player order.
+	Scores []int
+}
+
+// Won reports whether you, or your team in team games, won the game.
+func (r *Result) Won() bool {
+	b := r.Board
+	side := b.me()
+	if b.Teams > 0 && b.Players > 0 {
+		side = (side-1)*b.Teams/b.Players + 1
+	}
+	return r.Winner != 0 && r.Winner == side
+}
+
 type gameState struct {
-	ai   AI
-	locs [][]LocType
+	// last is closed when the last call for the game to arrive is done with
+	// the game's state, which the next call to arrive waits for, so calls use
+	// it one at a time and in order. It's protected by the adapter's mu.
+	last chan struct{}
+
+	id       string
+	ai       TeamAI
+	locs     [][]LocType
+	rules    RuleSet
+	symmetry Symmetry
+	players  int
+	player   int
+	teams    int
+
+	spawnRounds []int
+
+	// orig is the AI the factory made, which might want to know when the game
+	// starts and ends.
+	orig AI
+
+	// crashes is how many times the AI has panicked in the game.
+	crashes int
 }
 
 // aiAdapter is a type that implements botapi.Ai by mapping turns to
 // games and calling the AI interface methods.
 //
-// Note: since TakeTurn does not call server.Ack, the Cap'n Proto
-// concurrency model guarantees that each call to TakeTurn happens after
-// the previous return. Thus, we don't need to add any additional locks.
+// Note: unless it's concurrent, none of its methods call server.Ack, so the
+// Cap'n Proto concurrency model guarantees that each call happens after the
+// previous return. A concurrent adapter acknowledges each call once it has
+// its place in line for the call's game, see enter, so calls for different
+// games run at the same time and calls for the same game still run in order.
 type aiAdapter struct {
-	factory Factory
-	games   map[string]gameState
+	factory    SharedFactory
+	concurrent bool
+
+	// ended, if it's set, is called after the AI for a game is told the game
+	// ended.
+	ended func(*Result)
+
+	mu    sync.Mutex // protects games
+	games map[string]*gameState
+}
+
+func newAIAdapter(factory SharedFactory, concurrent bool, ended func(*Result)) *aiAdapter {
+	return &aiAdapter{
+		factory:    factory,
+		concurrent: concurrent,
+		ended:      ended,
+		games:      make(map[string]*gameState),
+	}
+}
+
+func (a *aiAdapter) GameStarted(call botapi.Ai_gameStarted) error {
+	ib, err := call.Params.Board()
+	if err != nil {
+		return err
+	}
+	b, _, gs, done, err := a.board(call.Options, ib, true)
+	if err != nil {
+		return err
+	}
+	defer done()
+	if s, ok := gs.orig.(GameStarter); ok {
+		gs.protect(b.Round, "GameStarted", func() { s.GameStarted(b) })
+	}
+	return nil
+}
+
+func (a *aiAdapter) GameEnded(call botapi.Ai_gameEnded) error {
+	res, err := call.Params.Result()
+	if err != nil {
+		return err
+	}
+	ib, err := res.Board()
+	if err != nil {
+		return err
+	}
+	b, _, gs, done, err := a.board(call.Options, ib, false)
+	if err != nil || gs == nil {
+		// There's nothing to tell about a game the AI never played.
+		return err
+	}
+	defer done()
+	wireScores, err := res.Scores()
+	if err != nil {
+		return err
+	}
+	r := &Result{
+		GameID:   gs.id,
+		Board:    b,
+		Reason:   EndReason(res.Reason()),
+		Winner:   int(res.Winner()),
+		Tiebreak: Tiebreak(res.Tiebreak()),
+		Scores:   make([]int, wireScores.Len()),
+	}
+	for i := range r.Scores {
+		r.Scores[i] = int(wireScores.At(i))
+	}
+
+	// The game's over, so there won't be any more turns for it.
+	a.mu.Lock()
+	delete(a.games, gs.id)
+	a.mu.Unlock()
+	if e, ok := gs.orig.(GameEnder); ok {
+		gs.protect(b.Round, "GameEnded", func() { e.GameEnded(r) })
+	}
+	if a.ended != nil {
+		a.ended(r)
+	}
+	return nil
 }
 
 func (a *aiAdapter) TakeTurn(call botapi.Ai_takeTurn) error {
@@ -152,47 +622,155 @@ func (a *aiAdapter) TakeTurn(call botapi.Ai_takeTurn) error {
 	if err != nil {
 		return err
 	}
-	board, err := ib.Board()
+	b, robots, gs, done, err := a.board(call.Options, ib, true)
 	if err != nil {
 		return err
 	}
-	gameID, err := board.GameId()
+	defer done()
+	turns, err := botapi.NewTurn_List(call.Results.Segment(), int32(len(robots)))
 	if err != nil {
 		return err
 	}
+	actions := gs.act(b)
+	for i, r := range robots {
+		actions[r.ID].ToWire(r.ID, turns.At(i))
+	}
+	call.Results.SetTurns(turns)
+	call.Results.SetCrashes(uint32(gs.crashes))
+	return nil
+}
+
+// act gets the actions for your robots on b from the game's AI. If the AI
+// panics, the robots it was deciding for wait instead of the whole bot going
+// down: just the one robot for an AI that acts per robot, or all of them for a
+// TeamAI.
+func (gs *gameState) act(b *Board) map[uint32]Action {
+	p, ok := gs.ai.(perRobot)
+	if !ok {
+		var actions map[uint32]Action
+		gs.protect(b.Round, "ActAll", func() { actions = gs.ai.ActAll(b) })
+		return actions
+	}
+	actions := make(map[uint32]Action)
+	for _, r := range b.Bots(MyFaction) {
+		r := r
+		gs.protect(b.Round, fmt.Sprintf("Act for robot %d", r.ID), func() { actions[r.ID] = p.ai.Act(b, r) })
+	}
+	return actions
+}
+
+// protect calls f, which calls into the game's AI, and recovers if it panics,
+// logging where along with the stack trace and counting the crash.
+func (gs *gameState) protect(round int, what string, f func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			gs.crashes++
+			log.Printf("game %s, round %d: %s panicked: %v\n%s", gs.id, round, what, v, debug.Stack())
+		}
+	}()
+	f()
+}
+
+// board converts ib to the game representation, along with your robots on it,
+// and returns the state for its game once it's this call's turn to use it,
+// along with a function to call when it's done. If start is true, a new game
+// is started, otherwise the state for a game the adapter doesn't know about is
+// nil.
+func (a *aiAdapter) board(opts capnp.CallOptions, ib botapi.InitialBoard, start bool) (*Board, []*Robot, *gameState, func(), error) {
+	board, err := ib.Board()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	gameID, err := board.GameId()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	// Convert the board to the game representation
 	b, robots, err := convertBoard(board)
 	if err != nil {
-		return err
+		return nil, nil, nil, nil, err
+	}
+
+	gs, done := a.enter(opts, gameID, start)
+	if gs == nil {
+		return nil, nil, nil, nil, nil
 	}
 
 	// Load the AI for this game, or create a new one
-	ai := a.games[gameID].ai
-	if ai == nil {
-		ai = a.factory(gameID)
-
-		// Load the cells for the board
-		cells, err := ib.Cells()
-		if err != nil {
-			return nil
-		}
-		locs := convertLocs(cells, len(b.Cells), len(b.Cells[0]))
-		a.games[gameID] = gameState{
-			ai:   ai,
-			locs: locs,
+	if gs.ai == nil {
+		if err := gs.start(ib, b, a.factory(gameID, a.concurrent)); err != nil {
+			done()
+			return nil, nil, nil, nil, err
 		}
 	}
-	b.LType = a.games[gameID].locs
-	turns, err := botapi.NewTurn_List(call.Results.Segment(), int32(len(robots)))
+	b.LType = gs.locs
+	b.Rules = gs.rules
+	b.Symmetry = gs.symmetry
+	b.Players = gs.players
+	b.Player = gs.player
+	b.Teams = gs.teams
+	b.SpawnRounds = gs.spawnRounds
+	return b, robots, gs, done, nil
+}
+
+// enter takes the next place in line for the game's state, creating the state
+// if start is true and the game is new, and waits for the calls ahead of it to
+// be done. It returns the state and a function to call when the caller is done
+// with it, or nil if the game is new and start is false. A concurrent adapter
+// acknowledges the call once it has its place, so calls for other games can go
+// ahead while it waits.
+func (a *aiAdapter) enter(opts capnp.CallOptions, gameID string, start bool) (*gameState, func()) {
+	a.mu.Lock()
+	gs, ok := a.games[gameID]
+	if !ok && !start {
+		a.mu.Unlock()
+		return nil, nil
+	}
+	if !ok {
+		gs = &gameState{id: gameID, last: make(chan struct{})}
+		close(gs.last)
+		a.games[gameID] = gs
+	}
+	prev, done := gs.last, make(chan struct{})
+	gs.last = done
+	a.mu.Unlock()
+
+	if a.concurrent {
+		server.Ack(opts)
+	}
+	<-prev
+	return gs, func() { close(done) }
+}
+
+// start fills in the state for a new game played by ai on ib, which converts
+// to b.
+func (gs *gameState) start(ib botapi.InitialBoard, b *Board, ai AI) error {
+	// Load the cells for the board
+	cells, err := ib.Cells()
 	if err != nil {
 		return err
 	}
-	for i, r := range robots {
-		t := ai.Act(b, r)
-		t.ToWire(r.ID, turns.At(i))
+	rules, err := ib.Rules()
+	if err != nil {
+		return err
 	}
-	call.Results.SetTurns(turns)
+	wireRounds, err := ib.SpawnRounds()
+	if err != nil {
+		return err
+	}
+	gs.spawnRounds = make([]int, wireRounds.Len())
+	for i := range gs.spawnRounds {
+		gs.spawnRounds[i] = int(wireRounds.At(i))
+	}
+	gs.locs = convertLocs(cells, len(b.Cells), len(b.Cells[0]))
+	gs.rules = convertRules(rules)
+	gs.symmetry = Symmetry(ib.Symmetry())
+	gs.players = int(ib.Players())
+	gs.player = int(ib.Player())
+	gs.teams = int(ib.Teams())
+	gs.orig = ai
+	gs.ai = asTeam(ai)
 	return nil
 }
 
@@ -209,6 +787,12 @@ func convertLocs(wireLocs botapi.CellType_List, w, h int) [][]LocType {
 	return cols
 }
 
+// convertRules reads rules off the wire the way the engine does, so rules from
+// before a field existed mean the same thing to both.
+func convertRules(wire botapi.RuleSet) RuleSet {
+	return rulesFromEngine(engine.RulesFromWire(wire))
+}
+
 func convertBoard(wire botapi.Board) (b *Board, playerBots []*Robot, err error) {
 	w, h := int(wire.Width()), int(wire.Height())
 	cells := make([]*Robot, w*h)
@@ -228,11 +812,18 @@ func convertBoard(wire botapi.Board) (b *Board, playerBots []*Robot, err error)
 			ID:     r.Id(),
 			Loc:    l,
 			Health: int(r.Health()),
+			Player: int(r.Player()),
+		}
+		if d := r.Entering(); d != botapi.Direction_none {
+			e := l.Add(Direction(d))
+			rr.Entering = &e
 		}
 		switch r.Faction() {
 		case botapi.Faction_mine:
 			rr.Faction = MyFaction
 			playerBots = append(playerBots, rr)
+		case botapi.Faction_ally:
+			rr.Faction = AllyFaction
 		case botapi.Faction_opponent:
 			fallthrough
 		default:
@@ -0,0 +1,294 @@
+package game
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcspragu/Gobots/botapi"
+	"github.com/bcspragu/Gobots/engine"
+	"golang.org/x/net/context"
+)
+
+func TestMain(m *testing.M) {
+	// The AIs in these tests panic on purpose, and the stack traces would
+	// drown out everything else.
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// testGame returns the board for the start of a game, with robots for both
+// players.
+func testGame(t *testing.T) *engine.Board {
+	bc := engine.BoardConfig{
+		Size:      engine.Loc{X: 17, Y: 17},
+		Spawner:   engine.NewRandomSpawn(2),
+		CellTyper: engine.NewCircleSpawn(engine.Loc{X: 17, Y: 17}),
+	}
+	b := engine.EmptyBoard(bc)
+	b.InitBoard(bc)
+	if b.BotCount(engine.P1Faction) == 0 {
+		t.Fatal("no robots spawned")
+	}
+	return b
+}
+
+// myRobots returns the IDs of the first player's robots on b, in order.
+func myRobots(b *engine.Board) []uint32 {
+	var ids []uint32
+	for _, r := range b.Locs {
+		if r.Faction == engine.P1Faction {
+			ids = append(ids, uint32(r.ID))
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// testAdapter returns a client for an adapter that makes AIs with factory.
+func testAdapter(factory Factory, concurrent bool, ended func(*Result)) *localAI {
+	return &localAI{botapi.Ai_ServerToClient(newAIAdapter(factory.shared(), concurrent, ended))}
+}
+
+// takeTurn has the first player's bot take a turn on b in game gid, and
+// returns the kind of turn each robot took, keyed by robot ID, and the number
+// of times the AI has crashed in the game.
+func takeTurn(t *testing.T, la *localAI, gid string, b *engine.Board) (map[uint32]botapi.Turn_Which, int) {
+	res, err := la.takeTurn(context.Background(), gid, b, engine.P1Faction)
+	if err != nil {
+		t.Fatalf("takeTurn(%s, round %d): %v", gid, b.Round, err)
+	}
+	turns, err := res.Turns()
+	if err != nil {
+		t.Fatalf("takeTurn(%s, round %d): Turns: %v", gid, b.Round, err)
+	}
+	kinds := make(map[uint32]botapi.Turn_Which)
+	for i := 0; i < turns.Len(); i++ {
+		kinds[turns.At(i).Id()] = turns.At(i).Which()
+	}
+	return kinds, int(res.Crashes())
+}
+
+// all returns a map of every ID in ids to kind.
+func all(ids []uint32, kind botapi.Turn_Which) map[uint32]botapi.Turn_Which {
+	m := make(map[uint32]botapi.Turn_Which)
+	for _, id := range ids {
+		m[id] = kind
+	}
+	return m
+}
+
+// perRobotAI guards with every robot except panicky, which it panics for.
+type perRobotAI struct {
+	panicky uint32
+}
+
+func (p perRobotAI) Act(b *Board, r *Robot) Action {
+	if r.ID == p.panicky {
+		panic("can't decide")
+	}
+	return Action{Kind: Guard}
+}
+
+// teamAI guards with every robot, or panics if it's panicky. Its Act should
+// never be called in a game.
+type teamAI struct {
+	t       *testing.T
+	panicky bool
+}
+
+func (ta teamAI) Act(b *Board, r *Robot) Action {
+	ta.t.Error("Act called on a TeamAI")
+	return Action{}
+}
+
+func (ta teamAI) ActAll(b *Board) map[uint32]Action {
+	if ta.panicky {
+		panic("can't decide")
+	}
+	actions := make(map[uint32]Action)
+	for _, r := range b.Bots(MyFaction) {
+		actions[r.ID] = Action{Kind: Guard}
+	}
+	return actions
+}
+
+func TestAdapterActions(t *testing.T) {
+	b := testGame(t)
+	ids := myRobots(b)
+	first := ids[0]
+
+	withFirst := func(kind botapi.Turn_Which) map[uint32]botapi.Turn_Which {
+		m := all(ids, botapi.Turn_Which_guard)
+		m[first] = kind
+		return m
+	}
+
+	tests := []struct {
+		desc string
+		ai   AI
+		want map[uint32]botapi.Turn_Which
+		// crashes is how many times the AI crashes each turn.
+		crashes int
+	}{
+		{"per robot", perRobotAI{}, all(ids, botapi.Turn_Which_guard), 0},
+		{"per robot, panicking", perRobotAI{panicky: first}, withFirst(botapi.Turn_Which_wait), 1},
+		{"team", teamAI{t: t}, all(ids, botapi.Turn_Which_guard), 0},
+		{"team, panicking", teamAI{t: t, panicky: true}, all(ids, botapi.Turn_Which_wait), 1},
+		{"TeamBot", TeamBot(teamAI{t: t}), all(ids, botapi.Turn_Which_guard), 0},
+	}
+	for _, test := range tests {
+		la := testAdapter(func(string) AI { return test.ai }, false, nil)
+		// Crashes add up over the game.
+		for round := 1; round <= 2; round++ {
+			got, crashes := takeTurn(t, la, "game", b)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s, turn %d: turns = %v; want %v", test.desc, round, got, test.want)
+			}
+			if want := round * test.crashes; crashes != want {
+				t.Errorf("%s, turn %d: crashes = %d; want %d", test.desc, round, crashes, want)
+			}
+		}
+		// Other games start with a clean slate.
+		if _, crashes := takeTurn(t, la, "other", b); crashes != test.crashes {
+			t.Errorf("%s, other game: crashes = %d; want %d", test.desc, crashes, test.crashes)
+		}
+	}
+}
+
+// hookAI records when its game starts and ends.
+type hookAI struct {
+	perRobotAI
+	gid string
+	log *[]string
+}
+
+func (h hookAI) GameStarted(b *Board) {
+	*h.log = append(*h.log, "started "+h.gid)
+}
+
+func (h hookAI) GameEnded(r *Result) {
+	*h.log = append(*h.log, "ended "+r.GameID)
+	panic("sore loser")
+}
+
+func TestAdapterHooks(t *testing.T) {
+	b := testGame(t)
+	ctx := context.Background()
+
+	var got []string
+	factory := func(gid string) AI {
+		got = append(got, "new "+gid)
+		return hookAI{gid: gid, log: &got}
+	}
+	ended := func(r *Result) {
+		got = append(got, "told "+r.GameID)
+	}
+	la := testAdapter(factory, false, ended)
+
+	if err := la.gameStarted(ctx, "a", b, engine.P1Faction); err != nil {
+		t.Fatal("gameStarted(a):", err)
+	}
+	takeTurn(t, la, "a", b)
+	// Games from servers that don't say when they start start with their
+	// first turn.
+	takeTurn(t, la, "b", b)
+	if err := la.gameEnded(ctx, "a", b, engine.P1Faction); err != nil {
+		t.Fatal("gameEnded(a):", err)
+	}
+	// The AI isn't used after its game ends, so another game with the same ID
+	// gets a new one.
+	takeTurn(t, la, "a", b)
+	// A game the bot never played is left alone.
+	if err := la.gameEnded(ctx, "c", b, engine.P1Faction); err != nil {
+		t.Fatal("gameEnded(c):", err)
+	}
+
+	want := []string{
+		"new a", "started a",
+		"new b",
+		"ended a", "told a",
+		"new a",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
+
+// blockingAI records the rounds it plays, and waits to be let go in the rounds
+// in block.
+type blockingAI struct {
+	mu     sync.Mutex
+	rounds []int
+
+	entered chan int
+	block   map[int]chan struct{}
+}
+
+func (ba *blockingAI) ActAll(b *Board) map[uint32]Action {
+	ba.mu.Lock()
+	ba.rounds = append(ba.rounds, b.Round)
+	ba.mu.Unlock()
+	ba.entered <- b.Round
+	if c, ok := ba.block[b.Round]; ok {
+		<-c
+	}
+	return nil
+}
+
+func (ba *blockingAI) Act(b *Board, r *Robot) Action {
+	return Action{}
+}
+
+func TestAdapterOrder(t *testing.T) {
+	b1 := testGame(t)
+	b2 := b1.Clone()
+	b2.Round++
+
+	release := make(chan struct{})
+	slow := &blockingAI{entered: make(chan int, 2), block: map[int]chan struct{}{b1.Round: release}}
+	fast := &blockingAI{entered: make(chan int, 1)}
+	la := testAdapter(func(gid string) AI {
+		if gid == "slow" {
+			return slow
+		}
+		return fast
+	}, true, nil)
+
+	// takeTurn can't be used off the test's goroutine.
+	turn := func(b *engine.Board, done chan struct{}) {
+		defer close(done)
+		if _, err := la.takeTurn(context.Background(), "slow", b, engine.P1Faction); err != nil {
+			t.Errorf("takeTurn(slow, round %d): %v", b.Round, err)
+		}
+	}
+	done1, done2 := make(chan struct{}), make(chan struct{})
+	go turn(b1, done1)
+	<-slow.entered
+
+	// Another turn for the same game waits for the one ahead of it...
+	go turn(b2, done2)
+	// ...but a turn for a different game doesn't.
+	takeTurn(t, la, "fast", b1)
+
+	select {
+	case r := <-slow.entered:
+		t.Fatalf("round %d started before round %d was done", r, b1.Round)
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	<-done1
+	<-slow.entered
+	<-done2
+
+	slow.mu.Lock()
+	defer slow.mu.Unlock()
+	if want := []int{b1.Round, b2.Round}; !reflect.DeepEqual(slow.rounds, want) {
+		t.Errorf("rounds = %v; want %v", slow.rounds, want)
+	}
+}
@@ -17,32 +17,116 @@ type localAI struct {
 }
 
 type MatchResult struct {
+	// The number of robots each bot had left at the end.
 	P1Score int
 	P2Score int
+
+	// Winner is 1 or 2 for the player that won, or 0 for a tie. Ties on score
+	// are decided by Tiebreak, if the rules have one that picks a winner.
+	Winner   int
+	End      engine.EndReason
+	Tiebreak engine.Tiebreak
+
+	// The tiebreak values for each bot: the total health of its robots, and
+	// the damage they did to the other bot's.
+	P1Health int
+	P2Health int
+	P1Damage int
+	P2Damage int
+
+	// Seed is the seed the match was played with, passing it to FightSeeded
+	// replays the same match.
+	Seed int64
+
+	// Turns each bot sent that the server wouldn't play, like turns for robots
+	// that aren't theirs or robots that didn't get a turn.
+	P1Warnings []string
+	P2Warnings []string
+
+	// The illegal actions each bot's robots tried, like walking into walls.
+	// They're a good sign of pathing bugs.
+	P1Illegal engine.IllegalActions
+	P2Illegal engine.IllegalActions
+
+	// The number of times each bot's code panicked, which cost the robots it
+	// was deciding for their turn. The stack traces are logged.
+	P1Crashes int
+	P2Crashes int
+
+	// Err is why the match couldn't be played out, if it couldn't. The match is
+	// then aborted: End is engine.NotEnded and nobody won.
+	Err error
 }
 
 func (m *MatchResult) String() string {
+	return m.outcome() + m.crashes()
+}
+
+func (m *MatchResult) outcome() string {
+	if m.Err != nil {
+		return fmt.Sprintf("P1: %d P2: %d - Aborted: %v (seed %d)", m.P1Score, m.P2Score, m.Err, m.Seed)
+	}
 	outcome := "Tie"
-	if m.P1Score > m.P2Score {
+	switch m.Winner {
+	case 1:
 		outcome = "Player 1 wins"
-	} else if m.P1Score < m.P2Score {
+	case 2:
 		outcome = "Player 2 wins"
 	}
-	return fmt.Sprintf("P1: %d P2: %d - %s", m.P1Score, m.P2Score, outcome)
+	if m.Tiebreak != engine.NoTiebreak {
+		outcome += " on " + m.Tiebreak.String()
+	}
+	return fmt.Sprintf("P1: %d P2: %d - %s, ended by %s (seed %d)", m.P1Score, m.P2Score, outcome, m.End, m.Seed)
+}
+
+// crashes says how many times each bot crashed, if either did.
+func (m *MatchResult) crashes() string {
+	var s string
+	for i, n := range []int{m.P1Crashes, m.P2Crashes} {
+		switch {
+		case n == 1:
+			s += fmt.Sprintf(", P%d crashed once", i+1)
+		case n > 1:
+			s += fmt.Sprintf(", P%d crashed %d times", i+1, n)
+		}
+	}
+	return s
 }
 
+// FightBots plays a single match between the two bots with a fresh seed, and
+// returns the result. n is unused. The seed is in the result, and passing it to
+// FightSeeded plays the same match again.
 func FightBots(f1, f2 Factory, n int) MatchResult {
-	return fightN(f1, f2, 1)[0]
+	return fightN(f1, f2, 1, newSeed())[0]
 }
 
-// FightBotsN plays a match between the two bots and returns the result.
+// FightSeeded plays a single match between the two bots, seeded with seed, and
+// returns the result.
+func FightSeeded(f1, f2 Factory, seed int64) MatchResult {
+	return fightN(f1, f2, 1, seed)[0]
+}
+
+// FightBotsN plays n matches between the two bots and returns the results, each
+// with a fresh seed.
 func FightBotsN(f1, f2 Factory, n int) []MatchResult {
-	return fightN(f1, f2, n)
+	return fightN(f1, f2, n, newSeed())
+}
+
+// FightSeededN plays n matches between the two bots and returns the results.
+// The i-th match is seeded with seed+i, so any single match can be replayed
+// with FightSeeded.
+func FightSeededN(f1, f2 Factory, n int, seed int64) []MatchResult {
+	return fightN(f1, f2, n, seed)
+}
+
+// newSeed returns a seed for matches that weren't given one.
+func newSeed() int64 {
+	return time.Now().UnixNano()
 }
 
-func fightN(f1, f2 Factory, n int) []MatchResult {
-	aiA := &aiAdapter{factory: f1, games: make(map[string]gameState)}
-	aiB := &aiAdapter{factory: f2, games: make(map[string]gameState)}
+func fightN(f1, f2 Factory, n int, seed int64) []MatchResult {
+	aiA := newAIAdapter(f1.shared(), false, nil)
+	aiB := newAIAdapter(f2.shared(), false, nil)
 
 	a1, a2 := net.Pipe()
 	b1, b2 := net.Pipe()
@@ -72,36 +156,136 @@ func fightN(f1, f2 Factory, n int) []MatchResult {
 	matchRes := make([]MatchResult, n)
 	// Run the game
 	for i := 0; i < n; i++ {
-		b := engine.EmptyBoard(engine.DefaultConfig)
-		b.InitBoard(engine.DefaultConfig)
+		bc := engine.DefaultConfig
+		bc.Seed = seed + int64(i)
+		b := engine.EmptyBoard(bc)
+		b.InitBoard(bc)
 
+		var warnA, warnB []string
+		var crashA, crashB int
+
+		var matchErr error
+		gid := strconv.Itoa(i)
+		clientA.gameStarted(ctx, gid, b, engine.P1Faction)
+		clientB.gameStarted(ctx, gid, b, engine.P2Faction)
 		for !b.IsFinished() {
 			turnCtx, _ := context.WithTimeout(ctx, 30*time.Second)
-			resA, _ := clientA.takeTurn(turnCtx, strconv.Itoa(i), b, engine.P1Faction)
-			resB, _ := clientB.takeTurn(turnCtx, strconv.Itoa(i), b, engine.P2Faction)
-			b.Update(resA, resB)
+			// TODO Probably don't ignore errors
+			resA, _ := clientA.takeTurn(turnCtx, gid, b, engine.P1Faction)
+			resB, _ := clientB.takeTurn(turnCtx, gid, b, engine.P2Faction)
+			// The crash counts are for the whole game so far, and a turn that
+			// failed doesn't have one.
+			if n := int(resA.Crashes()); n > crashA {
+				crashA = n
+			}
+			if n := int(resB.Crashes()); n > crashB {
+				crashB = n
+			}
+			turnsA, _ := resA.Turns()
+			turnsB, _ := resB.Turns()
+			ta, va, err := b.ValidateTurns(engine.P1Faction, turnsA)
+			if err != nil {
+				matchErr = fmt.Errorf("player 1's turns in round %d: %v", b.Round, err)
+				break
+			}
+			tb, vb, err := b.ValidateTurns(engine.P2Faction, turnsB)
+			if err != nil {
+				matchErr = fmt.Errorf("player 2's turns in round %d: %v", b.Round, err)
+				break
+			}
+			warnA, warnB = appendWarnings(warnA, va), appendWarnings(warnB, vb)
+			b.Update(ta, tb)
+		}
+		clientA.gameEnded(ctx, gid, b, engine.P1Faction)
+		clientB.gameEnded(ctx, gid, b, engine.P2Faction)
+		res := b.Result()
+		if matchErr != nil {
+			// A game that didn't finish has no real outcome.
+			res.Reason, res.Winner, res.Tiebreak = engine.NotEnded, 0, engine.NoTiebreak
 		}
 		matchRes[i] = MatchResult{
-			P1Score: b.BotCount(1),
-			P2Score: b.BotCount(2),
+			P1Score:    res.Robots[engine.P1Faction],
+			P2Score:    res.Robots[engine.P2Faction],
+			Winner:     res.Winner,
+			End:        res.Reason,
+			Tiebreak:   res.Tiebreak,
+			P1Health:   res.Health[engine.P1Faction],
+			P2Health:   res.Health[engine.P2Faction],
+			P1Damage:   res.Damage[engine.P1Faction],
+			P2Damage:   res.Damage[engine.P2Faction],
+			Seed:       bc.Seed,
+			P1Warnings: warnA,
+			P2Warnings: warnB,
+			P1Illegal:  b.Illegal[engine.P1Faction],
+			P2Illegal:  b.Illegal[engine.P2Faction],
+			P1Crashes:  crashA,
+			P2Crashes:  crashB,
+			Err:        matchErr,
 		}
 	}
 	return matchRes
 }
 
-func (la *localAI) takeTurn(ctx context.Context, gid string, b *engine.Board, faction int) (botapi.Turn_List, error) {
-	// TODO Probably don't ignore errors
-	res, _ := la.TakeTurn(ctx, func(p botapi.Ai_takeTurn_Params) error {
+func appendWarnings(ws []string, vs []engine.Violation) []string {
+	for _, v := range vs {
+		ws = append(ws, v.String())
+	}
+	return ws
+}
+
+func (la *localAI) takeTurn(ctx context.Context, gid string, b *engine.Board, faction int) (botapi.Ai_takeTurn_Results, error) {
+	return la.TakeTurn(ctx, func(p botapi.Ai_takeTurn_Params) error {
 		iwb, err := p.NewBoard()
 		if err != nil {
 			return err
 		}
-		wb, err := iwb.NewBoard()
+		return boardToWire(iwb, gid, b, faction)
+	}).Struct()
+}
+
+func (la *localAI) gameStarted(ctx context.Context, gid string, b *engine.Board, faction int) error {
+	_, err := la.GameStarted(ctx, func(p botapi.Ai_gameStarted_Params) error {
+		iwb, err := p.NewBoard()
 		if err != nil {
 			return err
 		}
-		wb.SetGameId(gid)
-		return b.ToWireWithInitial(iwb, faction)
+		return boardToWire(iwb, gid, b, faction)
 	}).Struct()
-	return res.Turns()
+	return err
+}
+
+func (la *localAI) gameEnded(ctx context.Context, gid string, b *engine.Board, faction int) error {
+	_, err := la.GameEnded(ctx, func(p botapi.Ai_gameEnded_Params) error {
+		res, err := p.NewResult()
+		if err != nil {
+			return err
+		}
+		if err := b.ResultToWire(res, faction); err != nil {
+			return err
+		}
+		iwb, err := res.Board()
+		if err != nil {
+			return err
+		}
+		return setGameID(iwb, gid)
+	}).Struct()
+	return err
+}
+
+// boardToWire writes b, as faction sees it, to iwb for game gid.
+func boardToWire(iwb botapi.InitialBoard, gid string, b *engine.Board, faction int) error {
+	if err := b.ToWireWithInitial(iwb, faction); err != nil {
+		return err
+	}
+	return setGameID(iwb, gid)
+}
+
+// setGameID sets the game ID on a board that's already been written, since
+// writing the board replaces it.
+func setGameID(iwb botapi.InitialBoard, gid string) error {
+	wb, err := iwb.Board()
+	if err != nil {
+		return err
+	}
+	return wb.SetGameId(gid)
 }
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bcspragu/Gobots/engine"
+)
+
+func TestMatchResultString(t *testing.T) {
+	tests := []struct {
+		m    MatchResult
+		want string
+	}{
+		{
+			MatchResult{P1Score: 3, P2Score: 1, Winner: 1, End: engine.RoundLimit, Seed: 7},
+			"P1: 3 P2: 1 - Player 1 wins, ended by " + engine.RoundLimit.String() + " (seed 7)",
+		},
+		{
+			MatchResult{P1Score: 3, P2Score: 1, Winner: 1, End: engine.RoundLimit, Seed: 7, P1Crashes: 1, P2Crashes: 4},
+			"P1: 3 P2: 1 - Player 1 wins, ended by " + engine.RoundLimit.String() + " (seed 7), P1 crashed once, P2 crashed 4 times",
+		},
+		{
+			MatchResult{Seed: 7, P2Crashes: 2, Err: errors.New("boom")},
+			"P1: 0 P2: 0 - Aborted: boom (seed 7), P2 crashed 2 times",
+		},
+	}
+	for _, test := range tests {
+		if got := test.m.String(); got != test.want {
+			t.Errorf("String() = %q; want %q", got, test.want)
+		}
+	}
+}
+
+// waiter is a bot whose robots all wait.
+type waiter struct{}
+
+func (waiter) Act(b *Board, r *Robot) Action {
+	return Action{Kind: Wait}
+}
+
+func TestFightSeeded(t *testing.T) {
+	f := ToFactory(waiter{})
+	first := FightSeeded(f, f, 3)
+	if first.Err != nil || first.Seed != 3 {
+		t.Fatalf("FightSeeded = %v; want a match played with seed 3", &first)
+	}
+	if again := FightSeeded(f, f, 3); again.String() != first.String() {
+		t.Errorf("replaying seed 3 gave %v; want %v", &again, &first)
+	}
+	rs := FightSeededN(f, f, 2, 2)
+	if len(rs) != 2 || rs[0].Seed != 2 || rs[1].Seed != 3 {
+		t.Errorf("FightSeededN from seed 2 = %v; want matches seeded 2 and 3", rs)
+	}
+}
@@ -1,11 +1,12 @@
 package game
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
 	"net"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -15,13 +16,109 @@ import (
 )
 
 type ServerConfig struct {
-	RetryInterval time.Duration // How often to retry connecting to the server after losing a connection
-	ServerAddress string        // Address of API server
+	ServerAddress string // Address of API server
+
+	// MinRetry and MaxRetry bound how long Run waits before trying to connect
+	// to the server again. The wait starts at MinRetry and doubles after every
+	// failed attempt, up to MaxRetry, with some randomness added so a lot of
+	// bots don't all try at once.
+	MinRetry time.Duration
+	MaxRetry time.Duration
+
+	// RetryInterval is the old name for MaxRetry, which is used if MaxRetry
+	// isn't set.
+	RetryInterval time.Duration
+
+	// MaxAttempts is how many times in a row Run tries to connect before giving
+	// up with an *UnreachableError, or 0 to keep trying until it's stopped.
+	MaxAttempts int
+
+	// OnStateChange, if it's set, is called whenever the connection to the
+	// server changes state, with the error that caused it, if any. It's called
+	// from the goroutine Run was called from, and Run waits for it to return.
+	OnStateChange func(ConnState, error)
+
+	// OnGameEnded, if it's set, is called whenever one of the bot's games ends,
+	// after the game's AI has been told, for factories that keep track of the
+	// games they've made AIs for. No turns are taken while it runs.
+	OnGameEnded func(*Result)
+
+	// Concurrent is whether the bot takes turns in different games at the same
+	// time. Otherwise, it takes one turn at a time, whatever game it's for, so
+	// the games it's in wait on each other. When it's set, the AIs for
+	// different games are used from different goroutines at the same time, so a factory that returns the same AI for every game, like
+	// ToFactory's, has to return one that's safe for concurrent use, and
+	// OnGameEnded has to be too.
+	Concurrent bool
+
+	// SharedFactory, if it's set, makes the AI for each game instead of the
+	// factory passed to Run, and is told whether the AIs it makes may be shared
+	// between goroutines.
+	SharedFactory SharedFactory
+
+	// dialContext opens the connection to ServerAddress, or dials it over TCP
+	// if it isn't set. Tests use it to connect to a fake server.
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
 }
 
 var defaultConfig = &ServerConfig{
-	RetryInterval: 10 * time.Second,
 	ServerAddress: "gobotgame.com:8001",
+	MinRetry:      time.Second,
+	MaxRetry:      time.Minute,
+	// About a quarter of an hour, once the waits reach a minute.
+	MaxAttempts: 20,
+}
+
+// ConnState is the state of a bot's connection to the server.
+type ConnState int
+
+const (
+	// Connecting means Run is trying to connect to the server.
+	Connecting ConnState = iota
+	// Connected means the bot is connected, and the server can start matches
+	// with it.
+	Connected
+	// Disconnected means the bot isn't connected, because connecting failed or
+	// the connection dropped, and Run is waiting to try again.
+	Disconnected
+	// Stopped means Run is done, and about to return.
+	Stopped
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Disconnected:
+		return "disconnected"
+	case Stopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+var (
+	// ErrAuthRejected is returned when the server doesn't recognize the access
+	// token.
+	ErrAuthRejected = errors.New("game: the server rejected the access token")
+	// ErrNameTaken is returned when one of the owner's bots with the same name
+	// is already connected to the server.
+	ErrNameTaken = errors.New("game: a bot with that name is already connected")
+)
+
+// UnreachableError is returned by Run when it couldn't connect to the server
+// after ServerConfig.MaxAttempts tries.
+type UnreachableError struct {
+	Addr     string
+	Attempts int
+	// Err is why the last attempt failed.
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("game: couldn't reach the server at %s after %d attempts: %v", e.Addr, e.Attempts, e.Err)
 }
 
 // Client represents a connection to the game server.
@@ -32,7 +129,15 @@ type Client struct {
 
 // Dial connects to a server at the given TCP address.
 func Dial(addr string) (*Client, error) {
-	c, err := net.Dial("tcp", addr)
+	return dial(context.TODO(), addr, nil)
+}
+
+func dial(ctx context.Context, addr string, dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) (*Client, error) {
+	if dialContext == nil {
+		var d net.Dialer
+		dialContext = d.DialContext
+	}
+	c, err := dialContext(ctx, "tcp", addr)
 	if err != nil {
 		return nil, err
 	}
@@ -49,13 +154,15 @@ func (c *Client) Close() error {
 }
 
 // RegisterAI adds an AI implementation for the token given by the website.
-// The AI factory function will be called for each new game encountered.
+// The AI factory function will be called for each new game encountered. If the
+// server turns the bot away, the error is ErrAuthRejected or ErrNameTaken.
 func (c *Client) RegisterAI(name, token string, factory Factory) error {
-	a := botapi.Ai_ServerToClient(&aiAdapter{
-		factory: factory,
-		games:   make(map[string]gameState),
-	})
-	_, err := c.connector.Connect(context.TODO(), func(r botapi.ConnectRequest) error {
+	return c.register(context.TODO(), name, token, newAIAdapter(factory.shared(), false, nil))
+}
+
+func (c *Client) register(ctx context.Context, name, token string, adapter *aiAdapter) error {
+	a := botapi.Ai_ServerToClient(adapter)
+	res, err := c.connector.Connect(ctx, func(r botapi.ConnectRequest) error {
 		creds, err := r.NewCredentials()
 		if err != nil {
 			return err
@@ -69,24 +176,152 @@ func (c *Client) RegisterAI(name, token string, factory Factory) error {
 			return err
 		}
 		r.SetAi(a)
+		r.SetConcurrent(adapter.concurrent)
 		return nil
 	}).Struct()
-	return err
+	if err != nil {
+		return err
+	}
+	switch res.Status() {
+	case botapi.ConnectStatus_badToken:
+		return ErrAuthRejected
+	case botapi.ConnectStatus_nameTaken:
+		return ErrNameTaken
+	}
+	return nil
 }
 
-// connect wraps the Dial and RegisterAI functionality
-func connect(name, token string, factory Factory, config *ServerConfig) (*Client, error) {
+// Run connects to the server with the given robot name and user token, and
+// registers the robot provided by the factory function. It stays connected,
+// reconnecting whenever the connection drops, until ctx is done, and then
+// returns ctx.Err().
+//
+// It returns early with ErrAuthRejected if the server doesn't recognize the
+// token, with ErrNameTaken if a bot with the same name is already connected
+// when it first connects, and with an *UnreachableError if it can't connect
+// after config.MaxAttempts tries. A nil config connects to GobotGame.com, and
+// gives up after 20 tries in a row.
+func Run(ctx context.Context, name, token string, factory Factory, config *ServerConfig) error {
 	if config == nil {
 		config = defaultConfig
 	}
-	c, err := Dial(config.ServerAddress)
+	notify := func(s ConnState, err error) {
+		if config.OnStateChange != nil {
+			config.OnStateChange(s, err)
+		}
+	}
+	defer notify(Stopped, nil)
+
+	wait := config.backoff()
+	attempts, connected := 0, false
+	for {
+		notify(Connecting, nil)
+		c, err := connect(ctx, name, token, factory, config)
+		switch {
+		case err == nil:
+			attempts = 0
+			wait = config.backoff()
+			connected = true
+			notify(Connected, nil)
+			if err := c.wait(ctx); err != nil {
+				return err
+			}
+			notify(Disconnected, errors.New("game: lost the connection to the server"))
+		case err == ErrAuthRejected:
+			return err
+		case err == ErrNameTaken && !connected:
+			return err
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			// Couldn't reach the server. After a dropped connection, the server
+			// might also still think the bot's connected for a bit.
+			attempts++
+			if config.MaxAttempts > 0 && attempts >= config.MaxAttempts {
+				return &UnreachableError{Addr: config.ServerAddress, Attempts: attempts, Err: err}
+			}
+			notify(Disconnected, err)
+		}
+
+		select {
+		case <-time.After(wait.next()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// wait waits for the connection to drop, or for ctx to be done, in which case
+// it closes the connection and returns ctx.Err().
+func (c *Client) wait(ctx context.Context) error {
+	dropped := make(chan struct{})
+	go func() {
+		c.conn.Wait()
+		close(dropped)
+	}()
+	select {
+	case <-dropped:
+		return nil
+	case <-ctx.Done():
+		c.Close()
+		return ctx.Err()
+	}
+}
+
+// backoff returns the waits between attempts to connect for the config.
+func (config *ServerConfig) backoff() *backoff {
+	b := &backoff{min: config.MinRetry, max: config.MaxRetry}
+	if b.max == 0 {
+		b.max = config.RetryInterval
+	}
+	if b.max == 0 {
+		b.max = defaultConfig.MaxRetry
+	}
+	switch {
+	case b.min == 0:
+		b.min = b.max / 10
+	case b.min > b.max:
+		b.min = b.max
+	}
+	b.wait = b.min
+	return b
+}
+
+// backoff is an exponential backoff with jitter.
+type backoff struct {
+	min, max time.Duration
+	// wait is the wait before the next attempt, before jitter.
+	wait time.Duration
+}
+
+// next returns how long to wait before the next attempt, which is somewhere
+// between half and all of a wait that doubles every time, up to max.
+func (b *backoff) next() time.Duration {
+	d := b.wait
+	if b.wait *= 2; b.wait > b.max {
+		b.wait = b.max
+	}
+	if d < 2 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
+// connect wraps the Dial and RegisterAI functionality
+func connect(ctx context.Context, name, token string, factory Factory, config *ServerConfig) (*Client, error) {
+	c, err := dial(ctx, config.ServerAddress, config.dialContext)
 	if err != nil {
-		return c, fmt.Errorf("Failed to connect to server: %v", err)
+		return nil, err
 	}
-	if err = c.RegisterAI(name, token, factory); err != nil {
-		return c, fmt.Errorf("Failed to register bot: %v", err)
+	newAI := factory.shared()
+	if config.SharedFactory != nil {
+		newAI = config.SharedFactory
 	}
-	return c, err
+	if err = c.register(ctx, name, token, newAIAdapter(newAI, config.Concurrent, config.OnGameEnded)); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
 }
 
 // StartServerForFactory connects to the server with the given robot name and
@@ -95,58 +330,40 @@ func StartServerForFactory(name, token string, factory Factory) {
 	Connect(name, token, factory, defaultConfig)
 }
 
+// Connect is like Run, but it stays connected until the program gets SIGINT or
+// SIGQUIT, reports what's going on on stderr, and exits the program if
+// something goes wrong. Use Run to keep the bot in charge of its own
+// lifecycle.
 func Connect(name, token string, factory Factory, config *ServerConfig) {
-	c, err := connect(name, token, factory, config)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(exitFail)
+	if config == nil {
+		config = defaultConfig
 	}
-	fmt.Fprintf(os.Stderr, "Connected bot %s. Ctrl-C or send SIGINT to disconnect.\n", name)
-	retryChan := make(chan time.Time)
-	cWait := func() {
-		c.conn.Wait()
-		retryChan <- time.Now()
+	cfg := *config
+	cfg.OnStateChange = func(s ConnState, err error) {
+		switch s {
+		case Connected:
+			fmt.Fprintf(os.Stderr, "Connected bot %s. Ctrl-C or send SIGINT to disconnect.\n", name)
+		case Disconnected:
+			fmt.Fprintf(os.Stderr, "Lost connection to server (%v), trying to reconnect...\n", err)
+		}
+		if config.OnStateChange != nil {
+			config.OnStateChange(s, err)
+		}
 	}
-	go cWait()
+
+	ctx, cancel := context.WithCancel(context.Background())
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGQUIT)
+	defer signal.Stop(sig)
+	go func() {
+		// Wait for the user to mercilessly silence their bot.
+		<-sig
+		cancel()
+	}()
 
-	// Wait for either our connection with the server to terminate, or the user
-	// to mercilessly silence their bot.
-loop:
-	for {
-		select {
-		case <-retryChan:
-			fmt.Fprintln(os.Stderr, "Lost connection to server, trying to reconnect...")
-			c, err = connect(name, token, factory, config)
-			if err == nil {
-				fmt.Fprintf(os.Stderr, "Reconnected bot %s successfully!\n", name)
-				// Wait until we lose the connection again
-				go cWait()
-			} else {
-				if strings.Contains(err.Error(), "connection refused") {
-					// If we can't connect, just wait ten (or --retry_interval) seconds and try again
-					go func() {
-						retryChan <- <-time.After(config.RetryInterval)
-					}()
-				} else {
-					// Fail on all other errors, like the server saying you have an invalid token
-					fmt.Fprintln(os.Stderr, err)
-					os.Exit(exitFail)
-				}
-			}
-		case <-sig:
-			signal.Stop(sig)
-			break loop
-		}
+	if err := Run(ctx, name, token, factory, &cfg); err != context.Canceled {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitFail)
 	}
-
-	signal.Stop(sig)
 	fmt.Fprintln(os.Stderr, "Interrupted. Quitting...")
-	if c != nil {
-		if err := c.Close(); err != nil {
-			fmt.Fprintln(os.Stderr, "Error closing our connection:", err)
-			os.Exit(exitFail)
-		}
-	}
 }
@@ -0,0 +1,221 @@
+package game
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcspragu/Gobots/botapi"
+	"golang.org/x/net/context"
+	"zombiezen.com/go/capnproto2/rpc"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		desc   string
+		config ServerConfig
+		want   []time.Duration
+	}{
+		{
+			"doubles up to max",
+			ServerConfig{MinRetry: time.Second, MaxRetry: 5 * time.Second},
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			"RetryInterval is max",
+			ServerConfig{MinRetry: time.Second, RetryInterval: 2 * time.Second},
+			[]time.Duration{time.Second, 2 * time.Second, 2 * time.Second},
+		},
+		{
+			"min defaults to a tenth of max",
+			ServerConfig{MaxRetry: 10 * time.Second},
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second},
+		},
+		{
+			"min over max",
+			ServerConfig{MinRetry: time.Minute, MaxRetry: time.Second},
+			[]time.Duration{time.Second, time.Second},
+		},
+		{
+			"nothing set",
+			ServerConfig{},
+			[]time.Duration{defaultConfig.MaxRetry / 10, defaultConfig.MaxRetry / 5},
+		},
+	}
+	for _, test := range tests {
+		b := test.config.backoff()
+		for i, want := range test.want {
+			// Jitter takes off up to half the wait.
+			if got := b.next(); got < want/2 || got >= want {
+				t.Errorf("%s: wait %d = %v; want between %v and %v", test.desc, i, got, want/2, want)
+			}
+		}
+	}
+}
+
+// fakeConnector is a server that answers every bot that connects with status.
+type fakeConnector struct {
+	status botapi.ConnectStatus
+}
+
+func (f fakeConnector) Connect(call botapi.AiConnector_connect) error {
+	call.Results.SetStatus(f.status)
+	return nil
+}
+
+// fakeServer fakes dialing the server for Run. Each dial does what the next
+// entry in dials says: fail with err, or connect to a server that answers
+// with status.
+type fakeServer struct {
+	t     *testing.T
+	dials []fakeDial
+
+	mu    sync.Mutex
+	conns []*rpc.Conn
+}
+
+type fakeDial struct {
+	err    error
+	status botapi.ConnectStatus
+}
+
+func (f *fakeServer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.conns)
+	if n >= len(f.dials) {
+		f.t.Fatalf("dialed %d times; want at most %d", n+1, len(f.dials))
+	}
+	d := f.dials[n]
+	f.conns = append(f.conns, nil)
+	if d.err != nil {
+		return nil, d.err
+	}
+	client, srv := net.Pipe()
+	connector := botapi.AiConnector_ServerToClient(fakeConnector{status: d.status})
+	f.conns[n] = rpc.NewConn(rpc.StreamTransport(srv), rpc.MainInterface(connector.Client))
+	return client, nil
+}
+
+// drop closes the connection made by the last dial, from the server's end.
+func (f *fakeServer) drop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.conns[len(f.conns)-1].Close()
+}
+
+func (f *fakeServer) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.conns {
+		if c != nil {
+			c.Close()
+		}
+	}
+}
+
+// runFake runs a bot against srv until it returns, calling onState with each
+// state change, and returns Run's error and the states it went through.
+func runFake(ctx context.Context, srv *fakeServer, maxAttempts int, onState func(ConnState)) ([]ConnState, error) {
+	defer srv.close()
+
+	var states []ConnState
+	config := &ServerConfig{
+		ServerAddress: "fake",
+		MinRetry:      time.Millisecond,
+		MaxRetry:      time.Millisecond,
+		MaxAttempts:   maxAttempts,
+		dialContext:   srv.dial,
+		OnStateChange: func(s ConnState, err error) {
+			states = append(states, s)
+			if onState != nil {
+				onState(s)
+			}
+		},
+	}
+	err := Run(ctx, "bot", "token", func(string) AI { return nil }, config)
+	return states, err
+}
+
+func TestRunRejected(t *testing.T) {
+	tests := []struct {
+		status botapi.ConnectStatus
+		want   error
+	}{
+		{botapi.ConnectStatus_badToken, ErrAuthRejected},
+		{botapi.ConnectStatus_nameTaken, ErrNameTaken},
+	}
+	for _, test := range tests {
+		srv := &fakeServer{t: t, dials: []fakeDial{{status: test.status}}}
+		states, err := runFake(context.Background(), srv, 0, nil)
+		if err != test.want {
+			t.Errorf("%s: Run = %v; want %v", test.status, err, test.want)
+		}
+		if want := []ConnState{Connecting, Stopped}; !reflect.DeepEqual(states, want) {
+			t.Errorf("%s: states = %v; want %v", test.status, states, want)
+		}
+	}
+}
+
+func TestRunUnreachable(t *testing.T) {
+	refused := errors.New("connection refused")
+	srv := &fakeServer{t: t, dials: []fakeDial{{err: refused}, {err: refused}, {err: refused}}}
+	states, err := runFake(context.Background(), srv, 3, nil)
+
+	ue, ok := err.(*UnreachableError)
+	if !ok {
+		t.Fatalf("Run = %v; want an *UnreachableError", err)
+	}
+	if ue.Addr != "fake" || ue.Attempts != 3 || ue.Err != refused {
+		t.Errorf("Run = %+v; want 3 attempts at fake, failing with %v", ue, refused)
+	}
+	want := []ConnState{Connecting, Disconnected, Connecting, Disconnected, Connecting, Stopped}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("states = %v; want %v", states, want)
+	}
+}
+
+func TestRunReconnects(t *testing.T) {
+	refused := errors.New("connection refused")
+	srv := &fakeServer{t: t, dials: []fakeDial{
+		{err: refused},
+		{status: botapi.ConnectStatus_ok},
+		// After a dropped connection, the server might still think the bot's
+		// connected for a bit.
+		{status: botapi.ConnectStatus_nameTaken},
+		{status: botapi.ConnectStatus_ok},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	connects := 0
+	states, err := runFake(ctx, srv, 2, func(s ConnState) {
+		if s != Connected {
+			return
+		}
+		if connects++; connects == 1 {
+			srv.drop()
+		} else {
+			cancel()
+		}
+	})
+
+	if err != context.Canceled {
+		t.Errorf("Run = %v; want %v", err, context.Canceled)
+	}
+	// Failing to connect after having connected counts towards MaxAttempts
+	// afresh.
+	want := []ConnState{
+		Connecting, Disconnected,
+		Connecting, Connected, Disconnected,
+		Connecting, Disconnected,
+		Connecting, Connected,
+		Stopped,
+	}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("states = %v; want %v", states, want)
+	}
+}
@@ -0,0 +1,398 @@
+package game
+
+import (
+	"github.com/bcspragu/Gobots/botapi"
+	"github.com/bcspragu/Gobots/engine"
+)
+
+// Outcome is the result of simulating a round with Simulate.
+type Outcome struct {
+	// Board is the board at the end of the round, or nil for Sim.Step.
+	Board *Board
+	// Died holds the IDs of the robots that were destroyed during the round.
+	Died []uint32
+	// Damage is how much health each robot that got hurt lost.
+	Damage map[uint32]int
+}
+
+// Clone returns a copy of the board that can be changed without affecting b.
+// The location types are shared, since they never change during a game.
+func (b *Board) Clone() *Board {
+	c := *b
+	n := 0
+	for _, col := range b.Cells {
+		for _, r := range col {
+			if r != nil {
+				n++
+			}
+		}
+	}
+	// All the robots are copied into one slice, so it's never reallocated
+	robots := make([]Robot, 0, n)
+	cells := make([]*Robot, b.Size.X*b.Size.Y)
+	c.Cells = make([][]*Robot, b.Size.X)
+	for x := range c.Cells {
+		c.Cells[x] = cells[x*b.Size.Y : (x+1)*b.Size.Y]
+		for y, r := range b.Cells[x] {
+			if r != nil {
+				robots = append(robots, *r)
+				c.Cells[x][y] = &robots[len(robots)-1]
+			}
+		}
+	}
+	return &c
+}
+
+// Simulate plays out a round on a copy of b, using the same rules as the
+// server, with the actions in mine for your robots and the actions in theirs for
+// everyone else's, allies included. Both maps are keyed by robot ID, robots
+// without an action wait, and so do robots given actions the rules don't allow,
+// like on the server. Actions for robots on the wrong side are ignored. b isn't
+// changed.
+//
+// Where new robots spawn is random, so no robots spawn in a simulated round, but
+// robots standing in the spawn zone when it's time to spawn are still dealt
+// with as Rules.SpawnCamping says.
+//
+// Simulate converts the whole board every time it's called, so bots that play
+// out lots of rounds should use a Sim instead.
+func Simulate(b *Board, mine, theirs map[uint32]Action) *Outcome {
+	s := NewSim(b)
+	out := s.Step(mine, theirs)
+	out.Board = s.Board()
+	return out
+}
+
+// A Sim is a board kept the way the server keeps it, which rounds can be
+// played out on over and over without converting the board each time, for bots
+// that search through lots of moves. Rounds play out like they do in Simulate.
+type Sim struct {
+	eb *engine.Board
+	// orig is the board the Sim started from, which has everything that
+	// doesn't change during a game.
+	orig *Board
+}
+
+// NewSim returns a Sim that starts from b. b isn't changed.
+func NewSim(b *Board) *Sim {
+	return &Sim{eb: toEngine(b), orig: b}
+}
+
+// Clone returns a copy of s that can be stepped without affecting s.
+func (s *Sim) Clone() *Sim {
+	return &Sim{eb: s.eb.Clone(), orig: s.orig}
+}
+
+// Round returns the last round played out on s.
+func (s *Sim) Round() int {
+	return s.eb.Round
+}
+
+// Board returns the board as it is now.
+func (s *Sim) Board() *Board {
+	return fromEngine(s.eb, s.orig)
+}
+
+// Step plays out a round on s with the actions in mine for your robots and the
+// actions in theirs for everyone else's, and returns what happened. The
+// Outcome's Board is nil, see Board.
+func (s *Sim) Step(mine, theirs map[uint32]Action) *Outcome {
+	me := s.orig.me()
+	turns := make([][]engine.Turn, s.eb.Factions)
+	for _, r := range s.eb.Locs {
+		actions := theirs
+		if r.Faction == me {
+			actions = mine
+		}
+		id := uint32(r.ID)
+		turns[r.Faction-1] = append(turns[r.Faction-1], actions[id].toEngine(id))
+	}
+	// The same checks the server does, which turn actions the rules don't
+	// allow into waits.
+	for i := range turns {
+		turns[i], _ = s.eb.CheckTurns(i+1, turns[i])
+	}
+
+	out := &Outcome{Damage: make(map[uint32]int)}
+	for _, e := range s.eb.Apply(turns...) {
+		switch e.Type {
+		case engine.Died:
+			out.Died = append(out.Died, uint32(e.Robot))
+		case engine.Damaged:
+			out.Damage[uint32(e.Robot)] += e.Damage
+		}
+	}
+	return out
+}
+
+var (
+	locToEngine = map[LocType]engine.CellType{
+		Invalid: engine.Invalid,
+		Valid:   engine.Valid,
+		Spawn:   engine.Spawn,
+
+		Hazard:  engine.HazardCell,
+		Cover:   engine.CoverCell,
+		Slow:    engine.SlowCell,
+		Healing: engine.HealingCell,
+	}
+
+	penaltyToEngine = map[Penalty]engine.Penalty{
+		NoPenalty:     engine.NoPenalty,
+		CountPenalty:  engine.CountPenalty,
+		DamagePenalty: engine.DamagePenalty,
+	}
+
+	tiebreakToEngine = map[Tiebreak]engine.Tiebreak{
+		NoTiebreak:  engine.NoTiebreak,
+		TotalHealth: engine.TotalHealth,
+		DamageDealt: engine.DamageDealt,
+	}
+
+	campingToEngine = map[Camping]engine.Camping{
+		KillCampers:   engine.KillCampers,
+		SkipOccupied:  engine.SkipOccupied,
+		DamageCampers: engine.DamageCampers,
+	}
+
+	metricToEngine = map[Metric]engine.Metric{
+		Manhattan: engine.Manhattan,
+		Chebyshev: engine.Chebyshev,
+	}
+
+	penaltyFromEngine = map[engine.Penalty]Penalty{
+		engine.NoPenalty:     NoPenalty,
+		engine.CountPenalty:  CountPenalty,
+		engine.DamagePenalty: DamagePenalty,
+	}
+
+	tiebreakFromEngine = map[engine.Tiebreak]Tiebreak{
+		engine.NoTiebreak:  NoTiebreak,
+		engine.TotalHealth: TotalHealth,
+		engine.DamageDealt: DamageDealt,
+	}
+
+	campingFromEngine = map[engine.Camping]Camping{
+		engine.KillCampers:   KillCampers,
+		engine.SkipOccupied:  SkipOccupied,
+		engine.DamageCampers: DamageCampers,
+	}
+
+	metricFromEngine = map[engine.Metric]Metric{
+		engine.Manhattan: Manhattan,
+		engine.Chebyshev: Chebyshev,
+	}
+
+	symmetryToEngine = map[Symmetry]engine.Symmetry{
+		Horizontal: engine.Horizontal,
+		Vertical:   engine.Vertical,
+		Rotational: engine.Rotational,
+		Diagonal:   engine.Diagonal,
+		Quartered:  engine.Quartered,
+	}
+)
+
+// toEngine converts a board to the engine's representation, where each robot's
+// faction is the player that owns it.
+func toEngine(b *Board) *engine.Board {
+	var schedule engine.Schedule
+	if len(b.SpawnRounds) > 0 {
+		schedule = engine.SpawnRounds(b.SpawnRounds)
+	}
+	rules := rulesToEngine(b.Rules)
+	eb := engine.EmptyBoard(engine.BoardConfig{
+		Size:     engine.Loc{X: b.Size.X, Y: b.Size.Y},
+		Rules:    &rules,
+		Symmetry: symmetryToEngine[b.Symmetry],
+		Factions: b.Players,
+		Teams:    b.Teams,
+		Schedule: schedule,
+	})
+	eb.Round = b.Round
+	for x := range b.LType {
+		for y, t := range b.LType[x] {
+			eb.Cells[x][y] = locToEngine[t]
+		}
+	}
+	for _, col := range b.Cells {
+		for _, r := range col {
+			if r == nil {
+				continue
+			}
+			eb.Set(engine.Loc{X: r.Loc.X, Y: r.Loc.Y}, &engine.Robot{
+				ID:      engine.RobotID(r.ID),
+				Health:  r.Health,
+				Faction: b.owner(r),
+
+				Entering: enteringToEngine(r.Entering),
+			})
+			if id := engine.RobotID(r.ID); id > eb.NextID {
+				eb.NextID = id
+			}
+		}
+	}
+	return eb
+}
+
+// rulesToEngine converts a rule set to the engine's representation.
+func rulesToEngine(rs RuleSet) engine.RuleSet {
+	return engine.RuleSet{
+		InitialHealth:   rs.InitialHealth,
+		CollisionDamage: rs.CollisionDamage,
+		AttackDamage:    rs.AttackDamage,
+		DestructDamage:  rs.DestructDamage,
+		GuardMultiplier: rs.GuardMultiplier,
+		SpawnEvery:      rs.SpawnEvery,
+		SpawnUntil:      rs.SpawnUntil,
+		MaxRounds:       rs.MaxRounds,
+		FriendlyFire:    rs.FriendlyFire,
+		IllegalPenalty:  penaltyToEngine[rs.IllegalPenalty],
+		IllegalDamage:   rs.IllegalDamage,
+
+		EndOnElimination: rs.EndOnElimination,
+		Tiebreaks: [2]engine.Tiebreak{
+			tiebreakToEngine[rs.Tiebreaks[0]],
+			tiebreakToEngine[rs.Tiebreaks[1]],
+		},
+		VisionRadius: rs.VisionRadius,
+		VisionMetric: metricToEngine[rs.VisionMetric],
+
+		HazardDamage:    rs.HazardDamage,
+		CoverMultiplier: rs.CoverMultiplier,
+		HealAmount:      rs.HealAmount,
+		AllyHeal:        rs.AllyHeal,
+		RangedDamage:    rs.RangedDamage,
+
+		SpawnCamping:  campingToEngine[rs.SpawnCamping],
+		CampingDamage: rs.CampingDamage,
+	}
+}
+
+// rulesFromEngine converts a rule set from the engine's representation.
+func rulesFromEngine(rs engine.RuleSet) RuleSet {
+	return RuleSet{
+		InitialHealth:   rs.InitialHealth,
+		CollisionDamage: rs.CollisionDamage,
+		AttackDamage:    rs.AttackDamage,
+		DestructDamage:  rs.DestructDamage,
+		GuardMultiplier: rs.GuardMultiplier,
+		SpawnEvery:      rs.SpawnEvery,
+		SpawnUntil:      rs.SpawnUntil,
+		MaxRounds:       rs.MaxRounds,
+		FriendlyFire:    rs.FriendlyFire,
+		IllegalPenalty:  penaltyFromEngine[rs.IllegalPenalty],
+		IllegalDamage:   rs.IllegalDamage,
+
+		EndOnElimination: rs.EndOnElimination,
+		Tiebreaks: [2]Tiebreak{
+			tiebreakFromEngine[rs.Tiebreaks[0]],
+			tiebreakFromEngine[rs.Tiebreaks[1]],
+		},
+		VisionRadius: rs.VisionRadius,
+		VisionMetric: metricFromEngine[rs.VisionMetric],
+
+		HazardDamage:    rs.HazardDamage,
+		CoverMultiplier: rs.CoverMultiplier,
+		HealAmount:      rs.HealAmount,
+		AllyHeal:        rs.AllyHeal,
+		RangedDamage:    rs.RangedDamage,
+
+		SpawnCamping:  campingFromEngine[rs.SpawnCamping],
+		CampingDamage: rs.CampingDamage,
+	}
+}
+
+// fromEngine converts a board from the engine's representation, taking
+// everything that doesn't change from orig.
+func fromEngine(eb *engine.Board, orig *Board) *Board {
+	b := *orig
+	cells := make([]*Robot, b.Size.X*b.Size.Y)
+	b.Cells = make([][]*Robot, b.Size.X)
+	for x := range b.Cells {
+		b.Cells[x] = cells[x*b.Size.Y : (x+1)*b.Size.Y]
+	}
+	b.Round = eb.Round
+	me := b.me()
+	for loc, r := range eb.Locs {
+		faction := OpponentFaction
+		switch {
+		case r.Faction == me:
+			faction = MyFaction
+		case eb.Team(r.Faction) == eb.Team(me):
+			faction = AllyFaction
+		}
+		b.Cells[loc.X][loc.Y] = &Robot{
+			ID:      uint32(r.ID),
+			Loc:     Loc{X: loc.X, Y: loc.Y},
+			Faction: faction,
+			Health:  r.Health,
+			Player:  r.Faction,
+
+			Entering: enteringFromEngine(r.Entering),
+		}
+	}
+	return &b
+}
+
+func enteringToEngine(l *Loc) *engine.Loc {
+	if l == nil {
+		return nil
+	}
+	return &engine.Loc{X: l.X, Y: l.Y}
+}
+
+func enteringFromEngine(l *engine.Loc) *Loc {
+	if l == nil {
+		return nil
+	}
+	return &Loc{X: l.X, Y: l.Y}
+}
+
+// me returns the player you are, which is the first one on boards that don't
+// say.
+func (b *Board) me() int {
+	if b.Player == 0 {
+		return engine.P1Faction
+	}
+	return b.Player
+}
+
+// owner returns the player that owns r, working it out from its faction on
+// boards that don't say.
+func (b *Board) owner(r *Robot) int {
+	switch {
+	case r.Player != 0:
+		return r.Player
+	case r.Faction == MyFaction:
+		return b.me()
+	case b.me() == engine.P1Faction:
+		return engine.P2Faction
+	}
+	return engine.P1Faction
+}
+
+// toEngine converts the action for robot id to the engine's representation.
+func (a Action) toEngine(id uint32) engine.Turn {
+	t := engine.Turn{ID: engine.RobotID(id)}
+	switch a.Kind {
+	case Wait:
+		t.Kind = botapi.Turn_Which_wait
+	case Move:
+		t.Kind = botapi.Turn_Which_move
+		t.Direction = a.Direction.toWire()
+	case Attack:
+		t.Kind = botapi.Turn_Which_attack
+		t.Direction = a.Direction.toWire()
+	case SelfDestruct:
+		t.Kind = botapi.Turn_Which_selfDestruct
+	case Guard:
+		t.Kind = botapi.Turn_Which_guard
+	case Heal:
+		t.Kind = botapi.Turn_Which_heal
+		t.Direction = a.Direction.toWire()
+	case RangedAttack:
+		t.Kind = botapi.Turn_Which_rangedAttack
+		t.Direction = a.Direction.toWire()
+	}
+	return t
+}
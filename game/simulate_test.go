@@ -0,0 +1,220 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bcspragu/Gobots/botapi"
+	"github.com/bcspragu/Gobots/engine"
+	"zombiezen.com/go/capnproto2"
+)
+
+// testBoard returns a 5x5 open board seen by player 1, with robots 1 and 3
+// belonging to player 1 and robots 2 and 4 to player 2:
+//
+//	. . . . .
+//	. . . . .
+//	. 1 2 4 .
+//	. 3 . . .
+//	. . . . .
+func testBoard(rs RuleSet) *Board {
+	b := &Board{
+		Round:    1,
+		Size:     Loc{5, 5},
+		Rules:    rs,
+		Symmetry: Horizontal,
+		Players:  2,
+		Player:   1,
+		Cells:    make([][]*Robot, 5),
+		LType:    make([][]LocType, 5),
+	}
+	for x := range b.Cells {
+		b.Cells[x] = make([]*Robot, 5)
+		b.LType[x] = make([]LocType, 5)
+		for y := range b.LType[x] {
+			b.LType[x][y] = Valid
+		}
+	}
+	for _, r := range []*Robot{
+		{ID: 1, Loc: Loc{1, 2}, Faction: MyFaction, Health: 40, Player: 1},
+		{ID: 2, Loc: Loc{2, 2}, Faction: OpponentFaction, Health: 50, Player: 2},
+		{ID: 3, Loc: Loc{1, 3}, Faction: MyFaction, Health: 50, Player: 1},
+		{ID: 4, Loc: Loc{3, 2}, Faction: OpponentFaction, Health: 50, Player: 2},
+	} {
+		b.Cells[r.Loc.X][r.Loc.Y] = r
+	}
+	return b
+}
+
+func testRules() RuleSet {
+	return RuleSet{
+		InitialHealth:   50,
+		CollisionDamage: 5,
+		AttackDamage:    10,
+		DestructDamage:  15,
+		GuardMultiplier: 0.5,
+		SpawnEvery:      10,
+		SpawnUntil:      100,
+		MaxRounds:       100,
+		FriendlyFire:    true,
+		IllegalPenalty:  CountPenalty,
+		IllegalDamage:   5,
+	}
+}
+
+// health returns the health of every robot on b, keyed by ID.
+func health(b *Board) map[uint32]int {
+	hs := make(map[uint32]int)
+	for _, col := range b.Cells {
+		for _, r := range col {
+			if r != nil {
+				hs[r.ID] = r.Health
+			}
+		}
+	}
+	return hs
+}
+
+func TestSimulate(t *testing.T) {
+	b := testBoard(testRules())
+	before := b.Clone()
+
+	out := Simulate(b,
+		map[uint32]Action{1: {Kind: Attack, Direction: East}, 3: {Kind: Move, Direction: West}},
+		// Robot 1 isn't theirs, so it shouldn't move.
+		map[uint32]Action{1: {Kind: Move, Direction: North}, 4: {Kind: Attack, Direction: West}},
+	)
+
+	if !reflect.DeepEqual(b, before) {
+		t.Error("Simulate changed the board it was given")
+	}
+	if want := map[uint32]int{2: 20}; !reflect.DeepEqual(out.Damage, want) {
+		t.Errorf("Damage = %v; want %v", out.Damage, want)
+	}
+	if len(out.Died) != 0 {
+		t.Errorf("Died = %v; want nobody", out.Died)
+	}
+	if out.Board.Round != b.Round+1 {
+		t.Errorf("Round = %d; want %d", out.Board.Round, b.Round+1)
+	}
+	if r := out.Board.Cells[1][2]; r == nil || r.ID != 1 || r.Faction != MyFaction {
+		t.Errorf("robot at (1, 2) = %+v; want robot 1, still mine", r)
+	}
+	if r := out.Board.Cells[0][3]; r == nil || r.ID != 3 {
+		t.Errorf("robot at (0, 3) = %+v; want robot 3", r)
+	}
+	if r := out.Board.Cells[3][2]; r == nil || r.ID != 4 || r.Faction != OpponentFaction || r.Player != 2 {
+		t.Errorf("robot at (3, 2) = %+v; want robot 4, player 2's", r)
+	}
+}
+
+func TestSimulateDisallowed(t *testing.T) {
+	heal, ranged := testRules(), testRules()
+	heal.AllyHeal = 5
+	ranged.RangedDamage = 10
+	for _, rs := range []*RuleSet{&heal, &ranged} {
+		rs.IllegalPenalty = DamagePenalty
+	}
+
+	mine := map[uint32]Action{
+		1: {Kind: RangedAttack, Direction: East},
+		3: {Kind: Heal, Direction: North},
+	}
+	// A ranged attack off the board is punished when it's allowed, but the
+	// robot just waits when it isn't, like it would on the server.
+	offBoard := map[uint32]Action{3: {Kind: RangedAttack, Direction: West}}
+
+	tests := []struct {
+		desc    string
+		rs      RuleSet
+		actions map[uint32]Action
+		want    map[uint32]int
+	}{
+		{"heal only", heal, mine, map[uint32]int{1: 45, 2: 50, 3: 50, 4: 50}},
+		{"ranged only", ranged, mine, map[uint32]int{1: 40, 2: 50, 3: 50, 4: 40}},
+		{"heal only, off the board", heal, offBoard, map[uint32]int{1: 40, 2: 50, 3: 50, 4: 50}},
+		{"ranged only, off the board", ranged, offBoard, map[uint32]int{1: 40, 2: 50, 3: 45, 4: 50}},
+	}
+	for _, test := range tests {
+		out := Simulate(testBoard(test.rs), test.actions, nil)
+		if got := health(out.Board); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: health = %v; want %v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestSim(t *testing.T) {
+	b := testBoard(testRules())
+	s := NewSim(b)
+	attack := map[uint32]Action{1: {Kind: Attack, Direction: East}}
+
+	c := s.Clone()
+	for i := 0; i < 2; i++ {
+		out := c.Step(attack, nil)
+		if out.Board != nil {
+			t.Errorf("step %d: Board = %v; want nil", i, out.Board)
+		}
+		if want := map[uint32]int{2: 10}; !reflect.DeepEqual(out.Damage, want) {
+			t.Errorf("step %d: Damage = %v; want %v", i, out.Damage, want)
+		}
+	}
+	if c.Round() != b.Round+2 {
+		t.Errorf("clone's Round = %d; want %d", c.Round(), b.Round+2)
+	}
+	if got := health(c.Board())[2]; got != 30 {
+		t.Errorf("clone's robot 2 has %d health; want 30", got)
+	}
+
+	// Stepping the clone shouldn't have changed the original.
+	if s.Round() != b.Round {
+		t.Errorf("original's Round = %d; want %d", s.Round(), b.Round)
+	}
+	if got, want := health(s.Board()), health(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("original's health = %v; want %v", got, want)
+	}
+
+	// Stepping the Sim should match Simulate.
+	out := s.Step(attack, nil)
+	if want := Simulate(b, attack, nil); !reflect.DeepEqual(out.Damage, want.Damage) || !reflect.DeepEqual(s.Board(), want.Board) {
+		t.Errorf("Step and Simulate disagree: got %v and %v, Simulate gave %v and %v", out.Damage, s.Board(), want.Damage, want.Board)
+	}
+}
+
+func TestRules(t *testing.T) {
+	rs := testRules()
+	rs.EndOnElimination = true
+	rs.Tiebreaks = [2]Tiebreak{DamageDealt, TotalHealth}
+	rs.VisionRadius, rs.VisionMetric = 4, Chebyshev
+	rs.HazardDamage, rs.CoverMultiplier, rs.HealAmount = 3, 0.5, 2
+	rs.AllyHeal, rs.RangedDamage = 4, 6
+	rs.SpawnCamping, rs.CampingDamage = DamageCampers, 7
+
+	if got := rulesFromEngine(rulesToEngine(rs)); got != rs {
+		t.Errorf("rulesFromEngine(rulesToEngine(%+v)) = %+v", rs, got)
+	}
+
+	_, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		t.Fatal("capnp.NewMessage:", err)
+	}
+	ib, err := botapi.NewRootInitialBoard(seg)
+	if err != nil {
+		t.Fatal("botapi.NewRootInitialBoard:", err)
+	}
+	wire, err := ib.Rules()
+	if err != nil {
+		t.Fatal("Rules:", err)
+	}
+	// Rules that weren't sent are read like the engine reads them.
+	if got, want := convertRules(wire), rulesFromEngine(engine.RulesFromWire(wire)); got != want || got.InitialHealth == 0 {
+		t.Errorf("convertRules(missing) = %+v; want %+v", got, want)
+	}
+	wire, err = ib.NewRules()
+	if err != nil {
+		t.Fatal("NewRules:", err)
+	}
+	rulesToEngine(rs).ToWire(wire)
+	if got := convertRules(wire); got != rs {
+		t.Errorf("convertRules = %+v; want %+v", got, rs)
+	}
+}
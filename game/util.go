@@ -35,7 +35,8 @@ func (b *Board) Find(f func(*Robot) bool) *Robot {
 }
 
 // LocType returns the type of cell at location loc. It is either Invalid,
-// Valid, or Spawn
+// Valid, Spawn, or one of the kinds of terrain. Locations off the board are
+// Invalid.
 func (b *Board) LocType(loc Loc) LocType {
 	if loc.X >= 0 && loc.Y >= 0 && loc.X < b.Size.X && loc.Y < b.Size.Y {
 		return b.LType[loc.X][loc.Y]
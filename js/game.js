@@ -3,23 +3,41 @@ angular.module('gobotApp', [])
   var game = this;
   var playback = Gobot.GetPlayback(replayStr);
   game.round = 0;
+  // The faction whose view of the board is shown, or 0 for the whole board.
+  game.view = "0";
 
-  game.updateBoard = function(board) {
+  game.drawBoard = function(board) {
     game.board = board;
     game.rows = new Array(board.Height())
     for (var y = 0; y < board.Height(); y++) {
       game.rows[y] = new Array(board.Width())
       for (var x = 0; x < board.Width(); x++) {
-        game.rows[y][x] = board.AtXY(x,y)
+        var cell = board.ViewAtXY(parseInt(game.view, 10), x, y);
+        cell.Effect = playback.EffectAt(game.round, x, y);
+        game.rows[y][x] = cell;
       }
     }
+  }
+
+  game.updateBoard = function(board) {
+    game.drawBoard(board);
     $scope.$apply();
   }
 
+  // showView redraws the board when a different view is picked, which Angular
+  // is already applying.
+  game.showView = function() {
+    if (game.board) {
+      game.drawBoard(game.board);
+    }
+  }
+
   //game.updateBoard(playback.Board(0));
   var id = window.setInterval(function() {
     game.round++;
     var board = playback.Board(game.round);
+    game.log = playback.Log(game.round);
+    game.timing = playback.Timing(game.round);
     game.updateBoard(board);
     if (game.round >= playback.NumBoards()) {
       window.clearInterval(id)
@@ -6,9 +6,14 @@ import (
 	"encoding/gob"
 	"errors"
 	"flag"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
 
 	gocontext "golang.org/x/net/context"
 
@@ -22,12 +27,57 @@ var (
 	dbPath    = flag.String("db_path", "gobots.db", "Location of DB file")
 	hashPath  = flag.String("hash_path", "hashKey", "Location of hash key file")
 	blockPath = flag.String("block_path", "blockKey", "Location of block key file")
+	mapDir    = flag.String("map_dir", "maps", "Directory of map files to play on")
+	density   = flag.Float64("map_density", 0.15, "Fraction of generated maps to fill with walls")
+	vision    = flag.Int("vision_radius", 0, "How far bots can see from their robots, or 0 for the whole board")
+	metric    = flag.String("vision_metric", "manhattan", "How vision distance is measured, manhattan or chebyshev")
+	allyHeal  = flag.Int("ally_heal", 0, "How much a heal action restores, or 0 to not allow healing")
+	ranged    = flag.Int("ranged_damage", 0, "How much a ranged attack does, which has to be less than a regular attack, or 0 to not allow them")
+	spawnAt   = flag.String("spawn_rounds", "", "Comma-separated rounds to spawn robots after, instead of every 10 rounds")
+	camping   = flag.String("spawn_camping", "kill", "What happens to robots on spawn cells when robots spawn: kill, skip, or damage")
+	campDmg   = flag.Int("camping_damage", 10, "How much robots on spawn cells get hurt under -spawn_camping=damage")
+	turnTime  = flag.Duration("turn_timeout", 30*time.Second, "How long a bot gets to send each turn")
+	timeBank  = flag.Duration("time_bank", 0, "How long a bot gets for all of its turns in a match, or 0 for no limit")
+	strikes   = flag.Int("max_strikes", 3, "How many turns a bot can run out of time on before it forfeits, or 0 to never forfeit")
+	grace     = flag.Duration("disconnect_grace", 30*time.Second, "How long a bot that disconnects mid-match has to come back before it forfeits")
 
 	templates = tmpl{template.Must(template.ParseGlob("templates/*.html"))}
 
 	db               datastore
 	s                *securecookie.SecureCookie
 	globalAIEndpoint *aiEndpoint
+	maps             map[string]*engine.Map
+
+	// rules are the rules every match is played with.
+	rules = engine.DefaultRules
+
+	// schedule is when robots spawn in every match, or nil to go by the rules.
+	schedule engine.Schedule
+
+	// timing is how long bots get to send their turns in every match.
+	timing timeControl
+
+	// generators make a fresh map from the seed of each match played on them.
+	generators = map[string]*engine.Generator{
+		"random": {
+			Name:     "random",
+			Size:     engine.Loc{X: 17, Y: 17},
+			Symmetry: engine.Horizontal,
+			Spawns:   8,
+		},
+		"random-rotational": {
+			Name:     "random-rotational",
+			Size:     engine.Loc{X: 17, Y: 17},
+			Symmetry: engine.Rotational,
+			Spawns:   8,
+		},
+		"random-quartered": {
+			Name:     "random-quartered",
+			Size:     engine.Loc{X: 17, Y: 17},
+			Symmetry: engine.Quartered,
+			Spawns:   5,
+		},
+	}
 )
 
 func main() {
@@ -42,6 +92,40 @@ func main() {
 		log.Fatal("Can't encrypt the cookies! WHATEVER WILL WE DO")
 	}
 
+	if maps, err = engine.LoadMaps(*mapDir); os.IsNotExist(err) {
+		log.Printf("No map directory at %s, only the default map is available", *mapDir)
+	} else if err != nil {
+		log.Fatal("Couldn't load the maps: ", err)
+	}
+	for name, g := range generators {
+		if _, ok := maps[name]; ok {
+			log.Fatalf("The map %q has the same name as a generated map", name)
+		}
+		g.Density = *density
+		if err := g.Check(); err != nil {
+			log.Fatal("Bad map generator: ", err)
+		}
+	}
+	if rules.VisionMetric, err = engine.ParseMetric(*metric); err != nil {
+		log.Fatal("Bad vision metric: ", err)
+	}
+	rules.VisionRadius = *vision
+	rules.AllyHeal = *allyHeal
+	rules.RangedDamage = *ranged
+	if rules.SpawnCamping, err = engine.ParseCamping(*camping); err != nil {
+		log.Fatal("Bad spawn camping: ", err)
+	}
+	rules.CampingDamage = *campDmg
+	if err := rules.Check(); err != nil {
+		log.Fatal("Bad rules: ", err)
+	}
+	timing = timeControl{Turn: *turnTime, Bank: *timeBank, Strikes: *strikes, Grace: *grace}
+	if *spawnAt != "" {
+		if schedule, err = engine.ParseSpawnRounds(*spawnAt); err != nil {
+			log.Fatal("Bad spawn rounds: ", err)
+		}
+	}
+
 	http.HandleFunc("/", baseWrapper(serveIndex))
 	http.HandleFunc("/createUser", baseWrapper(createUserHandler))
 	http.HandleFunc("/login", baseWrapper(loginHandler))
@@ -70,6 +154,9 @@ func serveIndex(c context) error {
 	data := tmplData{
 		Data: map[string]interface{}{
 			"Bots": globalAIEndpoint.listOnlineAIs(),
+			"Maps": mapNames(),
+			// The players past the first two, who are optional
+			"ExtraPlayers": []int{3, 4},
 		},
 		Scripts: []template.URL{
 			"/js/main.js",
@@ -79,6 +166,20 @@ func serveIndex(c context) error {
 	return templates.ExecuteTemplate(c, "index.html", data)
 }
 
+// mapNames returns the names of all the maps, including the generated ones, in
+// order.
+func mapNames() []string {
+	names := make([]string, 0, len(maps)+len(generators))
+	for name := range maps {
+		names = append(names, name)
+	}
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func serveGame(c context) error {
 	replay, err := db.lookupGame(c.gameID())
 	if err != nil {
@@ -120,8 +221,10 @@ func serveGame(c context) error {
 			"GameID":   c.gameID(),
 			"Exists":   true,
 			"Playback": dat.String(),
-			"P1Name":   gInfo.AI1.Name,
-			"P2Name":   gInfo.AI2.Name,
+			"Info":     gInfo,
+			// Whether the players could only see part of the board, in which
+			// case the viewer can show what each of them saw.
+			"Fog": len(p.Boards) > 0 && p.Boards[0].Rules.VisionRadius > 0,
 		},
 	}
 	return templates.ExecuteTemplate(c, "game.html", data)
@@ -133,27 +236,77 @@ func serveError(w http.ResponseWriter, err error) {
 }
 
 func startMatch(c context) error {
-	ai1, _ := db.lookupAI(aiID(c.r.FormValue("ai1")))
-	ai2, _ := db.lookupAI(aiID(c.r.FormValue("ai2")))
-
-	online := globalAIEndpoint.listOnlineAIs()
-	var o1, o2 *onlineAI
-	for _, v := range online {
-		// Because we can't rely on the address of v
-		vv := v
-		if v.Info.ID == ai1.ID {
-			o1 = &vv
+	// The first two bots have to be picked, the rest are optional.
+	online := make(map[aiID]onlineAI)
+	for _, v := range globalAIEndpoint.listOnlineAIs() {
+		online[v.Info.ID] = v
+	}
+	var ais []*onlineAI
+	for i := 1; i <= maxPlayers; i++ {
+		id := aiID(c.r.FormValue("ai" + strconv.Itoa(i)))
+		if id == "" && i > 2 {
+			continue
+		}
+		// Because we can't rely on the address of the map value
+		o, ok := online[id]
+		if !ok {
+			return fmt.Errorf("bot %q isn't online", id)
+		}
+		ais = append(ais, &o)
+	}
+
+	// A seed can be passed in to rematch two bots on the same board, otherwise
+	// pick a fresh one.
+	bc := engine.DefaultConfig
+	bc.Rules = &rules
+	bc.Schedule = schedule
+	if seed := c.r.FormValue("seed"); seed != "" {
+		s, err := strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			return err
+		}
+		bc.Seed = s
+	} else {
+		bc.Seed = genSeed()
+	}
+	name := c.r.FormValue("map")
+	if name != "" {
+		if m, ok := maps[name]; ok {
+			bc.Size = m.Size
+			bc.CellTyper = m
+			bc.Symmetry = m.Symmetry
+		} else if g, ok := generators[name]; ok {
+			bc.Size = g.Size
+			bc.CellTyper = g
+			bc.Symmetry = g.Symmetry
+		} else {
+			return fmt.Errorf("there's no map named %q", name)
+		}
+	}
+	if players := bc.Symmetry.Players(); len(ais) > players {
+		if name == "" {
+			name = "default"
+		}
+		return fmt.Errorf("the %s map only has room for %d players", name, players)
+	}
+	// Teams are made of bots next to each other in order, so a 2v2 game is the
+	// first two bots against the last two.
+	if teams := c.r.FormValue("teams"); teams != "" {
+		n, err := strconv.Atoi(teams)
+		if err != nil {
+			return err
 		}
-		if v.Info.ID == ai2.ID {
-			o2 = &vv
+		if n < 2 || n >= len(ais) || len(ais)%n != 0 {
+			return fmt.Errorf("%d players can't be split into %d even teams", len(ais), n)
 		}
+		bc.Teams = n
 	}
 
 	gidCh := make(chan gameID)
 	matchDone := make(chan struct{})
 	go func() {
 		// TODO: Have the user choose the config
-		err := runMatch(gidCh, gocontext.TODO(), db, o1, o2, engine.DefaultConfig)
+		err := runMatch(gidCh, gocontext.TODO(), db, ais, bc, timing)
 		close(gidCh)
 		if err != nil {
 			log.Println("runMatch:", err)
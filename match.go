@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net"
 	"sync"
@@ -19,7 +20,18 @@ type aiEndpoint struct {
 
 	// fields below are protected by mu
 	mu     sync.Mutex
-	online map[aiID]botapi.Ai
+	online map[aiID]connection
+
+	// connected is closed and replaced whenever an AI connects, to wake up
+	// matches waiting for a disconnected AI to come back.
+	connected chan struct{}
+}
+
+// connection is an AI's connection to the server.
+type connection struct {
+	client botapi.Ai
+	// gone is closed when the connection drops.
+	gone <-chan struct{}
 }
 
 const (
@@ -32,8 +44,9 @@ func startAIEndpoint(addr string, ds datastore) (*aiEndpoint, error) {
 		return nil, err
 	}
 	e := &aiEndpoint{
-		ds:     ds,
-		online: make(map[aiID]botapi.Ai),
+		ds:        ds,
+		online:    make(map[aiID]connection),
+		connected: make(chan struct{}),
 	}
 	go e.listen(l)
 	return e, nil
@@ -53,10 +66,11 @@ func (e *aiEndpoint) listen(l net.Listener) {
 
 // handleConn runs in its own goroutine, started by listen.
 func (e *aiEndpoint) handleConn(c net.Conn) {
-	aic := &aiConnector{e: e}
+	aic := &aiConnector{e: e, gone: make(chan struct{})}
 	rc := rpc.NewConn(rpc.StreamTransport(c), rpc.MainInterface(botapi.AiConnector_ServerToClient(aic).Client))
 	rc.Wait()
 	aic.drop()
+	close(aic.gone)
 }
 
 // listOnlineAIs lists the active AIs connected to the server right now.  The AIs can be passed over to startMatch.
@@ -64,22 +78,23 @@ func (e *aiEndpoint) listOnlineAIs() []onlineAI {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	online := make([]onlineAI, 0, len(e.online))
-	for id, client := range e.online {
+	for id, conn := range e.online {
 		info, err := e.ds.lookupAI(id)
 		if err != nil {
 			log.Printf("Failed to lookup AI %s: %v", id, err)
 			continue
 		}
 		online = append(online, onlineAI{
-			Info:   *info,
-			client: client,
+			Info:       *info,
+			connection: conn,
+			e:          e,
 		})
 	}
 	return online
 }
 
-// connect adds an online AI
-func (e *aiEndpoint) connect(name, token string, ai botapi.Ai) (aiID, error) {
+// connect adds an online AI, whose connection closes gone when it drops.
+func (e *aiEndpoint) connect(name, token string, conn connection) (aiID, error) {
 	infos, err := e.ds.listAIsForUser(accessToken(token))
 	if err != nil {
 		return "", err
@@ -103,9 +118,11 @@ func (e *aiEndpoint) connect(name, token string, ai botapi.Ai) (aiID, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	if _, exists := e.online[id]; exists {
-		return "", errors.New("That bot is already connected. Choose a new name.")
+		return "", errNameTaken
 	} else {
-		e.online[id] = ai
+		e.online[id] = conn
+		close(e.connected)
+		e.connected = make(chan struct{})
 	}
 
 	return id, nil
@@ -120,17 +137,33 @@ func (e *aiEndpoint) removeAIs(ids []aiID) {
 	}
 }
 
+// errNameTaken is returned by connect when the owner already has a bot with the
+// same name connected.
+var errNameTaken = errors.New("gobots: a bot with that name is already connected")
+
 type aiConnector struct {
-	e   *aiEndpoint
-	ais []aiID
+	e    *aiEndpoint
+	ais  []aiID
+	gone chan struct{}
 }
 
 func (aic *aiConnector) Connect(call botapi.AiConnector_connect) error {
 	creds, _ := call.Params.Credentials()
 	tok, _ := creds.SecretToken()
 	name, _ := creds.BotName()
-	id, err := aic.e.connect(name, tok, call.Params.Ai())
-	if err != nil {
+	id, err := aic.e.connect(name, tok, connection{
+		client: call.Params.Ai(),
+		gone:   aic.gone,
+	})
+	switch err {
+	case nil:
+	case errUserNotFound:
+		call.Results.SetStatus(botapi.ConnectStatus_badToken)
+		return nil
+	case errNameTaken:
+		call.Results.SetStatus(botapi.ConnectStatus_nameTaken)
+		return nil
+	default:
 		return err
 	}
 	aic.ais = append(aic.ais, id)
@@ -141,115 +174,438 @@ func (aic *aiConnector) drop() {
 	aic.e.removeAIs(aic.ais)
 }
 
-func runMatch(gidCh chan<- gameID, ctx gocontext.Context, ds datastore, aiA, aiB *onlineAI, bc engine.BoardConfig) error {
+// maxPlayers is the most AIs that can play in one game, which is how many
+// quartered maps have room for.
+const maxPlayers = 4
+
+// timeControl is how long AIs get to send their turns.
+type timeControl struct {
+	// Turn is the most time an AI gets for one turn.
+	Turn time.Duration
+	// Bank is the total time an AI gets for all of its turns in a match, like a
+	// chess clock, or 0 for no limit. Each turn's time comes out of it.
+	Bank time.Duration
+	// Strikes is how many turns an AI can run out of time on before it forfeits
+	// the match, or 0 to never forfeit.
+	Strikes int
+	// Grace is how long an AI that disconnects has to connect again before it
+	// forfeits the match.
+	Grace time.Duration
+}
+
+// clock tracks an AI's time during a match.
+type clock struct {
+	left    time.Duration
+	strikes int
+}
+
+// deadline returns how long the AI gets for its next turn.
+func (c *clock) deadline(tc timeControl) time.Duration {
+	if tc.Bank > 0 && c.left < tc.Turn {
+		return c.left
+	}
+	return tc.Turn
+}
+
+// seat is how an AI is doing during a match.
+type seat struct {
+	ai       *onlineAI
+	clock    clock
+	warnings turnWarnings
+	// forfeit is why the AI forfeited, or completed if it hasn't.
+	forfeit resultReason
+	// crashes is how many times the AI's code has crashed in the match.
+	crashes int
+}
+
+// runMatch plays a game between the given AIs, where the i-th AI plays as
+// faction i+1, and records it. The board config's Factions is set to the number
+// of AIs. A game that can't be played out, because ctx is done or something
+// went wrong, is recorded as aborted.
+func runMatch(gidCh chan<- gameID, ctx gocontext.Context, ds datastore, ais []*onlineAI, bc engine.BoardConfig, tc timeControl) error {
 	sTime := time.Now()
 	// Create new board and store it.
+	bc.Factions = len(ais)
 	b := engine.EmptyBoard(bc)
 	b.InitBoard(bc)
 	_, seg, _ := capnp.NewMessage(capnp.SingleSegment(nil))
 	wb, _ := botapi.NewRootInitialBoard(seg)
-	b.ToWireWithInitial(wb, engine.P1Faction)
-	gid, err := ds.startGame(aiA.Info.ID, aiB.Info.ID, wb)
+	b.ToWireWithInitial(wb, engine.Spectator)
+	ids := make([]aiID, len(ais))
+	for i, ai := range ais {
+		ids[i] = ai.Info.ID
+	}
+	gid, err := ds.startGame(ids, wb, bc.Seed)
 	if err != nil {
 		return err
 	}
 	gidCh <- gid
 
 	// Run the game
+	seats := make([]*seat, len(ais))
+	for i, ai := range ais {
+		seats[i] = &seat{ai: ai, clock: clock{left: tc.Bank}}
+	}
+	notifyAll(ctx, seats, tc.Turn, func(ctx gocontext.Context, ai *onlineAI, f int) error {
+		return ai.gameStarted(ctx, gid, b, f)
+	})
+	var matchErr error
 	for !b.IsFinished() {
-		turnCtx, _ := gocontext.WithTimeout(ctx, 30*time.Second)
-		chA, chB := make(chan turnResult), make(chan turnResult)
-		go aiA.takeTurn(turnCtx, gid, b, engine.P1Faction, chA)
-		go aiB.takeTurn(turnCtx, gid, b, engine.P2Faction, chB)
-		ra, rb := <-chA, <-chB
-		if ra.err.HasError() {
-			log.Printf("Errors from AI ID %s: %v", aiA.Info.ID, ra.err)
+		if matchErr = ctx.Err(); matchErr != nil {
+			break
 		}
-		if rb.err.HasError() {
-			log.Printf("Errors from AI ID %s: %v", aiB.Info.ID, rb.err)
+		if matchErr = playRound(ctx, ds, gid, b, seats, tc); matchErr != nil {
+			break
 		}
-		b.Update(ra.results, rb.results)
-		_, s, err := capnp.NewMessage(capnp.SingleSegment(nil))
-		if err != nil {
-			return err
+	}
+
+	// Every bot hears how the game ended, even ones that forfeited or when the
+	// game was aborted, so they can clean up after it.
+	notifyAll(gocontext.Background(), seats, tc.Turn, func(ctx gocontext.Context, ai *onlineAI, f int) error {
+		return ai.gameEnded(ctx, gid, b, f)
+	})
+
+	res := b.Result()
+	gInfo := &gameInfo{
+		ID:        gid,
+		StartTime: sTime,
+		EndTime:   time.Now(),
+		Seed:      bc.Seed,
+		Map:       mapName(bc),
+		Teams:     bc.Teams,
+		End:       res.Reason,
+		Winner:    res.Winner,
+		Tiebreak:  res.Tiebreak,
+	}
+	for i, st := range seats {
+		f := i + 1
+		gInfo.Players = append(gInfo.Players, &playerInfo{
+			AI:       &st.ai.Info,
+			Faction:  f,
+			Team:     b.Team(f),
+			Score:    res.Robots[f],
+			Warnings: st.warnings,
+			Illegal:  b.Illegal[f],
+			Health:   res.Health[f],
+			Damage:   res.Damage[f],
+
+			Timeouts: st.clock.strikes,
+			Forfeit:  st.forfeit,
+			Crashes:  st.crashes,
+		})
+		if res.Reason == engine.Forfeit && st.forfeit != completed {
+			gInfo.Result = st.forfeit
 		}
-		r, err := botapi.NewRootReplay_Round(s)
-		if err != nil {
-			return err
+	}
+	if matchErr != nil {
+		gInfo.Result = aborted
+	}
+	if err := ds.finishGame(gid, gInfo); err != nil {
+		return err
+	}
+	return matchErr
+}
+
+// playRound gets the turns from every AI in the match that hasn't forfeited,
+// plays them out, and records the round.
+func playRound(ctx gocontext.Context, ds datastore, gid gameID, b *engine.Board, seats []*seat, tc timeControl) error {
+	chs := make([]chan turnResult, len(seats))
+	for i, st := range seats {
+		if b.Forfeited[i+1] {
+			continue
 		}
+		chs[i] = make(chan turnResult)
+		turnCtx, cancel := gocontext.WithTimeout(ctx, st.clock.deadline(tc))
+		go func(ai *onlineAI, f int, ch chan<- turnResult) {
+			ai.takeTurn(turnCtx, gid, b, f, ch)
+			cancel()
+		}(st.ai, i+1, chs[i])
+	}
+	turns := make([]botapi.Turn_List, len(seats))
+	latencies := make([]time.Duration, len(seats))
+	timedOut := make([]bool, len(seats))
+	// Forfeits wait until every turn is in, since the other bots' turns are
+	// still being sent out from b until then.
+	var forfeited []int
+	var nturns int
+	for i, ch := range chs {
+		if ch == nil {
+			continue
+		}
+		st, f := seats[i], i+1
+		res := <-ch
+		latencies[i] = res.latency
+		st.clock.left -= res.latency
 
-		wireBoard, err := r.NewEndBoard()
-		if err != nil {
-			return err
+		// A bot that disconnects gets a chance to come back while everyone
+		// waits, and forfeits if it doesn't. A bot that runs out of time gets a
+		// strike. Either way, all of its robots wait this round.
+		var missed bool
+		switch {
+		case st.ai.disconnected():
+			missed = true
+			res.results = botapi.Turn_List{}
+			st.warnings.problem(b.Round, "disconnected, so every robot waits")
+			if !st.ai.reconnect(ctx, tc.Grace) {
+				st.forfeit = forfeitDisconnect
+				forfeited = append(forfeited, f)
+			}
+		case res.timedOut:
+			missed, timedOut[i] = true, true
+			res.results = botapi.Turn_List{}
+			st.clock.strikes++
+			st.warnings.problem(b.Round, fmt.Sprintf("ran out of time after %v, so every robot waits", res.latency.Round(time.Millisecond)))
+			if tc.Strikes > 0 && st.clock.strikes >= tc.Strikes {
+				st.forfeit = forfeitTimeout
+				forfeited = append(forfeited, f)
+			}
+		case res.err.HasError():
+			log.Printf("Errors from AI ID %s: %v", st.ai.Info.ID, res.err)
+		}
+		// Crashes are counted even in rounds the bot missed, so they aren't
+		// blamed on the next round it plays.
+		if n := res.crashes - st.crashes; n > 0 {
+			st.crashes = res.crashes
+			if !missed {
+				st.warnings.problem(b.Round, "the bot crashed "+times(n)+", so those robots wait")
+			}
 		}
-		b.ToWire(wireBoard, engine.P1Faction)
 
-		ral, rbl := ra.results.Len(), rb.results.Len()
-		turns, err := botapi.NewTurn_List(r.Segment(), int32(ral+rbl))
+		// Don't let the AIs move robots that aren't theirs
+		ts, vs, err := b.ValidateTurns(f, res.results)
 		if err != nil {
 			return err
 		}
-		for i := 0; i < ral; i++ {
-			t := ra.results.At(i)
-			if err := turns.Set(i, t); err != nil {
-				return err
-			}
+		if !missed {
+			st.warnings.add(vs)
 		}
-		for i := 0; i < rbl; i++ {
-			t := rb.results.At(i)
-			if err := turns.Set(i+ral, t); err != nil {
+		turns[i] = ts
+		nturns += ts.Len()
+	}
+
+	// The robots of a bot that forfeits are gone, so it has no turns to play.
+	var events []engine.Event
+	for _, f := range forfeited {
+		events = append(events, b.Forfeit(f)...)
+		nturns -= turns[f-1].Len()
+		turns[f-1] = botapi.Turn_List{}
+	}
+	events = append(events, b.Update(turns...)...)
+	_, s, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	if err != nil {
+		return err
+	}
+	r, err := botapi.NewRootReplay_Round(s)
+	if err != nil {
+		return err
+	}
+
+	wireBoard, err := r.NewEndBoard()
+	if err != nil {
+		return err
+	}
+	b.ToWire(wireBoard, engine.Spectator)
+
+	moves, err := botapi.NewTurn_List(r.Segment(), int32(nturns))
+	if err != nil {
+		return err
+	}
+	var n int
+	for _, ts := range turns {
+		for i := 0; i < ts.Len(); i++ {
+			if err := moves.Set(n, ts.At(i)); err != nil {
 				return err
 			}
+			n++
 		}
-		r.SetMoves(turns)
-		db.addRound(gid, r)
 	}
+	r.SetMoves(moves)
 
-	gInfo := &gameInfo{
-		ID:        gid,
-		AI1:       &aiA.Info,
-		AI2:       &aiB.Info,
-		AI1Score:  b.BotCount(1),
-		AI2Score:  b.BotCount(2),
-		StartTime: sTime,
-		EndTime:   time.Now(),
+	wireEvents, err := botapi.NewEvent_List(r.Segment(), int32(len(events)))
+	if err != nil {
+		return err
+	}
+	for i, e := range events {
+		e.ToWire(wireEvents.At(i))
+	}
+	r.SetEvents(wireEvents)
+
+	wireLats, err := r.NewLatencies(int32(len(seats)))
+	if err != nil {
+		return err
+	}
+	wireOuts, err := r.NewTimedOut(int32(len(seats)))
+	if err != nil {
+		return err
+	}
+	for i := range seats {
+		wireLats.Set(i, uint32(latencies[i]/time.Millisecond))
+		wireOuts.Set(i, timedOut[i])
+	}
+	return ds.addRound(gid, r)
+}
+
+// times returns how many times something happened, in words.
+func times(n int) string {
+	if n == 1 {
+		return "once"
 	}
-	return db.finishGame(gid, &aiA.Info, &aiB.Info, gInfo)
+	return fmt.Sprintf("%d times", n)
+}
+
+// mapName returns the name of the map a board is set up with, or the empty
+// string if it isn't one of the named maps.
+func mapName(bc engine.BoardConfig) string {
+	switch t := bc.CellTyper.(type) {
+	case *engine.Map:
+		return t.Name
+	case *engine.Generator:
+		return t.Name
+	}
+	return ""
 }
 
 type onlineAI struct {
-	Info   aiInfo
-	client botapi.Ai
+	Info aiInfo
+	connection
+	e *aiEndpoint
+}
+
+// disconnected reports whether the AI's connection has dropped.
+func (oa *onlineAI) disconnected() bool {
+	select {
+	case <-oa.gone:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnect waits up to grace for a disconnected AI to connect again, and
+// switches over to the new connection if it does. It reports whether it did.
+func (oa *onlineAI) reconnect(ctx gocontext.Context, grace time.Duration) bool {
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	for {
+		oa.e.mu.Lock()
+		conn, ok := oa.e.online[oa.Info.ID]
+		connected := oa.e.connected
+		oa.e.mu.Unlock()
+		if ok && conn.gone != oa.gone {
+			oa.connection = conn
+			return true
+		}
+
+		select {
+		case <-connected:
+		case <-timer.C:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
 }
 
 type turnResult struct {
 	results botapi.Turn_List
 	err     turnError
+
+	// latency is how long the AI took to answer, and timedOut is whether it ran
+	// out of time before it did.
+	latency  time.Duration
+	timedOut bool
+
+	// crashes is how many times the AI says it's crashed in the game so far.
+	crashes int
 }
 
 func (oa *onlineAI) takeTurn(ctx gocontext.Context, gid gameID, b *engine.Board, faction int, ch chan<- turnResult) {
+	start := time.Now()
 	results, err := oa.client.TakeTurn(ctx, func(p botapi.Ai_takeTurn_Params) error {
 		iwb, err := p.NewBoard()
 		if err != nil {
 			return err
 		}
-		wb, err := iwb.NewBoard()
-		if err != nil {
-			return err
-		}
-		wb.SetGameId(string(gid))
-		return b.ToWireWithInitial(iwb, faction)
+		return boardToWire(iwb, gid, b, faction)
 	}).Struct()
+	latency := time.Since(start)
 	var te turnError
 	if err != nil {
 		te = append(te, err)
 	}
+	timedOut := err != nil && ctx.Err() == gocontext.DeadlineExceeded
 
 	tl, err := results.Turns()
 	if err != nil {
 		te = append(te, err)
 	}
-	ch <- turnResult{tl, te}
+	ch <- turnResult{tl, te, latency, timedOut, int(results.Crashes())}
+}
+
+// gameStarted tells the AI the game is starting, with the board it starts on.
+func (oa *onlineAI) gameStarted(ctx gocontext.Context, gid gameID, b *engine.Board, faction int) error {
+	_, err := oa.client.GameStarted(ctx, func(p botapi.Ai_gameStarted_Params) error {
+		iwb, err := p.NewBoard()
+		if err != nil {
+			return err
+		}
+		return boardToWire(iwb, gid, b, faction)
+	}).Struct()
+	return err
+}
+
+// gameEnded tells the AI how the game turned out.
+func (oa *onlineAI) gameEnded(ctx gocontext.Context, gid gameID, b *engine.Board, faction int) error {
+	_, err := oa.client.GameEnded(ctx, func(p botapi.Ai_gameEnded_Params) error {
+		res, err := p.NewResult()
+		if err != nil {
+			return err
+		}
+		if err := b.ResultToWire(res, faction); err != nil {
+			return err
+		}
+		iwb, err := res.Board()
+		if err != nil {
+			return err
+		}
+		return setGameID(iwb, gid)
+	}).Struct()
+	return err
+}
+
+// notifyAll sends a notification to every AI in the match at once, and waits
+// up to timeout for them to answer. Bots from before notifications existed
+// fail them, so errors are ignored.
+func notifyAll(ctx gocontext.Context, seats []*seat, timeout time.Duration, notify func(ctx gocontext.Context, ai *onlineAI, faction int) error) {
+	ctx, cancel := gocontext.WithTimeout(ctx, timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, st := range seats {
+		wg.Add(1)
+		go func(ai *onlineAI, f int) {
+			defer wg.Done()
+			notify(ctx, ai, f)
+		}(st.ai, i+1)
+	}
+	wg.Wait()
+}
+
+// boardToWire writes b, as faction sees it, to iwb for game gid.
+func boardToWire(iwb botapi.InitialBoard, gid gameID, b *engine.Board, faction int) error {
+	if err := b.ToWireWithInitial(iwb, faction); err != nil {
+		return err
+	}
+	return setGameID(iwb, gid)
+}
+
+// setGameID sets the game ID on a board that's already been written, since
+// writing the board replaces it.
+func setGameID(iwb botapi.InitialBoard, gid gameID) error {
+	wb, err := iwb.Board()
+	if err != nil {
+		return err
+	}
+	return wb.SetGameId(string(gid))
 }
 
 type turnError []error
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bcspragu/Gobots/botapi"
+	"github.com/bcspragu/Gobots/engine"
+	gocontext "golang.org/x/net/context"
+)
+
+func TestMain(m *testing.M) {
+	// The datastore logs everything it does.
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func TestClockDeadline(t *testing.T) {
+	tests := []struct {
+		tc   timeControl
+		left time.Duration
+		want time.Duration
+	}{
+		{timeControl{Turn: time.Second}, 0, time.Second},
+		{timeControl{Turn: time.Second, Bank: time.Minute}, time.Minute, time.Second},
+		{timeControl{Turn: time.Second, Bank: time.Minute}, time.Second, time.Second},
+		{timeControl{Turn: time.Second, Bank: time.Minute}, 300 * time.Millisecond, 300 * time.Millisecond},
+		{timeControl{Turn: time.Second, Bank: time.Minute}, -time.Millisecond, -time.Millisecond},
+	}
+	for _, test := range tests {
+		c := clock{left: test.left}
+		if got := c.deadline(test.tc); got != test.want {
+			t.Errorf("deadline(%+v) with %v left = %v; want %v", test.tc, test.left, got, test.want)
+		}
+	}
+}
+
+// matchDatastore is a datastore that keeps the one game played with it in
+// memory.
+type matchDatastore struct {
+	datastore
+
+	mu     sync.Mutex
+	rounds int
+	info   *gameInfo
+}
+
+func (ds *matchDatastore) startGame(ais []aiID, init botapi.InitialBoard, seed int64) (gameID, error) {
+	return "1", nil
+}
+
+func (ds *matchDatastore) addRound(id gameID, round botapi.Replay_Round) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.rounds++
+	return nil
+}
+
+func (ds *matchDatastore) finishGame(id gameID, info *gameInfo) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.info = info
+	return nil
+}
+
+// waitingAI is a bot whose robots all wait, or that never answers its turns if
+// it stalls.
+type waitingAI struct {
+	stall bool
+	// crashes is how many times it says it crashed every turn.
+	crashes int
+}
+
+func (w waitingAI) TakeTurn(call botapi.Ai_takeTurn) error {
+	if w.stall {
+		<-call.Ctx.Done()
+		return call.Ctx.Err()
+	}
+	ib, err := call.Params.Board()
+	if err != nil {
+		return err
+	}
+	board, err := ib.Board()
+	if err != nil {
+		return err
+	}
+	robots, err := board.Robots()
+	if err != nil {
+		return err
+	}
+	var mine []uint32
+	for i := 0; i < robots.Len(); i++ {
+		if r := robots.At(i); r.Faction() == botapi.Faction_mine {
+			mine = append(mine, r.Id())
+		}
+	}
+	turns, err := botapi.NewTurn_List(call.Results.Segment(), int32(len(mine)))
+	if err != nil {
+		return err
+	}
+	for i, id := range mine {
+		turns.At(i).SetId(id)
+		turns.At(i).SetWait()
+	}
+	call.Results.SetCrashes(uint32((board.Round() + 1) * int32(w.crashes)))
+	return call.Results.SetTurns(turns)
+}
+
+func (w waitingAI) GameStarted(call botapi.Ai_gameStarted) error {
+	return nil
+}
+
+func (w waitingAI) GameEnded(call botapi.Ai_gameEnded) error {
+	return nil
+}
+
+// newOnlineAI returns an AI with the given ID that plays like ai.
+func newOnlineAI(e *aiEndpoint, id aiID, ai waitingAI) *onlineAI {
+	return &onlineAI{
+		Info: aiInfo{ID: id, Name: "bot " + string(id)},
+		connection: connection{
+			client: botapi.Ai_ServerToClient(ai),
+			gone:   make(chan struct{}),
+		},
+		e: e,
+	}
+}
+
+// testMatch plays a short match between ais on ds, and returns runMatch's
+// error.
+func testMatch(ctx gocontext.Context, ds *matchDatastore, ais []*onlineAI, tc timeControl) error {
+	rules := engine.DefaultRules
+	rules.MaxRounds = 5
+	bc := engine.BoardConfig{
+		Size:      engine.Loc{X: BoardSize, Y: BoardSize},
+		Spawner:   engine.NewRandomSpawn(2),
+		CellTyper: engine.NewCircleSpawn(engine.Loc{X: BoardSize, Y: BoardSize}),
+		Rules:     &rules,
+	}
+	return runMatch(make(chan gameID, 1), ctx, ds, ais, bc, tc)
+}
+
+func TestRunMatchTimeouts(t *testing.T) {
+	tests := []struct {
+		desc string
+		tc   timeControl
+		// stalling is the index of the bot that stalls.
+		stalling int
+		// rounds is how many rounds the stalling bot lasts.
+		rounds int
+	}{
+		{"strikes", timeControl{Turn: 10 * time.Millisecond, Strikes: 2}, 1, 2},
+		{"bank", timeControl{Turn: time.Minute, Bank: 10 * time.Millisecond, Strikes: 1}, 1, 1},
+		// The other bot's turn is still being sent out when the first one
+		// forfeits.
+		{"first bot", timeControl{Turn: 10 * time.Millisecond, Strikes: 1}, 0, 1},
+	}
+	for _, test := range tests {
+		e := &aiEndpoint{}
+		ds := &matchDatastore{}
+		ais := []*onlineAI{
+			newOnlineAI(e, "1", waitingAI{stall: test.stalling == 0}),
+			newOnlineAI(e, "2", waitingAI{stall: test.stalling == 1}),
+		}
+		other := 1 - test.stalling
+		winner := other + 1
+		if err := testMatch(gocontext.Background(), ds, ais, test.tc); err != nil {
+			t.Fatalf("%s: runMatch: %v", test.desc, err)
+		}
+
+		info := ds.info
+		if ds.rounds != test.rounds {
+			t.Errorf("%s: played %d rounds; want %d", test.desc, ds.rounds, test.rounds)
+		}
+		if info.Result != forfeitTimeout || info.End != engine.Forfeit || info.Winner != winner {
+			t.Errorf("%s: game ended with %v, %v, won by %d; want %v, %v, won by %d", test.desc, info.Result, info.End, info.Winner, forfeitTimeout, engine.Forfeit, winner)
+		}
+		if p := info.Players[other]; p.Forfeit != completed || p.Timeouts != 0 {
+			t.Errorf("%s: other bot forfeited with %v after %d timeouts; want %v after none", test.desc, p.Forfeit, p.Timeouts, completed)
+		}
+		if p := info.Players[test.stalling]; p.Forfeit != forfeitTimeout || p.Timeouts != test.rounds || p.Warnings.Count != test.rounds {
+			t.Errorf("%s: stalling bot forfeited with %v after %d timeouts and %d warnings; want %v after %d of each", test.desc, p.Forfeit, p.Timeouts, p.Warnings.Count, forfeitTimeout, test.rounds)
+		}
+	}
+}
+
+func TestRunMatchCrashes(t *testing.T) {
+	tests := []struct {
+		crashes int
+		want    string
+	}{
+		{1, "round 0: the bot crashed once, so those robots wait"},
+		{2, "round 0: the bot crashed 2 times, so those robots wait"},
+	}
+	for _, test := range tests {
+		e := &aiEndpoint{}
+		ds := &matchDatastore{}
+		ais := []*onlineAI{
+			newOnlineAI(e, "1", waitingAI{crashes: test.crashes}),
+			newOnlineAI(e, "2", waitingAI{}),
+		}
+		if err := testMatch(gocontext.Background(), ds, ais, timeControl{Turn: time.Second}); err != nil {
+			t.Fatalf("%d crashes a turn: runMatch: %v", test.crashes, err)
+		}
+
+		p := ds.info.Players[0]
+		if want := 5 * test.crashes; p.Crashes != want || p.Warnings.Count != 5 {
+			t.Errorf("%d crashes a turn: bot crashed %d times with %d warnings; want %d times with 5", test.crashes, p.Crashes, p.Warnings.Count, want)
+		}
+		if len(p.Warnings.Messages) == 0 || p.Warnings.Messages[0] != test.want {
+			t.Errorf("%d crashes a turn: warnings = %q; want the first to be %q", test.crashes, p.Warnings.Messages, test.want)
+		}
+	}
+}
+
+func TestRunMatchDisconnects(t *testing.T) {
+	for _, back := range []bool{false, true} {
+		e := &aiEndpoint{online: make(map[aiID]connection), connected: make(chan struct{})}
+		ds := &matchDatastore{}
+		ais := []*onlineAI{
+			newOnlineAI(e, "1", waitingAI{}),
+			newOnlineAI(e, "2", waitingAI{}),
+		}
+		gone := make(chan struct{})
+		close(gone)
+		ais[1].gone = gone
+		if back {
+			e.online["2"] = connection{client: botapi.Ai_ServerToClient(waitingAI{}), gone: make(chan struct{})}
+		}
+		if err := testMatch(gocontext.Background(), ds, ais, timeControl{Turn: time.Second, Grace: 10 * time.Millisecond}); err != nil {
+			t.Fatalf("came back = %t: runMatch: %v", back, err)
+		}
+
+		info := ds.info
+		wantRounds, wantResult, wantEnd, wantForfeit := 1, forfeitDisconnect, engine.Forfeit, forfeitDisconnect
+		if back {
+			wantRounds, wantResult, wantEnd, wantForfeit = 5, completed, engine.RoundLimit, completed
+		}
+		if ds.rounds != wantRounds {
+			t.Errorf("came back = %t: played %d rounds; want %d", back, ds.rounds, wantRounds)
+		}
+		if info.Result != wantResult || info.End != wantEnd {
+			t.Errorf("came back = %t: game ended with %v, %v; want %v, %v", back, info.Result, info.End, wantResult, wantEnd)
+		}
+		// The round it was gone for is a warning either way.
+		if p := info.Players[1]; p.Forfeit != wantForfeit || p.Warnings.Count != 1 {
+			t.Errorf("came back = %t: disconnected bot forfeited with %v and %d warnings; want %v and 1", back, p.Forfeit, p.Warnings.Count, wantForfeit)
+		}
+	}
+}
+
+func TestRunMatchAborted(t *testing.T) {
+	ds := &matchDatastore{}
+	ais := []*onlineAI{
+		newOnlineAI(&aiEndpoint{}, "1", waitingAI{}),
+		newOnlineAI(&aiEndpoint{}, "2", waitingAI{}),
+	}
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+	if err := testMatch(ctx, ds, ais, timeControl{Turn: time.Second}); err != gocontext.Canceled {
+		t.Errorf("runMatch = %v; want %v", err, gocontext.Canceled)
+	}
+	if ds.rounds != 0 || ds.info.Result != aborted || ds.info.Winner != 0 {
+		t.Errorf("played %d rounds, ended with %v won by %d; want 0 rounds, %v won by nobody", ds.rounds, ds.info.Result, ds.info.Winner, aborted)
+	}
+}
@@ -79,7 +79,7 @@ func nearestOpponent(b *game.Board, loc game.Loc) *game.Robot {
 	var closestDist int
 	for y, row := range b.Cells {
 		for x, r := range row {
-			curr := game.Loc{x, y}
+			curr := game.Loc{X: x, Y: y}
 			if r == nil || r.Faction != game.OpponentFaction {
 				continue
 			}
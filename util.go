@@ -76,6 +76,11 @@ func genName(n int) string {
 	return string(b)
 }
 
+// genSeed picks a random seed for a new match.
+func genSeed() int64 {
+	return rand.New(cryptoRandSource{}).Int63()
+}
+
 func initKeys(hashPath, blockPath string) (*securecookie.SecureCookie, error) {
 	var hashKey []byte
 	var blockKey []byte